@@ -0,0 +1,90 @@
+/*
+Package custody is the exchange's side of the chain: the wallets deposits arrive in and withdrawals are paid out
+of. Backend is what the server needs of one, Fake is an in-process backend for local use and tests.
+
+The server may hand a backend the same transfer again after a restart (it can't tell whether the call before the
+crash went through), so backends recognise transfers by ID and never receive or send one twice.
+*/
+package custody
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+)
+
+var ErrWalletShort = errors.New("custody wallet is short")
+
+type Backend interface {
+	// DepositAddress is where owner sends asset to deposit it.
+	DepositAddress(ctx context.Context, owner, asset string) (string, error)
+	// Receive waits for deposit to arrive and settle, and returns the ID of its transaction.
+	Receive(ctx context.Context, deposit models.Transfer) (string, error)
+	// Send pays withdrawal out to its address and returns the ID of its transaction.
+	Send(ctx context.Context, withdrawal models.Transfer) (string, error)
+}
+
+/*
+Fake settles every deposit as soon as it's asked and pays withdrawals out of what it holds of the asset, which
+starts at funded (what the ledger says is in custody) and grows with every deposit.
+*/
+type Fake struct {
+	mu       sync.Mutex
+	wallets  map[string]models.Decimal
+	received map[uint64]string
+	sent     map[uint64]string
+}
+
+func NewFake(funded map[string]models.Decimal) *Fake {
+	f := &Fake{wallets: make(map[string]models.Decimal), received: make(map[uint64]string), sent: make(map[uint64]string)}
+	for asset, amount := range funded {
+		f.wallets[strings.ToUpper(asset)] = amount
+	}
+	return f
+}
+
+func (f *Fake) DepositAddress(ctx context.Context, owner, asset string) (string, error) {
+	return fmt.Sprintf("fake:%s:%s", strings.ToLower(asset), owner), nil
+}
+
+func (f *Fake) Receive(ctx context.Context, deposit models.Transfer) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if tx, ok := f.received[deposit.ID]; ok {
+		return tx, nil
+	}
+	held, err := f.wallets[deposit.Asset].CheckedAdd(deposit.Amount)
+	if err != nil {
+		return "", fmt.Errorf("receiving %s %s: %w", deposit.Amount, deposit.Asset, err)
+	}
+	tx := fmt.Sprintf("fake-in-%d", deposit.ID)
+	f.wallets[deposit.Asset] = held
+	f.received[deposit.ID] = tx
+	return tx, nil
+}
+
+func (f *Fake) Send(ctx context.Context, withdrawal models.Transfer) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if tx, ok := f.sent[withdrawal.ID]; ok {
+		return tx, nil
+	}
+	if held := f.wallets[withdrawal.Asset]; held.LessThan(withdrawal.Amount) {
+		return "", fmt.Errorf("sending %s %s, %s is held: %w", withdrawal.Amount, withdrawal.Asset, held, ErrWalletShort)
+	}
+	tx := fmt.Sprintf("fake-out-%d", withdrawal.ID)
+	f.wallets[withdrawal.Asset] = f.wallets[withdrawal.Asset].Sub(withdrawal.Amount)
+	f.sent[withdrawal.ID] = tx
+	return tx, nil
+}
+
+// NOTE: Wallet is what the fake holds of asset.
+func (f *Fake) Wallet(asset string) models.Decimal {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.wallets[strings.ToUpper(asset)]
+}
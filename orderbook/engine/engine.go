@@ -0,0 +1,404 @@
+/*
+Package engine runs the matching engine: every market's book is owned by one goroutine that takes commands from
+a bounded channel, so different symbols match in parallel and a busy market only queues its own commands.
+
+A command is journaled by the goroutine of its market right before it's applied, which keeps the journal order
+of each market equal to the order its book saw the commands in. Markets share two things: order IDs and account
+settings (self-trade prevention), which are resolved before journaling and written into the record, and their
+users' funds, which are reserved before journaling and moved when the command is applied (see models.Balances).
+So a sequential replay of the journal rebuilds the same books and balances however the markets interleaved live.
+
+Account commands (deposits and withdrawals) belong to no market, they're journaled and applied under the journal
+lock by the goroutine that submits them, so they apply in journal order live as on replay. A withdrawal's funds
+are reserved first like an order's.
+
+When a market's queue is full, senders block until there is room or their context is done (backpressure).
+A command that made it into the queue always runs and always gets its reply.
+*/
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+)
+
+var (
+	ErrStopped = errors.New("engine is stopped")
+	ErrJournal = errors.New("failed to journal the command")
+)
+
+// NOTE: AppliedFunc is called on a market's goroutine after each command, before its sender gets the reply.
+type AppliedFunc func(m *models.Market, rec journal.Record, result journal.Result, err error)
+
+type command struct {
+	rec   *journal.Record // nil for a func run on the market's goroutine
+	fn    func(*models.Market)
+	reply chan reply // nil when nobody waits
+}
+
+type reply struct {
+	result journal.Result
+	err    error
+}
+
+type worker struct {
+	market   *models.Market
+	commands chan command
+}
+
+type Engine struct {
+	registry *models.MarketRegistry
+	applied  AppliedFunc
+
+	walMu sync.Mutex
+	wal   *journal.Writer
+
+	mu      sync.RWMutex // NOTE: guards closed against sends on the closed channels
+	closed  bool
+	workers map[string]*worker
+	wg      sync.WaitGroup
+
+	snapshotMu sync.Mutex // NOTE: two snapshots pausing the markets in different orders would wait on each other
+}
+
+/*
+New starts a goroutine for every market of registry with a queue of queueSize commands. wal may be nil to run
+without a journal, applied nil when there's nothing to do after a command. The registry's markets must not
+be touched by anything but the engine until Close.
+*/
+func New(registry *models.MarketRegistry, wal *journal.Writer, queueSize int, applied AppliedFunc) *Engine {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	e := &Engine{
+		registry: registry,
+		applied:  applied,
+		wal:      wal,
+		workers:  make(map[string]*worker),
+	}
+	for _, m := range registry.Markets() {
+		w := &worker{market: m, commands: make(chan command, queueSize)}
+		e.workers[m.Symbol] = w
+		e.wg.Add(1)
+		go e.run(w)
+	}
+	return e
+}
+
+func (e *Engine) run(w *worker) {
+	defer e.wg.Done()
+	for cmd := range w.commands {
+		var r reply
+		if cmd.rec != nil {
+			r.result, r.err = e.apply(w.market, cmd.rec)
+		} else {
+			cmd.fn(w.market)
+		}
+		if cmd.reply != nil {
+			cmd.reply <- r
+		}
+	}
+}
+
+// NOTE: apply journals rec and runs it against the book. It only ever runs on the goroutine of m.
+func (e *Engine) apply(m *models.Market, rec *journal.Record) (journal.Result, error) {
+	id, err := e.reserve(rec)
+	if err != nil {
+		return journal.Result{}, err
+	}
+	defer e.registry.Unreserve(id)
+
+	e.walMu.Lock()
+	rec.Time = time.Now().UnixNano()
+	if e.wal != nil {
+		if err := e.wal.Append(rec); err != nil {
+			e.walMu.Unlock()
+			return journal.Result{}, fmt.Errorf("%w: %v", ErrJournal, err)
+		}
+	}
+	e.walMu.Unlock()
+
+	result, err := journal.Apply(e.registry, *rec)
+	if e.wal != nil {
+		e.walMu.Lock()
+		if err := e.wal.AppendOutcome(rec.LSN, result.Trades); err != nil {
+			log.Printf("failed to journal the outcome of lsn %d: %v", rec.LSN, err)
+		}
+		e.walMu.Unlock()
+	}
+	if e.applied != nil {
+		e.applied(m, *rec, result, err)
+	}
+	if result.Order != nil {
+		order := *result.Order // NOTE: the book keeps changing its own copy once the reply is out
+		result.Order = &order
+	}
+	return result, err
+}
+
+/*
+reserve gives a new order its ID and its owner's self-trade prevention mode, both written into the record, and
+reserves the funds of the command, returning the order ID they're reserved for.
+*/
+func (e *Engine) reserve(rec *journal.Record) (uint, error) {
+	switch rec.Kind {
+	case journal.NewOrder:
+		if rec.Order == nil {
+			return 0, nil // NOTE: Apply refuses it
+		}
+		if rec.Order.ID == 0 {
+			rec.Order.ID = e.registry.NewOrderID()
+		}
+		e.registry.PinSelfTradePrevention(rec.Order)
+		return rec.Order.ID, e.registry.ReserveOrder(rec.Order)
+	case journal.AmendOrder:
+		return rec.OrderID, e.registry.ReserveAmend(rec.Symbol, rec.OrderID, rec.Price, rec.Quantity)
+	default:
+		return 0, nil
+	}
+}
+
+// NOTE: applyAccount journals and applies an account command in one go under the journal lock.
+func (e *Engine) applyAccount(rec *journal.Record) (journal.Result, error) {
+	e.walMu.Lock()
+	defer e.walMu.Unlock()
+	if (rec.Kind == journal.Deposit || rec.Kind == journal.Withdraw) && rec.TransferID == 0 {
+		balances := e.registry.Balances()
+		if balances == nil {
+			return journal.Result{}, fmt.Errorf("%s needs balances", rec.Kind)
+		}
+		rec.TransferID = balances.Transfers().NewID()
+	}
+	if rec.Kind == journal.Withdraw {
+		if err := e.registry.ReserveWithdrawal(rec.TransferID, rec.Owner, rec.Asset, rec.Amount); err != nil {
+			return journal.Result{}, err
+		}
+		defer e.registry.UnreserveWithdrawal(rec.TransferID)
+	}
+	rec.Time = time.Now().UnixNano()
+	if e.wal != nil {
+		if err := e.wal.Append(rec); err != nil {
+			return journal.Result{}, fmt.Errorf("%w: %v", ErrJournal, err)
+		}
+	}
+	return journal.Apply(e.registry, *rec)
+}
+
+func (e *Engine) worker(symbol string) (*worker, error) {
+	m, err := e.registry.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := e.workers[m.Symbol]
+	if !ok {
+		return nil, fmt.Errorf("market %s was listed after the engine started: %w", m.Symbol, models.ErrMarketNotFound)
+	}
+	return w, nil
+}
+
+// NOTE: send queues cmd on w, waiting for room until ctx is done.
+func (e *Engine) send(ctx context.Context, w *worker, cmd command) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return ErrStopped
+	}
+	select {
+	case w.commands <- cmd:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+Submit runs a command (new order, cancel or amend) on the goroutine of rec.Symbol's market and returns what it
+did, an account command on the caller's goroutine. The order in the result is a copy, safe to read after Submit
+returns. An order or a withdrawal its owner can't pay for fails with models.ErrInsufficientFunds before it's
+journaled.
+*/
+func (e *Engine) Submit(ctx context.Context, rec journal.Record) (journal.Result, error) {
+	if rec.Kind.IsAccountCommand() {
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+		if e.closed {
+			return journal.Result{}, ErrStopped
+		}
+		return e.applyAccount(&rec)
+	}
+	w, err := e.worker(rec.Symbol)
+	if err != nil {
+		return journal.Result{}, err
+	}
+	if rec.Order != nil {
+		order := *rec.Order
+		rec.Order = &order
+	}
+	cmd := command{rec: &rec, reply: make(chan reply, 1)}
+	if err := e.send(ctx, w, cmd); err != nil {
+		return journal.Result{}, err
+	}
+	r := <-cmd.reply
+	return r.result, r.err
+}
+
+// NOTE: Exec runs fn on the goroutine of symbol's market, the only place its book may be read, and waits for it.
+func (e *Engine) Exec(ctx context.Context, symbol string, fn func(*models.Market)) error {
+	w, err := e.worker(symbol)
+	if err != nil {
+		return err
+	}
+	return e.exec(ctx, w, fn)
+}
+
+func (e *Engine) exec(ctx context.Context, w *worker, fn func(*models.Market)) error {
+	cmd := command{fn: fn, reply: make(chan reply, 1)}
+	if err := e.send(ctx, w, cmd); err != nil {
+		return err
+	}
+	<-cmd.reply
+	return nil
+}
+
+// NOTE: Each runs fn on the goroutine of every market in turn.
+func (e *Engine) Each(ctx context.Context, fn func(*models.Market)) error {
+	for _, m := range e.registry.Markets() {
+		w, ok := e.workers[m.Symbol]
+		if !ok {
+			continue
+		}
+		if err := e.exec(ctx, w, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NOTE: OpenOrders returns copies of owner's open orders in one market, or in every market when symbol is empty.
+func (e *Engine) OpenOrders(ctx context.Context, owner, symbol string) ([]models.Order, error) {
+	var orders []models.Order
+	collect := func(m *models.Market) {
+		for _, order := range m.Book.OpenOrders(owner) {
+			orders = append(orders, *order)
+		}
+	}
+	var err error
+	if symbol != "" {
+		err = e.Exec(ctx, symbol, collect)
+	} else {
+		err = e.Each(ctx, collect)
+	}
+	return orders, err
+}
+
+/*
+FindOrder returns a copy of the open order id. Only the queue of the order's market is waited on, the registry
+knows which one it is (see models.MarketRegistry.OrderSymbol).
+*/
+func (e *Engine) FindOrder(ctx context.Context, id uint) (models.Order, bool, error) {
+	symbol, open := e.registry.OrderSymbol(id)
+	if !open {
+		return models.Order{}, false, nil
+	}
+	return e.GetOrder(ctx, symbol, id)
+}
+
+// NOTE: GetOrder returns a copy of the open order id of symbol's market.
+func (e *Engine) GetOrder(ctx context.Context, symbol string, id uint) (models.Order, bool, error) {
+	var found models.Order
+	var ok bool
+	err := e.Exec(ctx, symbol, func(m *models.Market) {
+		if order, in := m.Book.GetOrder(id); in {
+			found, ok = *order, true
+		}
+	})
+	return found, ok, err
+}
+
+/*
+RunExpiry looks for orders past their expiry in every market each interval until ctx is done or the engine
+stops. A market with an expiry due gets an ExpireOrders command, journaled and applied like any other so a replay
+expires the same orders at the same point, a market with none is left alone.
+*/
+func (e *Engine) RunExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, w := range e.workers {
+				if err := e.send(ctx, w, command{fn: e.expire}); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *Engine) expire(m *models.Market) {
+	if !m.Book.ExpiryDue(time.Now()) {
+		return
+	}
+	if _, err := e.apply(m, &journal.Record{Kind: journal.ExpireOrders, Symbol: m.Symbol}); err != nil {
+		log.Printf("failed to expire the orders of %s: %v", m.Symbol, err)
+	}
+}
+
+/*
+Snapshot pauses every market between two commands, then takes the registry's state and the journal's LSN,
+which match: every journaled command has been applied and no other can start until the markets resume.
+*/
+func (e *Engine) Snapshot(ctx context.Context) (uint64, models.RegistryState, error) {
+	e.snapshotMu.Lock()
+	defer e.snapshotMu.Unlock()
+	release := make(chan struct{})
+	defer close(release)
+	paused := make(chan struct{}, len(e.workers))
+	for _, w := range e.workers {
+		cmd := command{fn: func(*models.Market) {
+			paused <- struct{}{}
+			<-release
+		}}
+		if err := e.send(ctx, w, cmd); err != nil {
+			return 0, models.RegistryState{}, err
+		}
+	}
+	for range e.workers {
+		select {
+		case <-paused:
+		case <-ctx.Done():
+			return 0, models.RegistryState{}, ctx.Err()
+		}
+	}
+	e.walMu.Lock() // NOTE: keeps account commands out as well
+	defer e.walMu.Unlock()
+	var lsn uint64
+	if e.wal != nil {
+		lsn = e.wal.LSN()
+	}
+	return lsn, e.registry.State(), nil
+}
+
+// NOTE: Close stops taking commands, lets the markets finish the queued ones and waits for their goroutines.
+func (e *Engine) Close() {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.closed = true
+	for _, w := range e.workers {
+		close(w.commands)
+	}
+	e.mu.Unlock()
+	e.wg.Wait()
+}
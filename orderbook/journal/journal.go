@@ -0,0 +1,397 @@
+/*
+Package journal is the write-ahead log of the orderbook service.
+
+Every accepted command (new order, cancel, amend, deposit or withdrawal) is appended and synced to the journal
+before it is applied to the books, together with the clock value the books use while applying it. Replaying the journal into fresh books
+therefore rebuilds the same state, order IDs and trades included.
+
+On disk a record is framed as:
+
+	[4 bytes length][4 bytes CRC-32 (IEEE) of the payload][payload: the JSON Record]
+
+big endian. A record cut short by a crash (torn tail) is dropped on open, a bad record followed by more data is
+corruption and stops the replay.
+*/
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+)
+
+type Kind int
+
+const (
+	NewOrder Kind = iota + 1
+	CancelOrder
+	AmendOrder
+	Outcome          // the digest of the trades command LSN produced, written after applying it
+	Deposit          // opens a deposit request
+	Withdraw         // opens a withdrawal request and holds its funds
+	ConfirmTransfer  // custody saw a deposit, or an admin approved a withdrawal
+	CompleteTransfer // custody settled a transfer
+	RejectTransfer
+	SetSelfTradePrevention // an owner's account setting, see models.SelfTradePrevention
+	ExpireOrders           // expires a market's GTD/DAY orders due at the record's time
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case NewOrder:
+		return "NewOrder"
+	case CancelOrder:
+		return "CancelOrder"
+	case AmendOrder:
+		return "AmendOrder"
+	case Outcome:
+		return "Outcome"
+	case Deposit:
+		return "Deposit"
+	case Withdraw:
+		return "Withdraw"
+	case ConfirmTransfer:
+		return "ConfirmTransfer"
+	case CompleteTransfer:
+		return "CompleteTransfer"
+	case RejectTransfer:
+		return "RejectTransfer"
+	case SetSelfTradePrevention:
+		return "SetSelfTradePrevention"
+	case ExpireOrders:
+		return "ExpireOrders"
+	default:
+		return "Unknown"
+	}
+}
+
+/*
+Record is one journal entry. Time is the book clock in unix nanoseconds, Order the order as it was submitted.
+Account commands (transfers) have no symbol. A request names the transfer (TransferID, handed out before it's
+journaled), Owner, Asset, Amount and Address; the commands that move it on name the transfer and, in Owner, the
+admin approving a withdrawal. SetSelfTradePrevention names the Owner and the mode, ExpireOrders just the Symbol.
+*/
+type Record struct {
+	LSN      uint64         `json:"lsn"`
+	Kind     Kind           `json:"kind"`
+	Time     int64          `json:"time,omitempty"`
+	Symbol   string         `json:"symbol,omitempty"`
+	Order    *models.Order  `json:"order,omitempty"`
+	OrderID  uint           `json:"order_id,omitempty"`
+	Price    models.Decimal `json:"price"`
+	Quantity models.Decimal `json:"quantity"`
+	Owner    string         `json:"owner,omitempty"`
+	Asset    string         `json:"asset,omitempty"`
+	Amount   models.Decimal `json:"amount"`
+	Digest   string         `json:"digest,omitempty"`
+
+	TransferID uint64 `json:"transfer_id,omitempty"`
+	Address    string `json:"address,omitempty"`
+	TxID       string `json:"tx_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+
+	SelfTradePrevention models.SelfTradePrevention `json:"self_trade_prevention,omitempty"`
+}
+
+// NOTE: IsAccountCommand reports whether the command is about an account rather than any market's book.
+func (kind Kind) IsAccountCommand() bool {
+	switch kind {
+	case Deposit, Withdraw, ConfirmTransfer, CompleteTransfer, RejectTransfer, SetSelfTradePrevention:
+		return true
+	default:
+		return false
+	}
+}
+
+const headerSize = 8
+
+var (
+	ErrCorrupt = errors.New("journal is corrupted")
+	crcTable   = crc32.MakeTable(crc32.IEEE)
+)
+
+func encode(rec *Record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("error in encoding journal record: %v", err)
+	}
+	frame := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(payload, crcTable))
+	copy(frame[headerSize:], payload)
+	return frame, nil
+}
+
+/*
+Read calls fn with every record of the journal at path, in order, and returns the size of its valid part.
+A missing file is an empty journal. A torn tail is ignored, anything else that doesn't check out is ErrCorrupt.
+*/
+func Read(path string, fn func(Record) error) (int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error in opening journal: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	var offset int64
+	header := make([]byte, headerSize)
+	for {
+		if _, err := io.ReadFull(file, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return offset, nil // NOTE: clean end, or a header cut short
+			}
+			return offset, err
+		}
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		end := offset + headerSize + length
+		if end > size {
+			return offset, nil // NOTE: payload cut short
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(file, payload); err != nil {
+			return offset, err
+		}
+		var rec Record
+		if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) || json.Unmarshal(payload, &rec) != nil {
+			if end == size {
+				return offset, nil // NOTE: the last record was being written when the process died
+			}
+			return offset, fmt.Errorf("bad record at offset %d: %w", offset, ErrCorrupt)
+		}
+		if err := fn(rec); err != nil {
+			return offset, err
+		}
+		offset = end
+	}
+}
+
+// NOTE: Writer appends to a journal file, it's not safe for concurrent use.
+type Writer struct {
+	file *os.File
+	lsn  uint64
+}
+
+// NOTE: Open opens the journal at path for appending, creating it if needed and cutting off a torn tail.
+func Open(path string) (*Writer, error) {
+	var lsn uint64
+	valid, err := Read(path, func(rec Record) error {
+		lsn = rec.LSN
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error in opening journal: %v", err)
+	}
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error in truncating journal: %v", err)
+	}
+	if _, err := file.Seek(valid, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &Writer{file: file, lsn: lsn}, nil
+}
+
+// NOTE: LSN is the sequence number of the last command in the journal.
+func (w *Writer) LSN() uint64 { return w.lsn }
+
+// NOTE: Append gives the command the next LSN, writes it and syncs the file, so it survives a crash once it returns.
+func (w *Writer) Append(rec *Record) error {
+	if rec.Kind == Outcome {
+		return fmt.Errorf("outcomes are written with AppendOutcome")
+	}
+	rec.LSN = w.lsn + 1
+	if err := w.write(rec); err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("error in syncing journal: %v", err)
+	}
+	w.lsn = rec.LSN
+	return nil
+}
+
+// NOTE: AppendOutcome records what command lsn produced. It isn't synced: losing it only loses a check, not state.
+func (w *Writer) AppendOutcome(lsn uint64, trades []models.Trade) error {
+	return w.write(&Record{LSN: lsn, Kind: Outcome, Digest: Digest(trades)})
+}
+
+func (w *Writer) write(rec *Record) error {
+	frame, err := encode(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := w.file.Write(frame); err != nil {
+		return fmt.Errorf("error in writing journal: %v", err)
+	}
+	return nil
+}
+
+func (w *Writer) Close() error {
+	return w.file.Close()
+}
+
+// NOTE: Result is what applying a command did: the order it created or touched and its trades, the transfer it moved or the orders it expired.
+type Result struct {
+	Order    *models.Order
+	Trades   []models.Trade
+	Transfer *models.Transfer // NOTE: set by the transfer commands
+	Expired  []*models.Order  // NOTE: set by ExpireOrders
+}
+
+/*
+Apply runs a command against the registry with the book clock pinned to the command's time, the one code path
+for live commands and replay. Rejected commands are still deterministic: they fail the same way on replay.
+*/
+func Apply(registry *models.MarketRegistry, rec Record) (Result, error) {
+	registry.BeginCommand(rec.Symbol, rec.LSN)
+	if rec.Kind == SetSelfTradePrevention {
+		return Result{}, registry.SetSelfTradePrevention(rec.Owner, rec.SelfTradePrevention)
+	}
+	if rec.Kind.IsAccountCommand() {
+		transfer, err := applyTransfer(registry, rec)
+		return Result{Transfer: &transfer}, err
+	}
+	m, err := registry.Market(rec.Symbol)
+	if err != nil {
+		return Result{}, err
+	}
+	now := time.Unix(0, rec.Time)
+	m.Book.SetClock(func() time.Time { return now })
+	defer m.Book.SetClock(time.Now)
+
+	switch rec.Kind {
+	case NewOrder:
+		if rec.Order == nil {
+			return Result{}, fmt.Errorf("lsn %d: new order record without an order", rec.LSN)
+		}
+		order := *rec.Order
+		trades, err := registry.AddOrder(&order)
+		return Result{Order: &order, Trades: trades}, err
+	case CancelOrder:
+		order, err := registry.CancelOrder(rec.Symbol, rec.OrderID)
+		return Result{Order: order}, err
+	case AmendOrder:
+		order, err := registry.GetOrder(rec.Symbol, rec.OrderID)
+		if err != nil {
+			return Result{}, err
+		}
+		trades, err := registry.AmendOrder(rec.Symbol, rec.OrderID, rec.Price, rec.Quantity)
+		return Result{Order: order, Trades: trades}, err
+	case ExpireOrders:
+		expired, err := registry.ExpireOrders(rec.Symbol, now)
+		return Result{Expired: expired}, err
+	default:
+		return Result{}, fmt.Errorf("lsn %d: %s is not a command", rec.LSN, rec.Kind)
+	}
+}
+
+func applyTransfer(registry *models.MarketRegistry, rec Record) (models.Transfer, error) {
+	at := time.Unix(0, rec.Time).UTC()
+	switch rec.Kind {
+	case Deposit:
+		return registry.RequestDeposit(rec.TransferID, rec.Owner, rec.Asset, rec.Amount, rec.Address, at)
+	case Withdraw:
+		return registry.RequestWithdrawal(rec.TransferID, rec.Owner, rec.Asset, rec.Amount, rec.Address, at)
+	case ConfirmTransfer:
+		return registry.ConfirmTransfer(rec.TransferID, rec.Owner, at)
+	case CompleteTransfer:
+		return registry.CompleteTransfer(rec.TransferID, rec.TxID, at)
+	case RejectTransfer:
+		return registry.RejectTransfer(rec.TransferID, rec.Reason, at)
+	default:
+		return models.Transfer{}, fmt.Errorf("lsn %d: %s is not an account command", rec.LSN, rec.Kind)
+	}
+}
+
+/*
+Digest is a hex SHA-256 of the trades in a fixed text form (every field that matching decides,
+nothing the database adds), so equal digests mean byte-identical trades.
+*/
+func Digest(trades []models.Trade) string {
+	hash := sha256.New()
+	for _, t := range trades {
+		fmt.Fprintf(hash, "%s|%d|%d|%d|%s|%s|%s|%s|%d|%s|%s|%d\n",
+			t.Symbol, t.Sequence, t.MakerOrderID, t.TakerOrderID, t.MakerOwner, t.TakerOwner,
+			t.Price, t.Quantity, t.AggressorSide, t.MakerFee, t.TakerFee, t.Timestamp)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NOTE: Replay applies every command of the journal at path to registry, returning the last LSN applied.
+func Replay(path string, registry *models.MarketRegistry) (uint64, error) {
+	return ReplayAfter(path, registry, 0)
+}
+
+// NOTE: ReplayAfter applies the commands after LSN after, the tail a snapshot taken at after doesn't include.
+func ReplayAfter(path string, registry *models.MarketRegistry, after uint64) (uint64, error) {
+	return ReplayAfterFunc(path, registry, after, nil)
+}
+
+// NOTE: ReplayAfterFunc is ReplayAfter calling fn (when set) with every command applied and what it did.
+func ReplayAfterFunc(path string, registry *models.MarketRegistry, after uint64, fn func(Record, Result)) (uint64, error) {
+	lsn := after
+	_, err := Read(path, func(rec Record) error {
+		if rec.Kind == Outcome || rec.LSN <= after {
+			return nil
+		}
+		result, err := Apply(registry, rec)
+		if err == nil && fn != nil { // NOTE: a command rejected live is rejected again, that's not a replay error
+			fn(rec, result)
+		}
+		lsn = rec.LSN
+		return nil
+	})
+	return lsn, err
+}
+
+// NOTE: Report is what Verify found: how many commands it replayed and which ones produced different trades.
+type Report struct {
+	Commands   int
+	Checked    int
+	Mismatches []uint64
+}
+
+// NOTE: Verify replays the journal at path into registry and compares each command's trades with its recorded outcome.
+func Verify(path string, registry *models.MarketRegistry) (Report, error) {
+	var report Report
+	digests := make(map[uint64]string)
+	_, err := Read(path, func(rec Record) error {
+		if rec.Kind == Outcome {
+			if digest, ok := digests[rec.LSN]; ok {
+				report.Checked++
+				if digest != rec.Digest {
+					report.Mismatches = append(report.Mismatches, rec.LSN)
+				}
+				delete(digests, rec.LSN)
+			}
+			return nil
+		}
+		result, _ := Apply(registry, rec)
+		report.Commands++
+		digests[rec.LSN] = Digest(result.Trades)
+		return nil
+	})
+	return report, err
+}
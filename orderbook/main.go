@@ -1,5 +1,24 @@
+// Command orderbook runs the orderbook gRPC service (see server.Run) until it's interrupted. It reads .env from the
+// directory it's started in (the repository's sits at its root) and the markets file (MARKETS_FILE_ORDERBOOK)
+// relative to it.
+//
+//	go build -o orderbook . && ./orderbook
 package main
 
-func main() {
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/server"
+)
 
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := server.Run(ctx); err != nil {
+		log.Fatalf("orderbook: %v", err)
+	}
 }
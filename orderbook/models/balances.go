@@ -0,0 +1,470 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// NOTE: Balance is what a user holds of one asset. Locked is held for open orders and withdrawals, Available is free to use.
+type Balance struct {
+	Owner     string  `json:"owner"`
+	Asset     string  `json:"asset"`
+	Available Decimal `json:"available"`
+	Locked    Decimal `json:"locked"`
+}
+
+func (balance Balance) Total() Decimal {
+	return balance.Available.Add(balance.Locked)
+}
+
+type reservation struct {
+	owner  string
+	asset  string
+	amount Decimal
+}
+
+/*
+Balances keeps every user's funds by asset, as the totals of their available and locked accounts in the
+ledger: every change is a ledger entry. It's safe for concurrent use: a user's funds are shared by the books of
+every market.
+
+Funds move in two steps so a replay of the journal, which runs commands one market after another rather than
+interleaved as they ran live, moves them the same way:
+  - Reserve checks a command can be paid for before it's journaled, counting the reservations of commands
+    still on their way to their book, so two markets can't spend the same funds.
+  - applying the command locks the funds (and clears the reservation) without checking again. Locks, settlements
+    and releases only add and subtract, so their order doesn't change the final balances.
+*/
+type Balances struct {
+	mu        sync.Mutex // NOTE: makes a reservation check and the postings it races with atomic
+	ledger    *Ledger
+	transfers *Transfers
+	reserved  map[string]reservation // NOTE: by the reference of what they're for, as in the ledger ("order 12")
+}
+
+func NewBalances() *Balances {
+	return &Balances{ledger: NewLedger(), transfers: NewTransfers(), reserved: make(map[string]reservation)}
+}
+
+func (b *Balances) Ledger() *Ledger {
+	return b.ledger
+}
+
+// NOTE: Transfers are the deposits and withdrawals that move funds in and out of the exchange.
+func (b *Balances) Transfers() *Transfers {
+	return b.transfers
+}
+
+func userAccount(owner string, kind AccountKind) Account {
+	return Account{Owner: owner, Kind: kind}
+}
+
+// NOTE: mustPost books an entry built to balance, one that doesn't is a bug. ids numbers it, see entryIDs.
+func (b *Balances) mustPost(ids *entryIDs, kind EntryKind, reference string, lines ...LedgerLine) {
+	if _, err := b.ledger.post(ids.next(), kind, reference, lines...); err != nil {
+		panic(err)
+	}
+}
+
+func (b *Balances) Get(owner, asset string) Balance {
+	asset = strings.ToUpper(asset)
+	return Balance{
+		Owner:     owner,
+		Asset:     asset,
+		Available: b.ledger.Total(userAccount(owner, AccountAvailable), asset),
+		Locked:    b.ledger.Total(userAccount(owner, AccountLocked), asset),
+	}
+}
+
+// NOTE: Of returns owner's balances by asset code, All everyone's by owner then asset.
+func (b *Balances) Of(owner string) []Balance {
+	return b.list(func(balance LedgerBalance) bool { return balance.Owner == owner })
+}
+
+func (b *Balances) All() []Balance {
+	return b.list(func(LedgerBalance) bool { return true })
+}
+
+func (b *Balances) list(keep func(LedgerBalance) bool) []Balance {
+	by_key := make(map[[2]string]*Balance)
+	var balances []*Balance
+	for _, total := range b.ledger.Balances() {
+		if total.Owner == "" || !keep(total) {
+			continue
+		}
+		key := [2]string{total.Owner, total.Asset}
+		balance, ok := by_key[key]
+		if !ok {
+			balance = &Balance{Owner: total.Owner, Asset: total.Asset}
+			by_key[key] = balance
+			balances = append(balances, balance)
+		}
+		switch total.Account {
+		case AccountAvailable:
+			balance.Available = total.Amount
+		case AccountLocked:
+			balance.Locked = total.Amount
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Owner != balances[j].Owner {
+			return balances[i].Owner < balances[j].Owner
+		}
+		return balances[i].Asset < balances[j].Asset
+	})
+	list := make([]Balance, len(balances))
+	for i, balance := range balances {
+		list[i] = *balance
+	}
+	return list
+}
+
+// NOTE: Deposit brings amount into owner's available funds from custody, reference says where it came from.
+func (b *Balances) Deposit(owner, asset string, amount Decimal, reference string) error {
+	return b.deposit(nil, owner, asset, amount, reference)
+}
+
+func (b *Balances) deposit(ids *entryIDs, owner, asset string, amount Decimal, reference string) error {
+	if owner == "" || asset == "" {
+		return fmt.Errorf("deposit needs an owner and an asset")
+	}
+	if !amount.IsPositive() {
+		return fmt.Errorf("deposit amount must be greater than zero")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := b.ledger.post(ids.next(), EntryDeposit, reference,
+		debit(userAccount(owner, AccountAvailable), asset, amount),
+		credit(Account{Kind: AccountCustody}, asset, amount))
+	return err
+}
+
+/*
+Reserve sets amount of owner's available funds aside for the command on reference until it's applied (or
+Unreserve is called), failing with ErrInsufficientFunds when what is available and not reserved yet falls short.
+*/
+func (b *Balances) Reserve(reference, owner, asset string, amount Decimal) error {
+	asset = strings.ToUpper(asset)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.reserved[reference]; ok {
+		return fmt.Errorf("%s already has funds reserved", reference)
+	}
+	free := b.ledger.Total(userAccount(owner, AccountAvailable), asset)
+	for _, r := range b.reserved {
+		if r.owner == owner && r.asset == asset {
+			free = free.Sub(r.amount)
+		}
+	}
+	if free.LessThan(amount) {
+		return fmt.Errorf("%s needs %s %s, %s is available: %w", owner, amount, asset, free, ErrInsufficientFunds)
+	}
+	b.reserved[reference] = reservation{owner: owner, asset: asset, amount: amount}
+	return nil
+}
+
+func (b *Balances) Unreserve(reference string) {
+	b.mu.Lock()
+	delete(b.reserved, reference)
+	b.mu.Unlock()
+}
+
+// NOTE: lock moves amount from available to locked for reference (back when it's negative) and clears its reservation.
+func (b *Balances) lock(ids *entryIDs, reference, owner, asset string, amount Decimal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.reserved, reference)
+	available, locked := userAccount(owner, AccountAvailable), userAccount(owner, AccountLocked)
+	if amount.IsNegative() {
+		b.mustPost(ids, EntryRelease, reference, debit(available, asset, amount.Neg()), credit(locked, asset, amount.Neg()))
+		return
+	}
+	b.mustPost(ids, EntryHold, reference, debit(locked, asset, amount), credit(available, asset, amount))
+}
+
+// NOTE: withdraw sends amount owner locked for reference out to custody.
+func (b *Balances) withdraw(ids *entryIDs, reference, owner, asset string, amount Decimal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mustPost(ids, EntryWithdrawal, reference,
+		debit(Account{Kind: AccountCustody}, asset, amount),
+		credit(userAccount(owner, AccountLocked), asset, amount))
+}
+
+/*
+settle books a trade: the buyer pays notional out of its locked quote and gets the base, the seller the other
+way round. Then the fees go to the house: the buyer's out of what it holds, the seller's off what it got. A
+rebate (negative fee) is paid into the side's available funds.
+*/
+func (b *Balances) settle(ids *entryIDs, trade *Trade, buyer, seller, base, quote string, notional, buyer_fee, seller_fee Decimal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reference := fmt.Sprintf("trade %s/%d", trade.Symbol, trade.Sequence)
+	b.mustPost(ids, EntryTrade, reference,
+		credit(userAccount(buyer, AccountLocked), quote, notional),
+		debit(userAccount(buyer, AccountAvailable), base, trade.Quantity),
+		credit(userAccount(seller, AccountLocked), base, trade.Quantity),
+		debit(userAccount(seller, AccountAvailable), quote, notional))
+	buyer_pays := userAccount(buyer, AccountLocked)
+	if buyer_fee.IsNegative() {
+		buyer_pays = userAccount(buyer, AccountAvailable)
+	}
+	b.mustPost(ids, EntryFee, reference,
+		credit(buyer_pays, quote, buyer_fee),
+		credit(userAccount(seller, AccountAvailable), quote, seller_fee),
+		debit(Account{Kind: AccountHouse}, quote, buyer_fee.Add(seller_fee)))
+}
+
+/*
+holdFor is what an order locks while it's open: the base asset it may sell, or the quote asset it may spend
+buying (rounded up to the quote asset's scale) and the fees on it. A market buy has no price, it holds what
+sweeping the asks within its limit would cost right now. A stop-market buy can't know that before it triggers,
+so it has to bound its price with a protection price.
+*/
+func (m *Market) holdFor(order *Order) (string, Decimal, error) {
+	remaining := order.Remaining()
+	if order.Side == Sell {
+		return m.BaseAsset, remaining, nil
+	}
+	var cost Decimal
+	var err error
+	switch order.Type {
+	case MarketOrder:
+		cost, err = m.Book.sweepCost(order, m.Quote.Scale)
+	case StopMarketOrder:
+		if !order.ProtectionPrice.IsPositive() {
+			return "", Zero, fmt.Errorf("a stop market buy needs a protection price to hold funds for")
+		}
+		cost, err = order.ProtectionPrice.MulRound(remaining, m.Quote.Scale, RoundUp)
+	default:
+		cost, err = order.Price.MulRound(remaining, m.Quote.Scale, RoundUp)
+	}
+	if err != nil {
+		return "", Zero, err
+	}
+	fees, err := m.feeHold(order, cost)
+	return m.QuoteAsset, cost.Add(fees), err
+}
+
+// NOTE: sweepCost is what a market buy may spend on the asks within its limit, hidden iceberg quantity included
+// and its owner's own asks left out as self-trade prevention would, expired asks as matching would.
+func (ob *Orderbook) sweepCost(order *Order, scale uint8) (Decimal, error) {
+	limit := ob.takerLimit(order, ob.askOrders)
+	remaining, cost, now := order.Remaining(), Zero, ob.now()
+	for lvl := range ob.askOrders.levels.all() {
+		if !remaining.IsPositive() || !crosses(Buy, limit, lvl.Price) {
+			break
+		}
+		for _, maker := range lvl.Orders() {
+			if maker.expiredBy(now) {
+				continue
+			}
+			if order.selfTrade(maker) {
+				used, stop := order.selfTradeUse(maker, remaining)
+				if stop {
+					return cost, nil
+				}
+				remaining = remaining.Sub(used)
+				continue
+			}
+			qty := MinDecimal(remaining, maker.Remaining())
+			spent, err := lvl.Price.MulRound(qty, scale, RoundUp)
+			if err != nil {
+				return Zero, err
+			}
+			cost = cost.Add(spent)
+			remaining = remaining.Sub(qty)
+			if !remaining.IsPositive() {
+				break
+			}
+		}
+	}
+	return cost, nil
+}
+
+// NOTE: isDone reports whether the order left the books for good, so whatever it still holds goes back.
+func (order *Order) isDone() bool {
+	switch order.Status {
+	case StatusFilled, StatusCancelled, StatusExpired, StatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// NOTE: SetBalances makes the registry hold funds for the orders it takes. Set it before the first order.
+func (r *MarketRegistry) SetBalances(balances *Balances) {
+	r.mu.Lock()
+	r.balances = balances
+	r.mu.Unlock()
+}
+
+func (r *MarketRegistry) Balances() *Balances {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.balances
+}
+
+func orderReference(id uint) string {
+	return fmt.Sprintf("order %d", id)
+}
+
+// NOTE: ReserveOrder reserves the funds order needs (see Balances), order.ID must be set. Without balances it's a no-op.
+func (r *MarketRegistry) ReserveOrder(order *Order) error {
+	balances := r.Balances()
+	if balances == nil {
+		return nil
+	}
+	m, err := r.Market(order.Symbol)
+	if err != nil {
+		return err
+	}
+	pinned := *order
+	m.pinFees(&pinned)
+	asset, amount, err := m.holdFor(&pinned)
+	if err != nil {
+		return err
+	}
+	return balances.Reserve(orderReference(order.ID), order.OwnerUsername, asset, amount)
+}
+
+// NOTE: Unreserve drops what is left of the reservation for order id once its command has been applied (or refused).
+func (r *MarketRegistry) Unreserve(id uint) {
+	if balances := r.Balances(); balances != nil && id != 0 {
+		balances.Unreserve(orderReference(id))
+	}
+}
+
+// NOTE: ReserveAmend reserves what an amend to newPrice / newQty needs on top of what the order holds already.
+func (r *MarketRegistry) ReserveAmend(symbol string, id uint, newPrice, newQty Decimal) error {
+	balances := r.Balances()
+	if balances == nil {
+		return nil
+	}
+	m, err := r.Market(symbol)
+	if err != nil {
+		return err
+	}
+	order, ok := m.Book.GetOrder(id)
+	if !ok {
+		return nil // NOTE: the amend fails on its own
+	}
+	asset, amount, err := m.holdAt(order, newPrice, newQty)
+	if err != nil {
+		return err
+	}
+	if extra := amount.Sub(order.Held); extra.IsPositive() {
+		return balances.Reserve(orderReference(id), order.OwnerUsername, asset, extra)
+	}
+	return nil
+}
+
+// NOTE: holdAt is what order would hold at price and total quantity.
+func (m *Market) holdAt(order *Order, price, quantity Decimal) (string, Decimal, error) {
+	amended := *order
+	amended.Price, amended.Quantity = price, quantity
+	return m.holdFor(&amended)
+}
+
+// NOTE: holdFunds locks what order needs when it's applied. The funds were checked by ReserveOrder, or this is a replay.
+func (r *MarketRegistry) holdFunds(m *Market, order *Order) error {
+	balances := r.Balances()
+	if balances == nil {
+		return nil
+	}
+	asset, amount, err := m.holdFor(order)
+	if err != nil {
+		return err
+	}
+	balances.lock(&m.entries, orderReference(order.ID), order.OwnerUsername, asset, amount)
+	order.Held = amount
+	return nil
+}
+
+// NOTE: rehold locks or gives back the difference between what order holds and what it needs at price and quantity.
+func (r *MarketRegistry) rehold(m *Market, order *Order, price, quantity Decimal) (Decimal, error) {
+	balances := r.Balances()
+	if balances == nil {
+		return Zero, nil
+	}
+	asset, amount, err := m.holdAt(order, price, quantity)
+	if err != nil {
+		return Zero, err
+	}
+	delta := amount.Sub(order.Held)
+	balances.lock(&m.entries, orderReference(order.ID), order.OwnerUsername, asset, delta)
+	order.Held = amount
+	return delta, nil
+}
+
+// NOTE: releaseFunds gives back what order holds.
+func (r *MarketRegistry) releaseFunds(m *Market, order *Order) {
+	balances := r.Balances()
+	if balances == nil || order.Held.IsZero() {
+		return
+	}
+	asset := m.QuoteAsset
+	if order.Side == Sell {
+		asset = m.BaseAsset
+	}
+	balances.lock(&m.entries, orderReference(order.ID), order.OwnerUsername, asset, order.Held.Neg())
+	order.Held = Zero
+}
+
+/*
+settleFunds moves the funds of a fill. Each side's order takes what it spent (fee included) or sold off what it
+holds on its own fill event, the trade itself is booked once, on the taker's, where it also counts towards both
+owners' volume. An order that is done gives back what it still holds (price improvement, the unfilled rest), an
+amended one what it no longer needs.
+*/
+func (r *MarketRegistry) settleFunds(m *Market, event OrderEvent) {
+	balances := r.Balances()
+	if balances == nil {
+		return
+	}
+	order, fill := event.Order, event.Fill
+	if event.Kind == OrderFilled && fill != nil {
+		notional, err := m.Notional(fill.Price, fill.Quantity)
+		if err != nil {
+			notional = Zero
+		}
+		if order.Side == Buy {
+			order.Held = order.Held.Sub(notional)
+			if fee := tradeFee(order, fill); fee.IsPositive() {
+				order.Held = order.Held.Sub(fee)
+			}
+		} else {
+			order.Held = order.Held.Sub(fill.Quantity)
+		}
+		if order.ID == fill.TakerOrderID {
+			buyer, seller := fill.TakerOwner, fill.MakerOwner
+			buyer_fee, seller_fee := fill.TakerFee, fill.MakerFee
+			if fill.AggressorSide == Sell {
+				buyer, seller = seller, buyer
+				buyer_fee, seller_fee = seller_fee, buyer_fee
+			}
+			balances.settle(&m.entries, fill, buyer, seller, m.BaseAsset, m.QuoteAsset, notional, buyer_fee, seller_fee)
+		}
+	}
+	if event.Kind == OrderAmended && !order.isMarket() {
+		r.trimHold(m, order)
+	}
+	if order.isDone() {
+		r.releaseFunds(m, order)
+	}
+}
+
+// NOTE: trimHold gives back what order holds beyond what it needs now, once self-trade prevention cut its quantity.
+func (r *MarketRegistry) trimHold(m *Market, order *Order) {
+	asset, amount, err := m.holdFor(order)
+	if err != nil || !amount.LessThan(order.Held) {
+		return
+	}
+	r.Balances().lock(&m.entries, orderReference(order.ID), order.OwnerUsername, asset, amount.Sub(order.Held))
+	order.Held = amount
+}
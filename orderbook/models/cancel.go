@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrOrderNotFound = errors.New("not found in the book")
+
+// NOTE: ErrOrderExpired refuses to amend an order whose time ran out before the expiry sweep took it out.
+var ErrOrderExpired = errors.New("has expired")
+
+// NOTE: CancelOrder pulls a resting order out of the book, or a held stop out of the trigger book.
+func (ob *Orderbook) CancelOrder(id uint) (*Order, error) {
+	if stop, ok := ob.stops[id]; ok {
+		delete(ob.stops, id)
+		stop.Status = StatusCancelled
+		ob.notify(OrderCancelled, stop, nil)
+		return stop, nil
+	}
+	ro, ok := ob.resting[id]
+	if !ok {
+		return nil, fmt.Errorf("order %d: %w", id, ErrOrderNotFound)
+	}
+	order := ro.order()
+	ob.unrest(ro)
+	order.Status = StatusCancelled
+	ob.notify(OrderCancelled, order, nil)
+	return order, nil
+}
+
+/*
+AmendOrder changes the price and/or total quantity of a resting order.
+  - a quantity decrease at the same price is done in place and keeps time priority.
+  - a price change or a quantity increase loses priority: the order is taken out, re-stamped
+    and goes through matching again, so an amend that crosses the spread trades immediately.
+
+newQty is the new total quantity of the order and has to stay above what is already filled (and above the display
+quantity of an iceberg). An order past its ExpiresAt is refused, the expiry sweep takes it out.
+A post-only order keeps its guarantee: an amend that would cross is refused (or repriced) and the order stays as it was.
+*/
+func (ob *Orderbook) AmendOrder(id uint, newPrice, newQty Decimal) ([]Trade, error) {
+	if _, ok := ob.stops[id]; ok {
+		return nil, fmt.Errorf("order %d is a held stop order, cancel and replace it instead", id)
+	}
+	ro, ok := ob.resting[id]
+	if !ok {
+		return nil, fmt.Errorf("order %d: %w", id, ErrOrderNotFound)
+	}
+	order := ro.order()
+	if order.expiredBy(ob.now()) {
+		return nil, fmt.Errorf("order %d %w", id, ErrOrderExpired)
+	}
+	if !newPrice.IsPositive() {
+		return nil, fmt.Errorf("order price must be greater than zero")
+	}
+	if newQty.Cmp(order.FilledQuantity) <= 0 {
+		return nil, fmt.Errorf("new quantity %s must be greater than the filled quantity %s", newQty, order.FilledQuantity)
+	}
+	amended := *order
+	amended.Quantity = newQty
+	if err := validateIceberg(&amended); err != nil {
+		return nil, err
+	}
+
+	if order.PostOnly {
+		price, ok := ob.postOnlyPrice(order, newPrice)
+		if !ok {
+			return nil, fmt.Errorf("order %d: amend rejected: %s", id, RejectPostOnlyWouldCross)
+		}
+		newPrice = price
+	}
+
+	if newPrice == order.Price && newQty.Cmp(order.Quantity) <= 0 {
+		shown := order.visible()
+		order.Quantity = newQty
+		if order.isIceberg() {
+			order.VisibleQuantity = MinDecimal(order.VisibleQuantity, order.Remaining())
+		}
+		ro.level.TotalQuantity = ro.level.TotalQuantity.Sub(shown.Sub(order.visible()))
+		ro.side.touch(ro.level.Price)
+		ob.notify(OrderAmended, order, nil)
+		return nil, nil
+	}
+
+	ob.unrest(ro)
+	order.Price = newPrice
+	order.Quantity = newQty
+	order.Timestamp = ob.now()
+	ob.stamp(order)
+	ob.notify(OrderAmended, order, nil)
+	trades := ob.match(order)
+	return append(trades, ob.fireStops()...), nil
+}
+
+func (ob *Orderbook) unrest(ro *restingOrder) {
+	ro.side.remove(ro.level, ro.elem)
+	delete(ob.resting, ro.order().ID)
+}
@@ -0,0 +1,474 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/*
+NOTE: 18 decimals covers every asset scale we list (ETH uses 18). Amounts have at most 18 whole digits as well,
+what a numeric(36,18) column holds, so the mantissa needs 128 bits: 10^36 doesn't come near an int64.
+*/
+const (
+	MaxDecimalScale = 18
+	maxWholeDigits  = 18
+)
+
+var (
+	ErrDecimalOverflow = errors.New("decimal overflow")
+	ErrDecimalInexact  = errors.New("decimal result needs more than 18 decimals")
+)
+
+var pow10 = [MaxDecimalScale + 1]int64{
+	1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000,
+	10000000000, 100000000000, 1000000000000, 10000000000000, 100000000000000,
+	1000000000000000, 10000000000000000, 100000000000000000, 1000000000000000000,
+}
+
+/*
+Decimal is an exact fixed-point number, mantissa * 10^-scale, used for every price and quantity.
+The mantissa is a 128-bit two's complement number (hi:lo), amounts that fit an int64 take a fast path.
+It's always kept in canonical form (no trailing zeros in the fraction) so two equal amounts are
+equal with == and can be used as map keys, e.g. a price level keyed by its price.
+How many decimals an amount may carry is a property of its asset, see Asset.Scale.
+*/
+type Decimal struct {
+	hi    int64
+	lo    uint64
+	scale uint8
+}
+
+var (
+	Zero = Decimal{}
+	// NOTE: the largest representable amount, used as "no upper bound". It's never stored.
+	MaxDecimal = Decimal{hi: math.MaxInt64, lo: math.MaxUint64}
+)
+
+func NewDecimal(value int64, scale uint8) Decimal {
+	if scale > MaxDecimalScale {
+		panic(fmt.Sprintf("decimal scale %d is above %d", scale, MaxDecimalScale))
+	}
+	return normalize(fromInt64(value, scale))
+}
+
+// NOTE: NewDecimalFromInt is a whole amount, NewDecimalFromInt(5) == 5.
+func NewDecimalFromInt(value int64) Decimal {
+	return fromInt64(value, 0)
+}
+
+func fromInt64(value int64, scale uint8) Decimal {
+	d := Decimal{lo: uint64(value), scale: scale}
+	if value < 0 {
+		d.hi = -1
+	}
+	return d
+}
+
+// NOTE: small returns the mantissa when it fits an int64, the fast path of the arithmetic.
+func (d Decimal) small() (int64, bool) {
+	if (d.hi == 0 && d.lo>>63 == 0) || (d.hi == -1 && d.lo>>63 == 1) {
+		return int64(d.lo), true
+	}
+	return 0, false
+}
+
+func normalize(d Decimal) Decimal {
+	if value, ok := d.small(); ok {
+		scale := d.scale
+		for scale > 0 && value%10 == 0 {
+			value /= 10
+			scale--
+		}
+		return fromInt64(value, scale)
+	}
+	packed, _ := pack(trimZeros(d.big(), int(d.scale)))
+	return packed
+}
+
+// NOTE: ParseDecimal reads a plain decimal string such as "0.0015" or "-12.5" (no exponent).
+func ParseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	digits := s
+	negative := false
+	if digits[0] == '-' || digits[0] == '+' {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	for _, c := range whole + fraction {
+		if c < '0' || c > '9' {
+			return Zero, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	// NOTE: numeric columns come back padded ("1.500000000000000000"), the padding must not overflow.
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > MaxDecimalScale {
+		return Zero, fmt.Errorf("decimal %q has more than %d decimals", s, MaxDecimalScale)
+	}
+	if len(strings.TrimLeft(whole, "0")) > maxWholeDigits {
+		return Zero, fmt.Errorf("decimal %q: %w", s, ErrDecimalOverflow)
+	}
+	value, _ := new(big.Int).SetString("0"+whole+fraction, 10)
+	if negative {
+		value.Neg(value)
+	}
+	return fromBig(value, len(fraction))
+}
+
+// NOTE: MustParseDecimal is ParseDecimal for constants and tests, it panics on bad input.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NOTE: Scale is the number of decimals the amount actually uses.
+func (d Decimal) Scale() uint8 { return d.scale }
+
+func (d Decimal) IsZero() bool     { return d.hi == 0 && d.lo == 0 }
+func (d Decimal) IsPositive() bool { return d.hi > 0 || (d.hi == 0 && d.lo > 0) }
+func (d Decimal) IsNegative() bool { return d.hi < 0 }
+
+func (d Decimal) Neg() Decimal {
+	lo, borrow := bits.Sub64(0, d.lo, 0)
+	hi := -d.hi - int64(borrow)
+	return Decimal{hi: hi, lo: lo, scale: d.scale}
+}
+
+func (d Decimal) Sign() int {
+	switch {
+	case d.IsPositive():
+		return 1
+	case d.IsNegative():
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) big() *big.Int {
+	if value, ok := d.small(); ok {
+		return big.NewInt(value)
+	}
+	value := new(big.Int).Lsh(big.NewInt(d.hi), 64)
+	return value.Add(value, new(big.Int).SetUint64(d.lo))
+}
+
+var mask64 = new(big.Int).SetUint64(math.MaxUint64)
+
+// NOTE: pack stores a mantissa of at most 128 bits, false when it doesn't fit.
+func pack(value *big.Int, scale int) (Decimal, bool) {
+	if value.IsInt64() {
+		return fromInt64(value.Int64(), uint8(scale)), true
+	}
+	if value.BitLen() > 127 {
+		return Zero, false
+	}
+	lo := new(big.Int).And(value, mask64).Uint64()
+	hi := new(big.Int).Rsh(value, 64).Int64()
+	return Decimal{hi: hi, lo: lo, scale: uint8(scale)}, true
+}
+
+// NOTE: aligned returns both mantissas at the larger of the two scales, when they fit an int64.
+func aligned(a, b Decimal) (int64, int64, uint8, bool) {
+	av, a_ok := a.small()
+	bv, b_ok := b.small()
+	if !a_ok || !b_ok {
+		return 0, 0, 0, false
+	}
+	if a.scale == b.scale {
+		return av, bv, a.scale, true
+	}
+	if a.scale < b.scale {
+		av, ok := mulInt64(av, pow10[b.scale-a.scale])
+		return av, bv, b.scale, ok
+	}
+	bv, ok := mulInt64(bv, pow10[a.scale-b.scale])
+	return av, bv, a.scale, ok
+}
+
+// NOTE: alignedBig is aligned for any two amounts.
+func alignedBig(a, b Decimal) (*big.Int, *big.Int, uint8) {
+	scale := max(a.scale, b.scale)
+	ab := new(big.Int).Mul(a.big(), bigPow10(int(scale-a.scale)))
+	bb := new(big.Int).Mul(b.big(), bigPow10(int(scale-b.scale)))
+	return ab, bb, scale
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return c, true
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _, ok := aligned(d, other)
+	if !ok {
+		ab, bb, _ := alignedBig(d, other)
+		return ab.Cmp(bb)
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) LessThan(other Decimal) bool    { return d.Cmp(other) < 0 }
+func (d Decimal) GreaterThan(other Decimal) bool { return d.Cmp(other) > 0 }
+
+/*
+Add and Sub are exact. Amounts have at most 18 whole digits, so sums of them stay far inside 128 bits, but
+nothing keeps a running total (a balance, a wallet) under 18 whole digits: code adding up amounts without bound
+uses CheckedAdd. Add panics only past 128 bits, which no sum of amounts we parse or compute reaches.
+*/
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale, ok := aligned(d, other)
+	if sum := a + b; ok && !((b > 0 && sum < a) || (b < 0 && sum > a)) {
+		return normalize(fromInt64(sum, scale))
+	}
+	ab, bb, scale := alignedBig(d, other)
+	sum, ok := pack(trimZeros(ab.Add(ab, bb), int(scale)))
+	if !ok {
+		panic(ErrDecimalOverflow)
+	}
+	return sum
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+// NOTE: CheckedAdd is Add failing with ErrDecimalOverflow when the sum has more than 18 whole digits.
+func (d Decimal) CheckedAdd(other Decimal) (Decimal, error) {
+	sum := d.Add(other)
+	if !sum.inRange() {
+		return Zero, fmt.Errorf("%s + %s: %w", d, other, ErrDecimalOverflow)
+	}
+	return sum, nil
+}
+
+// NOTE: inRange reports whether the amount has at most 18 whole digits.
+func (d Decimal) inRange() bool {
+	limit := bigPow10(maxWholeDigits + int(d.scale))
+	return new(big.Int).Abs(d.big()).Cmp(limit) < 0
+}
+
+// NOTE: Mul is the exact product, an error if it doesn't fit (more than 18 decimals or 18 whole digits).
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	product := new(big.Int).Mul(d.big(), other.big())
+	return fromBig(product, int(d.scale)+int(other.scale))
+}
+
+type RoundingMode int
+
+const (
+	RoundDown RoundingMode = iota // towards zero
+	RoundUp                       // away from zero
+	RoundHalfUp
+)
+
+// NOTE: MulRound is the product rounded to scale decimals, e.g. a notional or a fee in the quote asset's scale.
+func (d Decimal) MulRound(other Decimal, scale uint8, mode RoundingMode) (Decimal, error) {
+	product := new(big.Int).Mul(d.big(), other.big())
+	return roundBig(product, int(d.scale)+int(other.scale), scale, mode)
+}
+
+// NOTE: QuoRound is d / other rounded to scale decimals.
+func (d Decimal) QuoRound(other Decimal, scale uint8, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Zero, fmt.Errorf("decimal division by zero")
+	}
+	if scale > MaxDecimalScale {
+		return Zero, ErrDecimalInexact
+	}
+	// (dv / 10^ds) / (ov / 10^os) at scale decimals = dv * 10^(scale+os) / (ov * 10^ds)
+	numerator := new(big.Int).Mul(d.big(), bigPow10(int(scale)+int(other.scale)))
+	denominator := new(big.Int).Mul(other.big(), bigPow10(int(d.scale)))
+	return fromBig(roundQuo(numerator, denominator, mode), int(scale))
+}
+
+func roundBig(value *big.Int, from_scale int, scale uint8, mode RoundingMode) (Decimal, error) {
+	if scale > MaxDecimalScale {
+		return Zero, ErrDecimalInexact
+	}
+	if from_scale > int(scale) {
+		value = roundQuo(value, bigPow10(from_scale-int(scale)), mode)
+		from_scale = int(scale)
+	}
+	return fromBig(value, from_scale)
+}
+
+// NOTE: roundQuo is numerator / denominator rounded to an integer with the given mode.
+func roundQuo(numerator, denominator *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+	away := false
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundHalfUp:
+		doubled := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		away = doubled.Cmp(new(big.Int).Abs(denominator)) >= 0
+	}
+	if away {
+		quo.Add(quo, big.NewInt(int64(numerator.Sign()*denominator.Sign())))
+	}
+	return quo
+}
+
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// NOTE: trimZeros drops the trailing zeros of the fraction of value * 10^-scale.
+func trimZeros(value *big.Int, scale int) (*big.Int, int) {
+	ten := big.NewInt(10)
+	rem := new(big.Int)
+	for scale > 0 {
+		quo, r := new(big.Int).QuoRem(value, ten, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		value = quo
+		scale--
+	}
+	return value, scale
+}
+
+func fromBig(value *big.Int, scale int) (Decimal, error) {
+	value, scale = trimZeros(value, scale)
+	if scale > MaxDecimalScale {
+		return Zero, ErrDecimalInexact
+	}
+	d, ok := pack(value, scale)
+	if !ok || !d.inRange() {
+		return Zero, ErrDecimalOverflow
+	}
+	return d, nil
+}
+
+// NOTE: FitsScale reports whether the amount can be written with at most scale decimals.
+func (d Decimal) FitsScale(scale uint8) bool {
+	return d.scale <= scale
+}
+
+// NOTE: IsMultipleOf reports whether d is a whole number of steps, e.g. a price on the tick grid.
+func (d Decimal) IsMultipleOf(step Decimal) bool {
+	if step.IsZero() {
+		return false
+	}
+	a, b, _, ok := aligned(d, step)
+	if !ok {
+		ab, bb, _ := alignedBig(d, step)
+		return new(big.Int).Rem(ab, bb).Sign() == 0
+	}
+	return a%b == 0
+}
+
+func MinDecimal(a, b Decimal) Decimal {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+func (d Decimal) String() string {
+	return d.StringFixed(0)
+}
+
+// NOTE: StringFixed pads the fraction with zeros up to scale decimals ("1.5" at 8 is "1.50000000").
+func (d Decimal) StringFixed(scale uint8) string {
+	digits := d.big().String()
+	sign := ""
+	if d.IsNegative() {
+		sign, digits = "-", digits[1:]
+	}
+	if d.scale > 0 {
+		if len(digits) <= int(d.scale) {
+			digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if scale > d.scale {
+		if d.scale == 0 {
+			digits += "."
+		}
+		digits += strings.Repeat("0", int(scale-d.scale))
+	}
+	return sign + digits
+}
+
+// NOTE: amounts go over JSON as strings so no client ever parses them into a float.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*d = Zero
+		return nil
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NOTE: Value and Scan store amounts in numeric columns as their exact string form.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case int64:
+		*d = NewDecimalFromInt(v)
+		return nil
+	case float64:
+		parsed, err := ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+		*d = parsed
+		return err
+	case []byte:
+		parsed, err := ParseDecimal(string(v))
+		*d = parsed
+		return err
+	case string:
+		parsed, err := ParseDecimal(v)
+		*d = parsed
+		return err
+	default:
+		return fmt.Errorf("can't scan %T into a Decimal", src)
+	}
+}
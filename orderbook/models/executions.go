@@ -0,0 +1,214 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrExecutionsGone = errors.New("execution reports are no longer retained")
+
+// NOTE: ExecutionReport tells an order's owner what happened to it. LastPrice/LastQuantity describe the fill
+// of an OrderFilled report, Maker says on which side of that trade the order was and Fee what it paid.
+type ExecutionReport struct {
+	Sequence       uint64         `json:"sequence"`
+	OwnerUsername  string         `json:"owner_username"`
+	OrderID        uint           `json:"order_id"`
+	Symbol         string         `json:"symbol"`
+	Kind           OrderEventKind `json:"kind"`
+	Side           OrderSide      `json:"side"`
+	Status         OrderStatus    `json:"status"`
+	Price          Decimal        `json:"price"`
+	Quantity       Decimal        `json:"quantity"`
+	FilledQuantity Decimal        `json:"filled_quantity"`
+	LastPrice      Decimal        `json:"last_price"`
+	LastQuantity   Decimal        `json:"last_quantity"`
+	Maker          bool           `json:"maker"`
+	Fee            Decimal        `json:"fee"`
+	RejectReason   RejectReason   `json:"reject_reason"`
+	Timestamp      uint32         `json:"timestamp"`
+}
+
+/*
+ExecutionHub turns order events into per-user execution reports and streams them to that user's subscribers.
+Every user has their own sequence, increasing by one per report, and the last `retain` reports are kept so a
+client that reconnects can resume after the last sequence it saw without missing a fill. A subscriber that
+falls behind its buffer is dropped and resumes the same way.
+*/
+type ExecutionHub struct {
+	mu     sync.Mutex
+	users  map[string]*userExecutions
+	retain int
+	buffer int
+	clock  func() time.Time
+}
+
+type userExecutions struct {
+	sequence    uint64
+	retained    []ExecutionReport // oldest first
+	subscribers map[*ExecutionSubscription]struct{}
+}
+
+type ExecutionSubscription struct {
+	C      <-chan ExecutionReport
+	events chan ExecutionReport
+	hub    *ExecutionHub
+	owner  string
+}
+
+func NewExecutionHub(retain, buffer int) *ExecutionHub {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &ExecutionHub{users: make(map[string]*userExecutions), retain: retain, buffer: buffer, clock: time.Now}
+}
+
+func (h *ExecutionHub) user(owner string) *userExecutions {
+	user, ok := h.users[owner]
+	if !ok {
+		user = &userExecutions{subscribers: make(map[*ExecutionSubscription]struct{})}
+		h.users[owner] = user
+	}
+	return user
+}
+
+// NOTE: Publish is meant to be the books' order listener (or called from it), reports are stamped with the wall clock.
+func (h *ExecutionHub) Publish(event OrderEvent) {
+	h.publish(event, uint32(h.clock().Unix()))
+}
+
+// NOTE: publish reports event as of at, a registry passes its book's clock so a replay stamps reports as the live run did.
+func (h *ExecutionHub) publish(event OrderEvent, at uint32) {
+	order := event.Order
+	report := ExecutionReport{
+		OwnerUsername:  order.OwnerUsername,
+		OrderID:        order.ID,
+		Symbol:         order.Symbol,
+		Kind:           event.Kind,
+		Side:           order.Side,
+		Status:         order.Status,
+		Price:          order.Price,
+		Quantity:       order.Quantity,
+		FilledQuantity: order.FilledQuantity,
+		RejectReason:   order.RejectReason,
+		Timestamp:      at,
+	}
+	if fill := event.Fill; fill != nil {
+		report.LastPrice = fill.Price
+		report.LastQuantity = fill.Quantity
+		report.Maker = fill.MakerOrderID == order.ID
+		report.Fee = tradeFee(order, fill)
+		report.Timestamp = fill.Timestamp
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	user := h.user(order.OwnerUsername)
+	user.sequence++
+	report.Sequence = user.sequence
+	if h.retain > 0 {
+		if len(user.retained) == h.retain {
+			copy(user.retained, user.retained[1:])
+			user.retained = user.retained[:h.retain-1]
+		}
+		user.retained = append(user.retained, report)
+	}
+	for sub := range user.subscribers {
+		select {
+		case sub.events <- report:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+/*
+Subscribe streams owner's execution reports. With after == 0 only new reports are sent, otherwise the retained
+reports with a sequence above after come first. ErrExecutionsGone means some of those were already discarded,
+the client has to rebuild its state (open orders, history) and subscribe again from 0.
+*/
+func (h *ExecutionHub) Subscribe(owner string, after uint64) (*ExecutionSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	user := h.user(owner)
+	if after > user.sequence {
+		return nil, fmt.Errorf("sequence %d is ahead of the last report %d", after, user.sequence)
+	}
+	var replay []ExecutionReport
+	if after > 0 && after < user.sequence {
+		if len(user.retained) == 0 || user.retained[0].Sequence > after+1 {
+			return nil, fmt.Errorf("resume after %d: %w", after, ErrExecutionsGone)
+		}
+		replay = user.retained[after+1-user.retained[0].Sequence:]
+	}
+
+	events := make(chan ExecutionReport, len(replay)+h.buffer)
+	for _, report := range replay {
+		events <- report
+	}
+	sub := &ExecutionSubscription{C: events, events: events, hub: h, owner: owner}
+	user.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// NOTE: Close ends the subscription, it's safe to call more than once and after the hub dropped it.
+func (sub *ExecutionSubscription) Close() {
+	sub.hub.mu.Lock()
+	defer sub.hub.mu.Unlock()
+	sub.hub.drop(sub)
+}
+
+func (h *ExecutionHub) drop(sub *ExecutionSubscription) {
+	user := h.user(sub.owner)
+	if _, ok := user.subscribers[sub]; ok {
+		delete(user.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// NOTE: UserExecutionsState is one user's last sequence and the reports retained for a resume, oldest first.
+type UserExecutionsState struct {
+	Owner    string            `json:"owner"`
+	Sequence uint64            `json:"sequence"`
+	Retained []ExecutionReport `json:"retained,omitempty"`
+}
+
+// NOTE: ExecutionsState is what a hub needs to carry on numbering and resuming after a restart, users by name.
+type ExecutionsState struct {
+	Users []UserExecutionsState `json:"users"`
+}
+
+func (h *ExecutionHub) State() ExecutionsState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := ExecutionsState{Users: []UserExecutionsState{}}
+	for owner, user := range h.users {
+		if user.sequence == 0 {
+			continue
+		}
+		state.Users = append(state.Users, UserExecutionsState{
+			Owner:    owner,
+			Sequence: user.sequence,
+			Retained: append([]ExecutionReport(nil), user.retained...),
+		})
+	}
+	sort.Slice(state.Users, func(i, j int) bool { return state.Users[i].Owner < state.Users[j].Owner })
+	return state
+}
+
+// NOTE: Restore loads state into a hub that hasn't published yet, keeping at most its own number of reports per user.
+func (h *ExecutionHub) Restore(state ExecutionsState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, restored := range state.Users {
+		user := h.user(restored.Owner)
+		user.sequence = restored.Sequence
+		retained := restored.Retained
+		if len(retained) > h.retain {
+			retained = retained[len(retained)-h.retain:]
+		}
+		user.retained = append([]ExecutionReport(nil), retained...)
+	}
+}
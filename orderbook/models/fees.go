@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	maxFeeBps        = 1000 // NOTE: 10%, either way
+	volumeWindowDays = 30
+	secondsPerDay    = 24 * 60 * 60
+)
+
+var bpsUnit = NewDecimal(1, 4)
+
+/*
+FeeTier is the rate owners pay in a market once their volume there over the last 30 days (in the quote asset)
+reached MinVolume. Rates are in basis points of the notional, a negative maker rate is a rebate.
+*/
+type FeeTier struct {
+	MinVolume Decimal `json:"min_volume"`
+	MakerBps  Decimal `json:"maker_bps"`
+	TakerBps  Decimal `json:"taker_bps"`
+}
+
+// NOTE: DailyVolume is what an owner traded in a market on one day (unix days), in the quote asset.
+type DailyVolume struct {
+	Day      uint32  `json:"day"`
+	Notional Decimal `json:"notional"`
+}
+
+type OwnerVolume struct {
+	Owner string        `json:"owner"`
+	Days  []DailyVolume `json:"days"`
+}
+
+/*
+validateFees checks the tiers go up in volume from a first one at zero and that no maker rebate is larger than
+the lowest taker fee, so the house never pays out more on a trade than it takes in.
+*/
+func (m *Market) validateFees() error {
+	if len(m.Fees) == 0 {
+		return nil
+	}
+	if !m.Fees[0].MinVolume.IsZero() {
+		return fmt.Errorf("the first fee tier must start at a volume of zero")
+	}
+	limit := NewDecimalFromInt(maxFeeBps)
+	lowest_taker := limit
+	for i, tier := range m.Fees {
+		if i > 0 && tier.MinVolume.Cmp(m.Fees[i-1].MinVolume) <= 0 {
+			return fmt.Errorf("fee tiers must go up in volume")
+		}
+		if !tier.MinVolume.FitsScale(m.Quote.Scale) {
+			return fmt.Errorf("fee tier volume %s has more than %d decimals (%s)", tier.MinVolume, m.Quote.Scale, m.Quote.Code)
+		}
+		if tier.TakerBps.IsNegative() || limit.LessThan(tier.TakerBps) || limit.LessThan(tier.MakerBps) || tier.MakerBps.LessThan(limit.Neg()) {
+			return fmt.Errorf("fee rates must be within %d bps and taker fees can't be negative", maxFeeBps)
+		}
+		lowest_taker = MinDecimal(lowest_taker, tier.TakerBps)
+	}
+	for _, tier := range m.Fees {
+		if tier.MakerBps.Neg().GreaterThan(lowest_taker) {
+			return fmt.Errorf("a maker rebate of %s bps is more than the lowest taker fee of %s bps", tier.MakerBps.Neg(), lowest_taker)
+		}
+	}
+	return nil
+}
+
+// NOTE: Volume is what owner traded in the market over the 30 days up to now (unix seconds).
+func (m *Market) Volume(owner string, now uint32) Decimal {
+	today, total := now/secondsPerDay, Zero
+	for _, day := range m.volumes[owner] {
+		if day.Day+volumeWindowDays > today {
+			total = total.Add(day.Notional)
+		}
+	}
+	return total
+}
+
+// NOTE: FeeTier is the tier owner trades in right now, with the 30-day volume that put them there.
+func (m *Market) FeeTier(owner string) (FeeTier, Decimal) {
+	volume := m.Volume(owner, m.Book.now())
+	var tier FeeTier
+	for _, t := range m.Fees {
+		if volume.LessThan(t.MinVolume) {
+			break
+		}
+		tier = t
+	}
+	return tier, volume
+}
+
+// NOTE: pinFees gives order the rates of its owner's tier, it keeps them for its life (a stop too, once triggered).
+func (m *Market) pinFees(order *Order) {
+	tier, _ := m.FeeTier(order.OwnerUsername)
+	order.MakerFeeBps, order.TakerFeeBps = tier.MakerBps, tier.TakerBps
+}
+
+// NOTE: addVolume counts notional towards owner's volume today and forgets the days that left the window.
+func (m *Market) addVolume(owner string, notional Decimal, now uint32) {
+	today := now / secondsPerDay
+	days := m.volumes[owner][:0]
+	for _, day := range m.volumes[owner] {
+		if day.Day+volumeWindowDays > today {
+			days = append(days, day)
+		}
+	}
+	if n := len(days); n > 0 && days[n-1].Day == today {
+		days[n-1].Notional = days[n-1].Notional.Add(notional)
+	} else {
+		days = append(days, DailyVolume{Day: today, Notional: notional})
+	}
+	m.volumes[owner] = days
+}
+
+// NOTE: countVolume adds a trade to the volume of both its owners (once when they're the same), on the taker's fill event.
+func (m *Market) countVolume(event OrderEvent) {
+	fill := event.Fill
+	if event.Kind != OrderFilled || fill == nil || event.Order.ID != fill.TakerOrderID {
+		return
+	}
+	notional, err := m.Notional(fill.Price, fill.Quantity)
+	if err != nil {
+		return
+	}
+	m.addVolume(fill.TakerOwner, notional, fill.Timestamp)
+	if fill.MakerOwner != fill.TakerOwner {
+		m.addVolume(fill.MakerOwner, notional, fill.Timestamp)
+	}
+}
+
+func (m *Market) volumeState() []OwnerVolume {
+	var state []OwnerVolume
+	for owner, days := range m.volumes {
+		state = append(state, OwnerVolume{Owner: owner, Days: append([]DailyVolume(nil), days...)})
+	}
+	sort.Slice(state, func(i, j int) bool { return state[i].Owner < state[j].Owner })
+	return state
+}
+
+func (m *Market) restoreVolumes(state []OwnerVolume) {
+	m.volumes = make(map[string][]DailyVolume)
+	for _, owner := range state {
+		m.volumes[owner.Owner] = append([]DailyVolume(nil), owner.Days...)
+	}
+}
+
+// NOTE: feeOf is bps of notional, rounded towards zero: fees in the payer's favour, rebates in the house's.
+func (m *Market) feeOf(notional, bps Decimal) Decimal {
+	rate, err := bps.Mul(bpsUnit)
+	if err != nil {
+		return Zero
+	}
+	fee, err := notional.MulRound(rate, m.Quote.Scale, RoundDown)
+	if err != nil {
+		return Zero
+	}
+	return fee
+}
+
+// NOTE: feeHold is what a buy holds on top of cost for the fees it may pay, at the higher of its two rates.
+func (m *Market) feeHold(order *Order, cost Decimal) (Decimal, error) {
+	bps := order.TakerFeeBps
+	if bps.LessThan(order.MakerFeeBps) {
+		bps = order.MakerFeeBps
+	}
+	if !bps.IsPositive() {
+		return Zero, nil
+	}
+	rate, err := bps.Mul(bpsUnit)
+	if err != nil {
+		return Zero, err
+	}
+	return cost.MulRound(rate, m.Quote.Scale, RoundUp)
+}
+
+// NOTE: chargeFees is the book's fee function: it prices both sides of trade at the rates their orders carry.
+func (m *Market) chargeFees(maker, taker *Order, trade *Trade) {
+	notional, err := m.Notional(trade.Price, trade.Quantity)
+	if err != nil {
+		return
+	}
+	trade.MakerFee = m.feeOf(notional, maker.MakerFeeBps)
+	trade.TakerFee = m.feeOf(notional, taker.TakerFeeBps)
+}
+
+// NOTE: tradeFee is what order paid on fill, negative for a rebate.
+func tradeFee(order *Order, fill *Trade) Decimal {
+	if fill.MakerOrderID == order.ID {
+		return fill.MakerFee
+	}
+	return fill.TakerFee
+}
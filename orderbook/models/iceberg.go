@@ -0,0 +1,53 @@
+package models
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// NOTE: an iceberg order only shows DisplayQuantity of its Quantity in the book at any time.
+func (order *Order) isIceberg() bool {
+	return order.DisplayQuantity.IsPositive()
+}
+
+// NOTE: visible is the part of a resting order other participants can see in depth and match against.
+func (order *Order) visible() Decimal {
+	if order.isIceberg() {
+		return order.VisibleQuantity
+	}
+	return order.Remaining()
+}
+
+func validateIceberg(order *Order) error {
+	if !order.isIceberg() {
+		return nil
+	}
+	if order.DisplayQuantity.Cmp(order.Quantity) >= 0 {
+		return fmt.Errorf("display quantity must be less than the order quantity")
+	}
+	if order.isMarket() {
+		return fmt.Errorf("%s orders can't be iceberg orders", order.Type)
+	}
+	if order.TimeInForce == IOC || order.TimeInForce == FOK {
+		return fmt.Errorf("iceberg orders can't be %s", order.TimeInForce)
+	}
+	return nil
+}
+
+// NOTE: showSlice sets the visible slice of an iceberg that is about to (re)enter a price level.
+func (order *Order) showSlice() {
+	if order.isIceberg() {
+		order.VisibleQuantity = MinDecimal(order.DisplayQuantity, order.Remaining())
+	}
+}
+
+// NOTE: replenish shows the next slice of an iceberg whose visible part was consumed. The new slice
+// is a new arrival as far as priority goes, so it's re-stamped and goes to the back of its price level.
+func (ob *Orderbook) replenish(lvl *PriceLevel, e *list.Element) {
+	order := e.Value.(*Order)
+	lvl.remove(e)
+	order.showSlice()
+	order.Timestamp = ob.now()
+	ob.stamp(order)
+	ob.resting[order.ID].elem = lvl.enqueue(order)
+}
@@ -0,0 +1,346 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrUnbalanced = errors.New("ledger doesn't balance")
+var ErrLedgerMismatch = errors.New("stored ledger doesn't match the books")
+
+type AccountKind int
+
+const (
+	AccountAvailable AccountKind = iota + 1 // a user's funds free to trade or withdraw
+	AccountLocked                           // a user's funds held by open orders
+	AccountCustody                          // the outside world: deposits come out of it, withdrawals go back to it
+	AccountHouse                            // what the exchange earns in fees
+)
+
+func (kind AccountKind) String() string {
+	switch kind {
+	case AccountAvailable:
+		return "available"
+	case AccountLocked:
+		return "locked"
+	case AccountCustody:
+		return "custody"
+	case AccountHouse:
+		return "house"
+	default:
+		return "unknown"
+	}
+}
+
+// NOTE: Account is a ledger account. User accounts have an owner, custody and house accounts don't.
+type Account struct {
+	Owner string      `json:"owner,omitempty"`
+	Kind  AccountKind `json:"kind"`
+}
+
+func (account Account) String() string {
+	if account.Owner == "" {
+		return account.Kind.String()
+	}
+	return account.Owner + ":" + account.Kind.String()
+}
+
+type EntryKind int
+
+const (
+	EntryDeposit EntryKind = iota + 1
+	EntryWithdrawal
+	EntryHold
+	EntryRelease
+	EntryTrade
+	EntryFee
+)
+
+func (kind EntryKind) String() string {
+	switch kind {
+	case EntryDeposit:
+		return "Deposit"
+	case EntryWithdrawal:
+		return "Withdrawal"
+	case EntryHold:
+		return "Hold"
+	case EntryRelease:
+		return "Release"
+	case EntryTrade:
+		return "Trade"
+	case EntryFee:
+		return "Fee"
+	default:
+		return "Unknown"
+	}
+}
+
+// NOTE: LedgerLine is one side of an entry. A positive amount debits the account, a negative one credits it.
+type LedgerLine struct {
+	ID       uint        `json:"-" gorm:"primaryKey"`
+	EntryID  uint64      `json:"-" gorm:"uniqueIndex:idx_ledger_line"`
+	Position int         `json:"-" gorm:"uniqueIndex:idx_ledger_line"` // NOTE: from 1, the line's place in its entry
+	Owner    string      `json:"owner,omitempty" gorm:"index"`
+	Account  AccountKind `json:"account"`
+	Asset    string      `json:"asset" gorm:"index"`
+	Amount   Decimal     `json:"amount" gorm:"type:numeric(36,18)"`
+}
+
+func debit(account Account, asset string, amount Decimal) LedgerLine {
+	return LedgerLine{Owner: account.Owner, Account: account.Kind, Asset: asset, Amount: amount}
+}
+
+func credit(account Account, asset string, amount Decimal) LedgerLine {
+	return debit(account, asset, amount.Neg())
+}
+
+/*
+LedgerEntry is one balanced movement of funds: for every asset its lines add up to zero. Entries are never
+changed once posted, a movement is undone by posting its opposite. Reference ties the entry to what caused it
+("order 12", "trade BTC-USDT/7").
+*/
+type LedgerEntry struct {
+	ID        uint64       `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Kind      EntryKind    `json:"kind"`
+	Reference string       `json:"reference" gorm:"index"`
+	CreatedAt time.Time    `json:"created_at"`
+	Lines     []LedgerLine `json:"lines" gorm:"foreignKey:EntryID"`
+}
+
+// NOTE: LedgerBalance is the total of one account in one asset, what a snapshot keeps of the ledger.
+type LedgerBalance struct {
+	Owner   string      `json:"owner,omitempty"`
+	Account AccountKind `json:"account"`
+	Asset   string      `json:"asset"`
+	Amount  Decimal     `json:"amount"`
+}
+
+type LedgerState struct {
+	NextEntryID uint64          `json:"next_entry_id"`
+	Balances    []LedgerBalance `json:"balances"`
+}
+
+type ledgerKey struct {
+	account Account
+	asset   string
+}
+
+/*
+Ledger is the double-entry book of every fund movement. It keeps the running total of every account, the
+entries themselves go to the entry listener (the server stores them) as they're posted.
+
+Entries posted by a journaled command take their ID from its LSN (see entryIDs), so a live run and a replay of the
+journal give every entry the same ID however the markets interleaved. Others are numbered in the order they're posted.
+*/
+type Ledger struct {
+	mu      sync.Mutex
+	totals  map[ledgerKey]Decimal
+	nextID  uint64
+	onEntry func(LedgerEntry)
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{totals: make(map[ledgerKey]Decimal)}
+}
+
+// NOTE: SetEntryListener registers fn to get every entry after it's posted. fn runs under the ledger's lock, it mustn't block.
+func (l *Ledger) SetEntryListener(fn func(LedgerEntry)) {
+	l.mu.Lock()
+	l.onEntry = fn
+	l.mu.Unlock()
+}
+
+// NOTE: entriesPerCommand bounds the entries one command may post, a sweep of 8 million makers is well past it.
+const entriesPerCommand = 1 << 24
+
+// NOTE: entryIDs numbers the entries of the command being applied: entry n of the command at lsn is lsn<<24 | n.
+type entryIDs struct {
+	lsn    uint64
+	posted uint64
+}
+
+// NOTE: next is the ID of the command's next entry, 0 (the ledger numbers it) for a command that wasn't journaled.
+func (ids *entryIDs) next() uint64 {
+	if ids == nil || ids.lsn == 0 {
+		return 0
+	}
+	ids.posted++
+	return ids.lsn*entriesPerCommand + ids.posted
+}
+
+// NOTE: Post checks lines balance per asset and books them as one entry. Zero lines are dropped.
+func (l *Ledger) Post(kind EntryKind, reference string, lines ...LedgerLine) (LedgerEntry, error) {
+	return l.post(0, kind, reference, lines...)
+}
+
+// NOTE: post is Post for an entry that has its ID already, 0 for the next one in posting order.
+func (l *Ledger) post(id uint64, kind EntryKind, reference string, lines ...LedgerLine) (LedgerEntry, error) {
+	entry := LedgerEntry{ID: id, Kind: kind, Reference: reference}
+	sums := make(map[string]Decimal)
+	for _, line := range lines {
+		if line.Amount.IsZero() {
+			continue
+		}
+		line.Asset = strings.ToUpper(line.Asset)
+		sums[line.Asset] = sums[line.Asset].Add(line.Amount)
+		entry.Lines = append(entry.Lines, line)
+	}
+	for asset, sum := range sums {
+		if !sum.IsZero() {
+			return LedgerEntry{}, fmt.Errorf("%s entry for %s is off by %s %s: %w", kind, reference, sum, asset, ErrUnbalanced)
+		}
+	}
+	if len(entry.Lines) == 0 {
+		return entry, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// NOTE: totals are worked out before anything is booked, an entry taking one out of range is refused whole
+	totals := make(map[ledgerKey]Decimal)
+	for _, line := range entry.Lines {
+		key := ledgerKey{account: Account{Owner: line.Owner, Kind: line.Account}, asset: line.Asset}
+		total, ok := totals[key]
+		if !ok {
+			total = l.totals[key]
+		}
+		total, err := total.CheckedAdd(line.Amount)
+		if err != nil {
+			return LedgerEntry{}, fmt.Errorf("%s entry for %s: %w", kind, reference, err)
+		}
+		totals[key] = total
+	}
+	if entry.ID == 0 {
+		l.nextID++
+		entry.ID = l.nextID
+	}
+	for i := range entry.Lines {
+		entry.Lines[i].EntryID, entry.Lines[i].Position = entry.ID, i+1
+	}
+	for key, total := range totals {
+		l.totals[key] = total
+	}
+	if l.onEntry != nil {
+		l.onEntry(entry)
+	}
+	return entry, nil
+}
+
+func (l *Ledger) Total(account Account, asset string) Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.totals[ledgerKey{account: account, asset: strings.ToUpper(asset)}]
+}
+
+// NOTE: Balances returns every account total that isn't zero, by asset, owner and account.
+func (l *Ledger) Balances() []LedgerBalance {
+	l.mu.Lock()
+	var balances []LedgerBalance
+	for key, amount := range l.totals {
+		if !amount.IsZero() {
+			balances = append(balances, LedgerBalance{Owner: key.account.Owner, Account: key.account.Kind, Asset: key.asset, Amount: amount})
+		}
+	}
+	l.mu.Unlock()
+	sort.Slice(balances, func(i, j int) bool {
+		a, b := balances[i], balances[j]
+		if a.Asset != b.Asset {
+			return a.Asset < b.Asset
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Account < b.Account
+	})
+	return balances
+}
+
+// NOTE: Reconcile proves the books balance: every asset's accounts add up to zero. It returns each asset's
+// total in custody, what the users and the house hold between them.
+func (l *Ledger) Reconcile() (map[string]Decimal, error) {
+	return ReconcileBalances(l.Balances())
+}
+
+// NOTE: ReconcileBalances is Reconcile over account totals from elsewhere, like the stored ledger lines.
+func ReconcileBalances(balances []LedgerBalance) (map[string]Decimal, error) {
+	sums, custody := make(map[string]Decimal), make(map[string]Decimal)
+	for _, balance := range balances {
+		sums[balance.Asset] = sums[balance.Asset].Add(balance.Amount)
+		if balance.Account == AccountCustody {
+			custody[balance.Asset] = custody[balance.Asset].Add(balance.Amount.Neg())
+		}
+	}
+	var unbalanced []string
+	for asset, sum := range sums {
+		if !sum.IsZero() {
+			unbalanced = append(unbalanced, fmt.Sprintf("%s is off by %s", asset, sum))
+		}
+	}
+	if len(unbalanced) > 0 {
+		sort.Strings(unbalanced)
+		return custody, fmt.Errorf("%s: %w", strings.Join(unbalanced, ", "), ErrUnbalanced)
+	}
+	return custody, nil
+}
+
+/*
+ReconcileStored checks the ledger against account totals kept elsewhere, the stored ledger lines summed by owner,
+account and asset: they have to balance on their own and match the ledger's totals account by account. It returns
+each asset's total in custody.
+*/
+func (l *Ledger) ReconcileStored(stored []LedgerBalance) (map[string]Decimal, error) {
+	custody, err := ReconcileBalances(stored)
+	if err != nil {
+		return custody, fmt.Errorf("stored ledger: %w", err)
+	}
+	totals := make(map[ledgerKey]Decimal)
+	for _, balance := range stored {
+		key := ledgerKey{account: Account{Owner: balance.Owner, Kind: balance.Account}, asset: strings.ToUpper(balance.Asset)}
+		totals[key] = totals[key].Add(balance.Amount)
+	}
+	var differ []string
+	for _, balance := range l.Balances() {
+		key := ledgerKey{account: Account{Owner: balance.Owner, Kind: balance.Account}, asset: balance.Asset}
+		if stored_amount := totals[key]; stored_amount != balance.Amount {
+			differ = append(differ, fmt.Sprintf("%s %s %s is %s, stored %s", balance.Owner, balance.Account, balance.Asset, balance.Amount, stored_amount))
+		}
+		delete(totals, key)
+	}
+	for key, amount := range totals {
+		if !amount.IsZero() {
+			differ = append(differ, fmt.Sprintf("%s %s %s is 0, stored %s", key.account.Owner, key.account.Kind, key.asset, amount))
+		}
+	}
+	if len(differ) > 0 {
+		sort.Strings(differ)
+		return custody, fmt.Errorf("%s: %w", strings.Join(differ, ", "), ErrLedgerMismatch)
+	}
+	return custody, nil
+}
+
+func (l *Ledger) State() LedgerState {
+	balances := l.Balances()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LedgerState{NextEntryID: l.nextID, Balances: balances}
+}
+
+// NOTE: Restore replaces the account totals with those of state, which has to balance.
+func (l *Ledger) Restore(state LedgerState) error {
+	if _, err := ReconcileBalances(state.Balances); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.totals = make(map[ledgerKey]Decimal)
+	for _, balance := range state.Balances {
+		key := ledgerKey{account: Account{Owner: balance.Owner, Kind: balance.Account}, asset: strings.ToUpper(balance.Asset)}
+		l.totals[key] = l.totals[key].Add(balance.Amount)
+	}
+	l.nextID = state.NextEntryID
+	return nil
+}
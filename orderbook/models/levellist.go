@@ -0,0 +1,102 @@
+package models
+
+import (
+	"iter"
+	"math/rand/v2"
+)
+
+const (
+	maxLevelHeight = 16 // NOTE: with a 1 in 4 chance to grow, enough for billions of levels
+	levelGrowOdds  = 4
+)
+
+type levelNode struct {
+	level *PriceLevel
+	next  []*levelNode
+}
+
+/*
+levelList is a skip list of the price levels of one side, best first, so adding or dropping a level costs
+O(log n) however deep the side is, and taking the best level (what matching does most) is O(1). The heights
+come from a generator seeded the same on every side, the layout doesn't change what the list holds anyway.
+*/
+type levelList struct {
+	head   levelNode
+	height int
+	len    int
+	better func(a, b Decimal) bool
+	rng    *rand.Rand
+}
+
+func newLevelList(better func(a, b Decimal) bool) *levelList {
+	return &levelList{
+		head:   levelNode{next: make([]*levelNode, maxLevelHeight)},
+		height: 1,
+		better: better,
+		rng:    rand.New(rand.NewPCG(1, 2)),
+	}
+}
+
+func (l *levelList) first() *PriceLevel {
+	if n := l.head.next[0]; n != nil {
+		return n.level
+	}
+	return nil
+}
+
+// NOTE: path fills update with the last node on each row that sits ahead of price.
+func (l *levelList) path(price Decimal, update []*levelNode) *levelNode {
+	n := &l.head
+	for row := l.height - 1; row >= 0; row-- {
+		for n.next[row] != nil && l.better(n.next[row].level.Price, price) {
+			n = n.next[row]
+		}
+		update[row] = n
+	}
+	return n.next[0]
+}
+
+// NOTE: insert adds lvl, whose price isn't in the list yet.
+func (l *levelList) insert(lvl *PriceLevel) {
+	var update [maxLevelHeight]*levelNode
+	l.path(lvl.Price, update[:])
+	height := 1
+	for height < maxLevelHeight && l.rng.IntN(levelGrowOdds) == 0 {
+		height++
+	}
+	for ; l.height < height; l.height++ {
+		update[l.height] = &l.head
+	}
+	node := &levelNode{level: lvl, next: make([]*levelNode, height)}
+	for row := 0; row < height; row++ {
+		node.next[row] = update[row].next[row]
+		update[row].next[row] = node
+	}
+	l.len++
+}
+
+func (l *levelList) delete(price Decimal) {
+	var update [maxLevelHeight]*levelNode
+	node := l.path(price, update[:])
+	if node == nil || node.level.Price != price {
+		return
+	}
+	for row := 0; row < len(node.next); row++ {
+		update[row].next[row] = node.next[row]
+	}
+	for l.height > 1 && l.head.next[l.height-1] == nil {
+		l.height--
+	}
+	l.len--
+}
+
+// NOTE: all yields the levels best first, the list mustn't change while it's walked.
+func (l *levelList) all() iter.Seq[*PriceLevel] {
+	return func(yield func(*PriceLevel) bool) {
+		for n := l.head.next[0]; n != nil; n = n.next[0] {
+			if !yield(n.level) {
+				return
+			}
+		}
+	}
+}
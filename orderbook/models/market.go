@@ -0,0 +1,545 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMarketNotFound   = errors.New("market not found")
+	ErrMarketNotTrading = errors.New("market is not accepting this request")
+)
+
+type MarketStatus int
+
+const (
+	MarketTrading MarketStatus = iota
+	MarketHalted               // no new orders, cancels still accepted
+	MarketClosed               // nothing accepted
+)
+
+func (status MarketStatus) String() string {
+	switch status {
+	case MarketTrading:
+		return "Trading"
+	case MarketHalted:
+		return "Halted"
+	case MarketClosed:
+		return "Closed"
+	default:
+		return "Unknown"
+	}
+}
+
+// NOTE: Asset is a coin or currency, Scale is how many decimals an amount of it may have (BTC 8, USDT 6).
+type Asset struct {
+	Code  string `json:"code"`
+	Scale uint8  `json:"scale"`
+
+	WithdrawalLimit   Decimal `json:"withdrawal_limit"`   // NOTE: most one owner may withdraw in 24 hours, zero for no limit
+	ApprovalThreshold Decimal `json:"approval_threshold"` // NOTE: withdrawals above it wait for an admin, zero for none
+}
+
+/*
+Market is one trading pair (BTC-USDT: base BTC, quote USDT) with its own order book.
+  - TickSize: prices (limit, stop, protection) must be a multiple of it.
+  - LotSize: quantities (total and iceberg display) must be a multiple of it.
+  - MinNotional: price * quantity of a priced order must reach it.
+  - Fees: the fee tiers by 30-day volume (see FeeTier), trading is free without any.
+  - SessionClose: the time of day ("16:30", UTC) at which DAY orders expire, 00:00 when it's empty.
+
+Prices and notionals are amounts of the quote asset and use its scale, quantities use the base asset's scale.
+*/
+type Market struct {
+	Symbol       string       `json:"symbol"`
+	BaseAsset    string       `json:"base_asset"`
+	QuoteAsset   string       `json:"quote_asset"`
+	TickSize     Decimal      `json:"tick_size"`
+	LotSize      Decimal      `json:"lot_size"`
+	MinNotional  Decimal      `json:"min_notional"`
+	Fees         []FeeTier    `json:"fees"`
+	SessionClose string       `json:"session_close"`
+	Status       MarketStatus `json:"status"`
+	Book         *Orderbook   `json:"-"`
+	Base         Asset        `json:"-"`
+	Quote        Asset        `json:"-"`
+
+	volumes map[string][]DailyVolume // NOTE: by owner, oldest day first
+	entries entryIDs                 // NOTE: numbers the ledger entries of the command being applied
+}
+
+func (m *Market) validate() error {
+	if m.BaseAsset == "" || m.QuoteAsset == "" {
+		return fmt.Errorf("market needs a base and a quote asset")
+	}
+	if m.BaseAsset == m.QuoteAsset {
+		return fmt.Errorf("base and quote asset must differ")
+	}
+	if m.Symbol != m.BaseAsset+"-"+m.QuoteAsset {
+		return fmt.Errorf("symbol %q must be %s-%s", m.Symbol, m.BaseAsset, m.QuoteAsset)
+	}
+	if !m.TickSize.IsPositive() || !m.LotSize.IsPositive() {
+		return fmt.Errorf("tick size and lot size must be greater than zero")
+	}
+	if m.MinNotional.IsNegative() {
+		return fmt.Errorf("min notional can't be negative")
+	}
+	if !m.TickSize.FitsScale(m.Quote.Scale) || !m.MinNotional.FitsScale(m.Quote.Scale) {
+		return fmt.Errorf("tick size and min notional can have at most %d decimals (%s)", m.Quote.Scale, m.Quote.Code)
+	}
+	if !m.LotSize.FitsScale(m.Base.Scale) {
+		return fmt.Errorf("lot size can have at most %d decimals (%s)", m.Base.Scale, m.Base.Code)
+	}
+	return nil
+}
+
+// NOTE: Notional is price * quantity in the quote asset, rounded down to the quote asset's scale.
+func (m *Market) Notional(price, quantity Decimal) (Decimal, error) {
+	return price.MulRound(quantity, m.Quote.Scale, RoundDown)
+}
+
+// NOTE: validateOrder checks the order against the market's trading rules.
+func (m *Market) validateOrder(order *Order, status MarketStatus) error {
+	if status != MarketTrading {
+		return fmt.Errorf("market %s is %s: %w", m.Symbol, status, ErrMarketNotTrading)
+	}
+	for _, price := range []Decimal{order.Price, order.StopPrice, order.ProtectionPrice} {
+		if !price.IsMultipleOf(m.TickSize) {
+			return fmt.Errorf("price %s is not a multiple of the tick size %s", price, m.TickSize)
+		}
+	}
+	return m.validateQuantity(order.Price, order.Quantity, order.DisplayQuantity)
+}
+
+func (m *Market) validateQuantity(price, quantity, display Decimal) error {
+	if !quantity.IsMultipleOf(m.LotSize) || !display.IsMultipleOf(m.LotSize) {
+		return fmt.Errorf("quantity %s is not a multiple of the lot size %s", quantity, m.LotSize)
+	}
+	if price.IsPositive() {
+		notional, err := m.Notional(price, quantity)
+		if err != nil {
+			return fmt.Errorf("order notional: %v", err)
+		}
+		if notional.LessThan(m.MinNotional) {
+			return fmt.Errorf("order notional %s is below the minimum of %s", notional, m.MinNotional)
+		}
+	}
+	return nil
+}
+
+/*
+MarketRegistry is every market listed on the deployment, keyed by symbol.
+Order IDs are handed out here rather than by each book so they stay unique across markets.
+The registry map is safe for concurrent use, a market's book is not: callers serialise access per book.
+*/
+type MarketRegistry struct {
+	mu          sync.RWMutex
+	assets      map[string]Asset
+	markets     map[string]*Market
+	nextOrderID uint
+	onUpdate    func(OrderEvent)
+	balances    *Balances
+	executions  *ExecutionHub
+	selfTrade   map[string]SelfTradePrevention // NOTE: account settings by owner, see SetSelfTradePrevention
+	accountIDs  entryIDs                       // NOTE: entryIDs of the account command being applied
+	openOrders  map[uint]string                // NOTE: the market of every open order by ID, see OrderSymbol
+	approvers   map[string]bool                // NOTE: who may approve withdrawals, nil until SetApprovers
+}
+
+func NewMarketRegistry() *MarketRegistry {
+	return &MarketRegistry{assets: make(map[string]Asset), markets: make(map[string]*Market), selfTrade: make(map[string]SelfTradePrevention), openOrders: make(map[uint]string)}
+}
+
+func (r *MarketRegistry) RegisterAsset(asset Asset) error {
+	asset.Code = strings.ToUpper(asset.Code)
+	if asset.Code == "" {
+		return fmt.Errorf("asset code is required")
+	}
+	if asset.Scale > MaxDecimalScale {
+		return fmt.Errorf("asset %s: scale can be at most %d", asset.Code, MaxDecimalScale)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.assets[asset.Code]; ok {
+		return fmt.Errorf("asset %s already exists", asset.Code)
+	}
+	r.assets[asset.Code] = asset
+	return nil
+}
+
+func (r *MarketRegistry) Asset(code string) (Asset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	asset, ok := r.assets[strings.ToUpper(code)]
+	if !ok {
+		return Asset{}, fmt.Errorf("asset %s not found", code)
+	}
+	return asset, nil
+}
+
+// NOTE: Register lists a new market with an empty book, both of its assets must be registered first.
+func (r *MarketRegistry) Register(m Market) (*Market, error) {
+	m.Symbol = strings.ToUpper(m.Symbol)
+	m.BaseAsset = strings.ToUpper(m.BaseAsset)
+	m.QuoteAsset = strings.ToUpper(m.QuoteAsset)
+	var err error
+	if m.Base, err = r.Asset(m.BaseAsset); err != nil {
+		return nil, err
+	}
+	if m.Quote, err = r.Asset(m.QuoteAsset); err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if err := m.validateFees(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.markets[m.Symbol]; ok {
+		return nil, fmt.Errorf("market %s already exists", m.Symbol)
+	}
+	m.Book = NewOrderbook()
+	m.Book.Symbol = m.Symbol
+	if err := m.Book.SetTickSize(m.TickSize); err != nil {
+		return nil, err
+	}
+	if m.SessionClose != "" {
+		var hour, minute int
+		if _, err := fmt.Sscanf(m.SessionClose, "%d:%d", &hour, &minute); err != nil {
+			return nil, fmt.Errorf("session close %q must be HH:MM", m.SessionClose)
+		}
+		if err := m.Book.SetSessionClose(hour, minute); err != nil {
+			return nil, err
+		}
+	}
+	listed := &m
+	listed.volumes = make(map[string][]DailyVolume)
+	listed.Book.SetOrderListener(func(event OrderEvent) { r.handle(listed, event) })
+	listed.Book.SetFeeFunc(listed.chargeFees)
+	r.markets[m.Symbol] = listed
+	return listed, nil
+}
+
+func (r *MarketRegistry) Market(symbol string) (*Market, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.markets[strings.ToUpper(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", symbol, ErrMarketNotFound)
+	}
+	return m, nil
+}
+
+// NOTE: Markets returns every listed market sorted by symbol.
+func (r *MarketRegistry) Markets() []*Market {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	markets := make([]*Market, 0, len(r.markets))
+	for _, m := range r.markets {
+		markets = append(markets, m)
+	}
+	sort.Slice(markets, func(i, j int) bool { return markets[i].Symbol < markets[j].Symbol })
+	return markets
+}
+
+func (r *MarketRegistry) SetStatus(symbol string, status MarketStatus) error {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m.Status = status
+	return nil
+}
+
+func (r *MarketRegistry) statusOf(m *Market) MarketStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return m.Status
+}
+
+// NOTE: NewOrderID reserves the next order ID, for callers that must know it before the order reaches a book.
+func (r *MarketRegistry) NewOrderID() uint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextOrderID++
+	return r.nextOrderID
+}
+
+// NOTE: seenOrderID keeps IDs given out before (a replayed order carries its ID) from being handed out again.
+func (r *MarketRegistry) seenOrderID(id uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id > r.nextOrderID {
+		r.nextOrderID = id
+	}
+}
+
+// NOTE: AddOrder routes the order to the book of order.Symbol after checking the market's rules.
+func (r *MarketRegistry) AddOrder(order *Order) ([]Trade, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+	m, err := r.Market(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if order.ID != 0 {
+		r.seenOrderID(order.ID)
+	}
+	if err := m.validateOrder(order, r.statusOf(m)); err != nil {
+		return nil, err
+	}
+	order.Symbol = m.Symbol
+	if order.ID == 0 {
+		order.ID = r.NewOrderID()
+	}
+	m.pinFees(order)
+	if err := r.holdFunds(m, order); err != nil {
+		return nil, err
+	}
+	trades, err := m.Book.AddOrder(order)
+	if err != nil {
+		r.releaseFunds(m, order)
+	}
+	return trades, err
+}
+
+func (r *MarketRegistry) CancelOrder(symbol string, id uint) (*Order, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if status := r.statusOf(m); status == MarketClosed {
+		return nil, fmt.Errorf("market %s is %s: %w", m.Symbol, status, ErrMarketNotTrading)
+	}
+	return m.Book.CancelOrder(id)
+}
+
+func (r *MarketRegistry) AmendOrder(symbol string, id uint, newPrice, newQty Decimal) ([]Trade, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if status := r.statusOf(m); status != MarketTrading {
+		return nil, fmt.Errorf("market %s is %s: %w", m.Symbol, status, ErrMarketNotTrading)
+	}
+	order, ok := m.Book.GetOrder(id)
+	if !ok {
+		return nil, fmt.Errorf("order %d in %s: %w", id, m.Symbol, ErrOrderNotFound)
+	}
+	// NOTE: refused before any funds move, the book would refuse it after rehold
+	if order.expiredBy(m.Book.now()) {
+		return nil, fmt.Errorf("order %d in %s %w", id, m.Symbol, ErrOrderExpired)
+	}
+	if !newPrice.IsMultipleOf(m.TickSize) {
+		return nil, fmt.Errorf("price %s is not a multiple of the tick size %s", newPrice, m.TickSize)
+	}
+	if err := m.validateQuantity(newPrice, newQty, order.DisplayQuantity); err != nil {
+		return nil, err
+	}
+	amended := *order
+	amended.Quantity = newQty
+	if err := validateIceberg(&amended); err != nil {
+		return nil, err
+	}
+	asset := m.QuoteAsset
+	if order.Side == Sell {
+		asset = m.BaseAsset
+	}
+	delta, err := r.rehold(m, order, newPrice, newQty)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := m.Book.AmendOrder(id, newPrice, newQty)
+	if err != nil && !delta.IsZero() {
+		r.balances.lock(&m.entries, orderReference(id), order.OwnerUsername, asset, delta.Neg())
+		order.Held = order.Held.Sub(delta)
+	}
+	return trades, err
+}
+
+/*
+BeginCommand is called right before the command at lsn of symbol's market (account commands have no symbol) is
+applied, so the ledger entries it posts are numbered after it. A market's commands apply on its own goroutine
+and account commands one at a time, neither needs a lock.
+*/
+func (r *MarketRegistry) BeginCommand(symbol string, lsn uint64) {
+	if symbol == "" {
+		r.accountIDs = entryIDs{lsn: lsn}
+		return
+	}
+	if m, err := r.Market(symbol); err == nil {
+		m.entries = entryIDs{lsn: lsn}
+	}
+}
+
+// NOTE: ExpireOrders expires symbol's orders due at now (see Orderbook.ExpireOrders), whatever the market's status.
+func (r *MarketRegistry) ExpireOrders(symbol string, now time.Time) ([]*Order, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return m.Book.ExpireOrders(now), nil
+}
+
+func (r *MarketRegistry) GetOrder(symbol string, id uint) (*Order, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	order, ok := m.Book.GetOrder(id)
+	if !ok {
+		return nil, fmt.Errorf("order %d in %s: %w", id, m.Symbol, ErrOrderNotFound)
+	}
+	return order, nil
+}
+
+// NOTE: SetOrderListener registers fn for the orders of every market, current and future (see Orderbook.SetOrderListener).
+func (r *MarketRegistry) SetOrderListener(fn func(OrderEvent)) {
+	r.mu.Lock()
+	r.onUpdate = fn
+	r.mu.Unlock()
+}
+
+/*
+handle is the listener of every book: funds move first, so the execution report and fn see the order holding what
+it holds now.
+*/
+func (r *MarketRegistry) handle(m *Market, event OrderEvent) {
+	m.countVolume(event)
+	r.settleFunds(m, event)
+	r.mu.Lock()
+	if event.Order.isDone() {
+		delete(r.openOrders, event.Order.ID)
+	} else {
+		r.openOrders[event.Order.ID] = m.Symbol
+	}
+	fn, executions := r.onUpdate, r.executions
+	r.mu.Unlock()
+	if executions != nil {
+		executions.publish(event, m.Book.now())
+	}
+	if fn != nil {
+		fn(event)
+	}
+}
+
+/*
+SetExecutions makes the registry report every order event to hub. The reports are part of the registry's state
+and are published on replay as well, so a restart carries on the owners' sequences and what they can resume
+from. Set it before the first order and before Restore.
+*/
+func (r *MarketRegistry) SetExecutions(hub *ExecutionHub) {
+	r.mu.Lock()
+	r.executions = hub
+	r.mu.Unlock()
+}
+
+func (r *MarketRegistry) Executions() *ExecutionHub {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.executions
+}
+
+/*
+OrderSymbol returns the market of the open order id without touching any book, so it's safe next to the engine's
+market goroutines. The index follows the books' order events: an order is in it from its acceptance until it's
+filled, cancelled, expired or rejected.
+*/
+func (r *MarketRegistry) OrderSymbol(id uint) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	symbol, ok := r.openOrders[id]
+	return symbol, ok
+}
+
+// NOTE: OpenOrders returns owner's open orders in one market, or in every market when symbol is empty.
+func (r *MarketRegistry) OpenOrders(owner, symbol string) ([]*Order, error) {
+	if symbol != "" {
+		m, err := r.Market(symbol)
+		if err != nil {
+			return nil, err
+		}
+		return m.Book.OpenOrders(owner), nil
+	}
+	var orders []*Order
+	for _, m := range r.Markets() {
+		orders = append(orders, m.Book.OpenOrders(owner)...)
+	}
+	return orders, nil
+}
+
+// NOTE: FindOrder looks an open order up by ID alone in the book OrderSymbol names, IDs being unique across markets.
+func (r *MarketRegistry) FindOrder(id uint) (*Order, bool) {
+	symbol, ok := r.OrderSymbol(id)
+	if !ok {
+		return nil, false
+	}
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, false
+	}
+	return m.Book.GetOrder(id)
+}
+
+func (r *MarketRegistry) RecentTrades(symbol string, n int) ([]Trade, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return m.Book.RecentTrades(n), nil
+}
+
+func (r *MarketRegistry) Depth(symbol string, side OrderSide, n int) ([]DepthLevel, error) {
+	m, err := r.Market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return m.Book.Depth(side, n), nil
+}
+
+// NOTE: MarketsConfig is the assets and markets listed on a deployment.
+type MarketsConfig struct {
+	Assets  []Asset  `json:"assets"`
+	Markets []Market `json:"markets"`
+}
+
+// NOTE: LoadMarkets reads the assets and markets to register from a JSON file.
+func LoadMarkets(path string) (MarketsConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return MarketsConfig{}, fmt.Errorf("error in opening markets file: %v", err)
+	}
+	defer file.Close()
+
+	var conf MarketsConfig
+	if err := json.NewDecoder(file).Decode(&conf); err != nil {
+		return MarketsConfig{}, fmt.Errorf("error in decoding markets file: %v", err)
+	}
+	return conf, nil
+}
+
+// NOTE: Load registers every asset and then every market of conf.
+func (r *MarketRegistry) Load(conf MarketsConfig) error {
+	for _, asset := range conf.Assets {
+		if err := r.RegisterAsset(asset); err != nil {
+			return err
+		}
+	}
+	for _, m := range conf.Markets {
+		if _, err := r.Register(m); err != nil {
+			return fmt.Errorf("failed to register market %s: %v", m.Symbol, err)
+		}
+	}
+	return nil
+}
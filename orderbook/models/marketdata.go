@@ -0,0 +1,172 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NOTE: LevelUpdate is the new state of one price level, a zero Quantity means the level is gone.
+type LevelUpdate struct {
+	Side  OrderSide  `json:"side"`
+	Level DepthLevel `json:"level"`
+}
+
+// NOTE: TrackLevelChanges makes the book remember which levels changed, for LevelChanges to report.
+func (ob *Orderbook) TrackLevelChanges() {
+	for _, bs := range []*bookSide{ob.bidOrders, ob.askOrders} {
+		if bs.changed == nil {
+			bs.changed = make(map[Decimal]struct{})
+		}
+	}
+}
+
+// NOTE: LevelChanges returns the current state of every level changed since the last call (bids then asks,
+// best price first) and forgets them. It's always empty unless TrackLevelChanges was called.
+func (ob *Orderbook) LevelChanges() []LevelUpdate {
+	var updates []LevelUpdate
+	for _, bs := range []*bookSide{ob.bidOrders, ob.askOrders} {
+		prices := make([]Decimal, 0, len(bs.changed))
+		for price := range bs.changed {
+			prices = append(prices, price)
+			delete(bs.changed, price)
+		}
+		sort.Slice(prices, func(i, j int) bool { return bs.better(prices[i], prices[j]) })
+		for _, price := range prices {
+			updates = append(updates, LevelUpdate{Side: bs.side, Level: ob.DepthAt(bs.side, price)})
+		}
+	}
+	return updates
+}
+
+type MarketDataKind int
+
+const (
+	MarketDataSnapshot MarketDataKind = iota
+	MarketDataLevel
+	MarketDataTrade
+)
+
+/*
+MarketDataEvent is one message of a market's public feed, depending on Kind:
+  - MarketDataSnapshot: Bids and Asks, the book as of Sequence. Always the first event of a subscription.
+  - MarketDataLevel: Update, a level that changed.
+  - MarketDataTrade: Trade, a trade print.
+
+Sequence numbers are per market and increase by exactly one from event to event, a subscriber that sees a gap
+has missed something and should subscribe again.
+*/
+type MarketDataEvent struct {
+	Sequence uint64         `json:"sequence"`
+	Symbol   string         `json:"symbol"`
+	Kind     MarketDataKind `json:"kind"`
+	Bids     []DepthLevel   `json:"bids,omitempty"`
+	Asks     []DepthLevel   `json:"asks,omitempty"`
+	Update   LevelUpdate    `json:"update"`
+	Trade    Trade          `json:"trade"`
+}
+
+/*
+MarketDataHub fans the public feed of every market out to its subscribers.
+A subscriber that doesn't keep up with its buffer is dropped (its channel closed) rather than slowing down
+matching or silently skipping events.
+The hub reads the books in Subscribe and Publish, callers hold whatever lock guards the book.
+*/
+type MarketDataHub struct {
+	mu     sync.Mutex
+	feeds  map[string]*marketFeed
+	buffer int
+}
+
+type marketFeed struct {
+	sequence    uint64
+	subscribers map[*MarketDataSubscription]struct{}
+}
+
+type MarketDataSubscription struct {
+	C      <-chan MarketDataEvent
+	events chan MarketDataEvent
+	hub    *MarketDataHub
+	symbol string
+}
+
+func NewMarketDataHub(buffer int) *MarketDataHub {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &MarketDataHub{feeds: make(map[string]*marketFeed), buffer: buffer}
+}
+
+func (h *MarketDataHub) feed(symbol string) *marketFeed {
+	feed, ok := h.feeds[symbol]
+	if !ok {
+		feed = &marketFeed{subscribers: make(map[*MarketDataSubscription]struct{})}
+		h.feeds[symbol] = feed
+	}
+	return feed
+}
+
+// NOTE: Subscribe starts a subscription to book's feed, the first event on C is a snapshot of up to depth levels a side.
+func (h *MarketDataHub) Subscribe(book *Orderbook, depth int) *MarketDataSubscription {
+	symbol := strings.ToUpper(book.Symbol)
+	events := make(chan MarketDataEvent, h.buffer+1)
+	sub := &MarketDataSubscription{C: events, events: events, hub: h, symbol: symbol}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	feed := h.feed(symbol)
+	events <- MarketDataEvent{
+		Sequence: feed.sequence,
+		Symbol:   symbol,
+		Kind:     MarketDataSnapshot,
+		Bids:     book.Depth(Buy, depth),
+		Asks:     book.Depth(Sell, depth),
+	}
+	feed.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// NOTE: Close ends the subscription, it's safe to call more than once and after the hub dropped it.
+func (sub *MarketDataSubscription) Close() {
+	sub.hub.mu.Lock()
+	defer sub.hub.mu.Unlock()
+	sub.hub.drop(sub)
+}
+
+func (h *MarketDataHub) drop(sub *MarketDataSubscription) {
+	feed := h.feed(sub.symbol)
+	if _, ok := feed.subscribers[sub]; ok {
+		delete(feed.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// NOTE: Publish sends the trades of one book operation followed by the level changes it left behind.
+func (h *MarketDataHub) Publish(book *Orderbook, trades []Trade) {
+	symbol := strings.ToUpper(book.Symbol)
+	var events []MarketDataEvent
+	for _, trade := range trades {
+		events = append(events, MarketDataEvent{Symbol: symbol, Kind: MarketDataTrade, Trade: trade})
+	}
+	for _, update := range book.LevelChanges() {
+		events = append(events, MarketDataEvent{Symbol: symbol, Kind: MarketDataLevel, Update: update})
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	feed := h.feed(symbol)
+	for _, event := range events {
+		feed.sequence++
+		event.Sequence = feed.sequence
+		for sub := range feed.subscribers {
+			select {
+			case sub.events <- event:
+			default:
+				h.drop(sub)
+			}
+		}
+	}
+}
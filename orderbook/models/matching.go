@@ -0,0 +1,291 @@
+package models
+
+import (
+	"container/heap"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// NOTE: Trade is a single execution between a resting (maker) order and an incoming (taker) order.
+// Sequence numbers the trades of one market from 1, fees are amounts of the quote asset (negative for a rebate).
+type Trade struct {
+	gorm.Model
+	Symbol        string    `json:"symbol" gorm:"uniqueIndex:idx_trade_symbol_sequence"`
+	Sequence      uint64    `json:"sequence" gorm:"uniqueIndex:idx_trade_symbol_sequence"`
+	MakerOrderID  uint      `json:"maker_order_id" gorm:"index"`
+	TakerOrderID  uint      `json:"taker_order_id" gorm:"index"`
+	MakerOwner    string    `json:"maker_owner" gorm:"index"`
+	TakerOwner    string    `json:"taker_owner" gorm:"index"`
+	Price         Decimal   `json:"price" gorm:"type:numeric(36,18)"`
+	Quantity      Decimal   `json:"quantity" gorm:"type:numeric(36,18)"`
+	AggressorSide OrderSide `json:"aggressor_side"`
+	MakerFee      Decimal   `json:"maker_fee" gorm:"type:numeric(36,18)"`
+	TakerFee      Decimal   `json:"taker_fee" gorm:"type:numeric(36,18)"`
+	Timestamp     uint32    `json:"timestamp"`
+}
+
+/*
+AddOrder runs the incoming order through the book with price-time priority:
+ 1. while the order has remaining quantity and the best opposite order crosses its limit price,
+    fill min(remaining, maker remaining) at the maker's price.
+ 2. fully filled makers leave their price level, partially filled makers keep their place in the queue
+    and an emptied level is dropped from the book. Only the visible slice of an iceberg maker trades,
+    once it is used up the next slice is shown at the back of the level.
+ 3. whatever is left of a limit order rests on its own side, what is left of a market order is cancelled.
+    TimeInForce decides the rest: IOC cancels the remainder, FOK never starts unless it can fill completely,
+    GTD and DAY rest until they expire: when they're swept (ExpireOrders) or met by a taker past their expiry.
+
+A post-only order that would cross is rejected (Status Rejected, RejectReason set) or repriced before matching.
+An order of an owner that meets their own resting order goes through its SelfTradePrevention instead of a fill.
+
+Stop orders are held in the trigger book instead and fire once the last traded price reaches their stop price.
+Every call returns the trades of the incoming order followed by those of the stops its fills triggered.
+
+A market order has no limit price of its own, it sweeps the opposite side until it is filled or the book runs dry,
+unless ProtectionPrice / MaxSlippageBps bound the worst price it may trade at.
+*/
+func (ob *Orderbook) AddOrder(order *Order) ([]Trade, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+	if !order.Quantity.IsPositive() || !order.Remaining().IsPositive() {
+		return nil, fmt.Errorf("order quantity must be greater than zero")
+	}
+	if order.Type < LimitOrder || order.Type > StopLimitOrder {
+		return nil, fmt.Errorf("unknown order type: %d", order.Type)
+	}
+	if !order.isMarket() && !order.Price.IsPositive() {
+		return nil, fmt.Errorf("order price must be greater than zero")
+	}
+	if order.ProtectionPrice.IsNegative() {
+		return nil, fmt.Errorf("protection price can't be negative")
+	}
+	if err := validateStop(order); err != nil {
+		return nil, err
+	}
+	if err := validateIceberg(order); err != nil {
+		return nil, err
+	}
+	if order.isMarket() && order.MaxSlippageBps > maxSlippageBps {
+		return nil, fmt.Errorf("max slippage must be at most %d bps", maxSlippageBps)
+	}
+	if err := ob.validateTimeInForce(order); err != nil {
+		return nil, err
+	}
+	if err := validatePostOnly(order); err != nil {
+		return nil, err
+	}
+	if err := validateSelfTradePrevention(order.SelfTradePrevention); err != nil {
+		return nil, err
+	}
+	if order.Side != Buy && order.Side != Sell {
+		return nil, fmt.Errorf("unknown order side: %d", order.Side)
+	}
+	if _, ok := ob.GetOrder(order.ID); ok && order.ID != 0 {
+		return nil, fmt.Errorf("order %d is already in the book", order.ID)
+	}
+	ob.assignID(order)
+	ob.stamp(order)
+	if order.Timestamp == 0 {
+		order.Timestamp = ob.now()
+	}
+	if order.TimeInForce == DAY {
+		order.ExpiresAt = ob.nextSessionClose()
+	}
+	ob.notify(OrderAccepted, order, nil)
+	if order.isStop() {
+		ob.holdStop(order)
+		return ob.fireStops(), nil
+	}
+	trades := ob.execute(order)
+	return append(trades, ob.fireStops()...), nil
+}
+
+// NOTE: execute takes an accepted (stamped) order through post-only, FOK and matching.
+func (ob *Orderbook) execute(order *Order) []Trade {
+	if order.PostOnly {
+		price, ok := ob.postOnlyPrice(order, order.Price)
+		if !ok {
+			order.reject(RejectPostOnlyWouldCross)
+			ob.notify(OrderRejected, order, nil)
+			return nil
+		}
+		order.Price = price
+	}
+	if order.TimeInForce == FOK && !ob.canFillCompletely(order) {
+		order.Status = StatusCancelled
+		ob.notify(OrderCancelled, order, nil)
+		return nil
+	}
+	return ob.match(order)
+}
+
+// NOTE: stamp hands out the arrival sequence that breaks ties between equal timestamps.
+func (ob *Orderbook) stamp(order *Order) {
+	ob.nextSequence++
+	order.Sequence = ob.nextSequence
+}
+
+func (ob *Orderbook) match(order *Order) []Trade {
+	opposite, own := ob.askOrders, ob.bidOrders
+	if order.Side == Sell {
+		opposite, own = ob.bidOrders, ob.askOrders
+	}
+
+	limit := ob.takerLimit(order, opposite)
+	var trades []Trade
+	for order.Remaining().IsPositive() {
+		lvl := opposite.best()
+		if lvl == nil || !crosses(order.Side, limit, lvl.Price) {
+			break
+		}
+		e := lvl.orders.Front()
+		maker := e.Value.(*Order)
+		if maker.expiredBy(ob.now()) {
+			// NOTE: a maker past its expiry that no sweep has taken out yet never trades
+			opposite.remove(lvl, e)
+			delete(ob.resting, maker.ID)
+			ob.expire(maker)
+			continue
+		}
+		if order.selfTrade(maker) {
+			if !ob.preventSelfTrade(order, opposite, lvl, e) {
+				break
+			}
+			continue
+		}
+		fill_qty := MinDecimal(order.Remaining(), maker.visible())
+		maker.fill(fill_qty)
+		lvl.TotalQuantity = lvl.TotalQuantity.Sub(fill_qty)
+		if maker.isIceberg() {
+			maker.VisibleQuantity = maker.VisibleQuantity.Sub(fill_qty)
+		}
+		order.fill(fill_qty)
+		opposite.touch(lvl.Price)
+		ob.lastPrice = maker.Price
+		ob.nextTradeSequence++
+		trade := Trade{
+			Symbol:        ob.Symbol,
+			Sequence:      ob.nextTradeSequence,
+			MakerOrderID:  maker.ID,
+			TakerOrderID:  order.ID,
+			MakerOwner:    maker.OwnerUsername,
+			TakerOwner:    order.OwnerUsername,
+			Price:         maker.Price,
+			Quantity:      fill_qty,
+			AggressorSide: order.Side,
+			Timestamp:     ob.now(),
+		}
+		if ob.fees != nil {
+			ob.fees(maker, order, &trade)
+		}
+		trades = append(trades, trade)
+		ob.recordTrade(trade)
+		if maker.Remaining().IsZero() {
+			opposite.remove(lvl, e)
+			delete(ob.resting, maker.ID)
+		} else if maker.visible().IsZero() {
+			ob.replenish(lvl, e)
+		}
+		ob.notify(OrderFilled, maker, &trade)
+		ob.notify(OrderFilled, order, &trade)
+	}
+
+	if order.Remaining().IsPositive() && order.Status != StatusCancelled {
+		if order.isMarket() || order.TimeInForce == IOC || order.TimeInForce == FOK {
+			order.Status = StatusCancelled
+			ob.notify(OrderCancelled, order, nil)
+		} else {
+			ob.rest(own, order)
+		}
+	}
+	return trades
+}
+
+func (ob *Orderbook) rest(bs *bookSide, order *Order) {
+	order.showSlice()
+	e := bs.add(order)
+	ob.resting[order.ID] = &restingOrder{side: bs, level: bs.by_price[order.Price], elem: e}
+	if order.ExpiresAt > 0 {
+		heap.Push(ob.expiries, expiry{id: order.ID, at: order.ExpiresAt})
+	}
+}
+
+// NOTE: crosses reports whether a taker with the given limit is willing to trade at the maker's price.
+func crosses(side OrderSide, limit, maker_price Decimal) bool {
+	if side == Buy {
+		return limit.Cmp(maker_price) >= 0
+	}
+	return limit.Cmp(maker_price) <= 0
+}
+
+const maxSlippageBps = 10000
+
+/*
+takerLimit is the worst price the order may trade at.
+For a limit order it's the order price. For a market order it starts unbounded and is tightened by
+ProtectionPrice and by MaxSlippageBps measured from the best opposite price at arrival (rounded towards the
+safe side for the taker).
+*/
+func (ob *Orderbook) takerLimit(order *Order, opposite *bookSide) Decimal {
+	if !order.isMarket() {
+		return order.Price
+	}
+	limit := MaxDecimal
+	if order.Side == Sell {
+		limit = Zero
+	}
+	tighten := func(bound Decimal) {
+		if opposite.better(limit, bound) {
+			return
+		}
+		limit = bound
+	}
+	if order.ProtectionPrice.IsPositive() {
+		tighten(order.ProtectionPrice)
+	}
+	if best := opposite.best(); best != nil && order.MaxSlippageBps > 0 {
+		factor, rounding := NewDecimal(int64(10000+order.MaxSlippageBps), 4), RoundDown
+		if order.Side == Sell {
+			factor, rounding = NewDecimal(int64(10000-order.MaxSlippageBps), 4), RoundUp
+		}
+		if bound, err := best.Price.MulRound(factor, ob.tickSize.Scale(), rounding); err == nil {
+			tighten(bound)
+		}
+	}
+	return limit
+}
+
+// NOTE: orders coming from the API don't carry an ID yet, the book hands them out sequentially.
+func (ob *Orderbook) assignID(order *Order) {
+	if order.ID == 0 {
+		ob.nextOrderID++
+		order.ID = ob.nextOrderID
+	} else if order.ID > ob.nextOrderID {
+		ob.nextOrderID = order.ID
+	}
+}
+
+// NOTE: recentTradesKept is how many trades RecentTrades can return.
+const recentTradesKept = 100
+
+func (ob *Orderbook) recordTrade(trade Trade) {
+	if len(ob.recentTrades) == recentTradesKept {
+		copy(ob.recentTrades, ob.recentTrades[1:])
+		ob.recentTrades = ob.recentTrades[:recentTradesKept-1]
+	}
+	ob.recentTrades = append(ob.recentTrades, trade)
+}
+
+// NOTE: RecentTrades returns up to n of the latest trades of the book, newest first (n <= 0 means all kept).
+func (ob *Orderbook) RecentTrades(n int) []Trade {
+	if n <= 0 || n > len(ob.recentTrades) {
+		n = len(ob.recentTrades)
+	}
+	trades := make([]Trade, 0, n)
+	for i := len(ob.recentTrades) - 1; len(trades) < n; i-- {
+		trades = append(trades, ob.recentTrades[i])
+	}
+	return trades
+}
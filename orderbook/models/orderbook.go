@@ -1,9 +1,14 @@
 package models
 
 import (
-	_ "container/heap"
+	"container/list"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
@@ -27,56 +32,306 @@ func (order_side OrderSide) getString() string {
 	}
 }
 
-type Order struct {
-	gorm.Model
-	Price         uint      `json:"price" form:"price" validate:"required" gorm:"type:decimal(10,2)"`
-	Quantity      uint      `json:"quantity" form:"quantity" validate:"required" gorm:"type:decimal(10,2)"`
-	Side          OrderSide `json:"side" form:"side" validate:"required"`
-	Timestamp     uint32    `json:"timestamp" form:"timestamp" validate:"required"`
-	OwnerUsername string    `json:"owner_username" form:"owner_username" validate:"required"`
+type OrderType int
+
+const (
+	LimitOrder OrderType = iota
+	MarketOrder
+	StopMarketOrder
+	StopLimitOrder
+)
+
+func (order_type OrderType) String() string {
+	switch order_type {
+	case LimitOrder:
+		return "Limit"
+	case MarketOrder:
+		return "Market"
+	case StopMarketOrder:
+		return "StopMarket"
+	case StopLimitOrder:
+		return "StopLimit"
+	default:
+		return "Unknown"
+	}
 }
 
-type OrderHeap []*Order
+type OrderStatus int
 
-func (h OrderHeap) Len() int { return len(h) }
+const (
+	StatusNew OrderStatus = iota
+	StatusPartiallyFilled
+	StatusFilled
+	StatusCancelled
+	StatusExpired
+	StatusRejected
+)
 
-func (h OrderHeap) Less(i, j int) bool {
-	if h[i].Side.getString() == "Buy" {
-		return h[i].Price > h[j].Price // Max-heap for buy orders
+func (status OrderStatus) String() string {
+	switch status {
+	case StatusNew:
+		return "New"
+	case StatusPartiallyFilled:
+		return "PartiallyFilled"
+	case StatusFilled:
+		return "Filled"
+	case StatusCancelled:
+		return "Cancelled"
+	case StatusExpired:
+		return "Expired"
+	case StatusRejected:
+		return "Rejected"
+	default:
+		return "Unknown"
 	}
-	return h[i].Price < h[j].Price // Min-heap for sell orders
 }
 
-func (h OrderHeap) Swap(i, j int) {
-	h[i], h[j] = h[j], h[i]
+type Order struct {
+	gorm.Model
+	Price          Decimal     `json:"price" form:"price" validate:"required" gorm:"type:numeric(36,18)"`
+	Quantity       Decimal     `json:"quantity" form:"quantity" validate:"required" gorm:"type:numeric(36,18)"`
+	FilledQuantity Decimal     `json:"filled_quantity" gorm:"type:numeric(36,18)"`
+	Side           OrderSide   `json:"side" form:"side" validate:"required"`
+	Type           OrderType   `json:"type" form:"type"`
+	TimeInForce    TimeInForce `json:"time_in_force" form:"time_in_force"`
+	ExpiresAt      uint32      `json:"expires_at" form:"expires_at"`
+	Status         OrderStatus `json:"status"`
+	Timestamp      uint32      `json:"timestamp" form:"timestamp" validate:"required"`
+	Sequence       uint64      `json:"sequence"`
+	OwnerUsername  string      `json:"owner_username" form:"owner_username" validate:"required"`
+	Symbol         string      `json:"symbol" form:"symbol" validate:"required" gorm:"index"`
+
+	// NOTE: market orders only, both optional. ProtectionPrice is the worst price the sweep may reach,
+	// MaxSlippageBps bounds it relative to the best opposite price when the order arrives.
+	ProtectionPrice Decimal `json:"protection_price" form:"protection_price" gorm:"type:numeric(36,18)"`
+	MaxSlippageBps  uint    `json:"max_slippage_bps" form:"max_slippage_bps"`
+
+	// NOTE: a post-only order never takes liquidity, if it would cross it's rejected or, with
+	// PostOnlyReprice, moved one tick away from the best opposite price.
+	PostOnly        bool         `json:"post_only" form:"post_only"`
+	PostOnlyReprice bool         `json:"post_only_reprice" form:"post_only_reprice"`
+	RejectReason    RejectReason `json:"reject_reason"`
+
+	// NOTE: stop orders wait in the trigger book until the last traded price reaches StopPrice,
+	// then a stop-market order becomes a market order and a stop-limit order a limit order at Price.
+	StopPrice Decimal `json:"stop_price" form:"stop_price" gorm:"type:numeric(36,18)"`
+	Triggered bool    `json:"triggered"`
+
+	// NOTE: iceberg orders: only DisplayQuantity is shown at a time, VisibleQuantity is what is left of
+	// the current slice. When it runs out a new slice is shown at the back of the price level.
+	DisplayQuantity Decimal `json:"display_quantity" form:"display_quantity" gorm:"type:numeric(36,18)"`
+	VisibleQuantity Decimal `json:"-" gorm:"-"`
+
+	// NOTE: Held is what the order still has locked of its owner's funds: the base asset for a sell, the quote
+	// asset for a buy. It's zero when the registry doesn't keep balances.
+	Held Decimal `json:"held" gorm:"type:numeric(36,18)"`
+
+	// NOTE: the fee rates (bps) of its owner's tier when the order was placed, see FeeTier.
+	MakerFeeBps Decimal `json:"maker_fee_bps" gorm:"type:numeric(12,4)"`
+	TakerFeeBps Decimal `json:"taker_fee_bps" gorm:"type:numeric(12,4)"`
+
+	// NOTE: what happens when the order would trade with one of its owner's resting orders, see SelfTradePrevention.
+	SelfTradePrevention SelfTradePrevention `json:"self_trade_prevention" form:"self_trade_prevention"`
 }
 
-// NOTE: Push an Order: Add an order to the heap.
-func (h *OrderHeap) Push(x interface{}) {
-	*h = append(*h, x.(*Order)) // Add new order to the heap
+// NOTE: Remaining is the part of the order that has not been filled yet.
+func (order *Order) Remaining() Decimal {
+	return order.Quantity.Sub(order.FilledQuantity)
 }
 
-// NOTE: Pop the Top Order: Remove the highest-priority order from the heap.
-func (h *OrderHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	item := old[n-1]  // Get last item
-	*h = old[0 : n-1] // Reduce the heap size
-	return item       // Return the removed item
+// NOTE: fill records an execution of qty against the order and updates its status.
+func (order *Order) fill(qty Decimal) {
+	order.FilledQuantity = order.FilledQuantity.Add(qty)
+	if order.Remaining().IsZero() {
+		order.Status = StatusFilled
+	} else {
+		order.Status = StatusPartiallyFilled
+	}
 }
 
-// NOTE: matching orders using heap O(1)
-// NOTE: manipulating orders is in O(logn)
 type Orderbook struct {
 	gorm.Model
-	bidOrders *OrderHeap
-	askOrders *OrderHeap
+	Symbol       string `gorm:"uniqueIndex"`
+	bidOrders    *bookSide
+	askOrders    *bookSide
+	resting      map[uint]*restingOrder
+	stops        map[uint]*Order
+	buyStops     *stopHeap
+	sellStops    *stopHeap
+	lastPrice    Decimal
+	expiries     *expiryHeap
+	nextOrderID  uint
+	nextSequence uint64
+	sessionClose time.Duration
+	// NOTE: trades of this book get their own sequence, recentTrades is the tape of the last ones, oldest first.
+	nextTradeSequence uint64
+	recentTrades      []Trade
+	tickSize          Decimal
+	clock             func() time.Time
+	onUpdate          func(OrderEvent)
+	fees              func(maker, taker *Order, trade *Trade)
+}
+
+// NOTE: restingOrder is where an order sits in the book, so it can be found by ID in O(1).
+type restingOrder struct {
+	side  *bookSide
+	level *PriceLevel
+	elem  *list.Element
+}
+
+func (ro *restingOrder) order() *Order { return ro.elem.Value.(*Order) }
+
+func NewOrderbook() *Orderbook {
+	return &Orderbook{
+		bidOrders: newBookSide(Buy),
+		askOrders: newBookSide(Sell),
+		resting:   make(map[uint]*restingOrder),
+		stops:     make(map[uint]*Order),
+		buyStops:  &stopHeap{},
+		sellStops: &stopHeap{},
+		expiries:  &expiryHeap{},
+		tickSize:  NewDecimalFromInt(1),
+		clock:     time.Now, // NOTE: DAY orders expire at 00:00 UTC unless SetSessionClose says otherwise.
+	}
+}
+
+// NOTE: SetClock replaces the wall clock the book uses for timestamps and expiry (tests, replay).
+func (ob *Orderbook) SetClock(clock func() time.Time) {
+	ob.clock = clock
+}
+
+type OrderEventKind int
+
+const (
+	OrderAccepted OrderEventKind = iota
+	OrderFilled
+	OrderAmended
+	OrderTriggered
+	OrderCancelled
+	OrderExpired
+	OrderRejected
+)
+
+func (kind OrderEventKind) String() string {
+	switch kind {
+	case OrderAccepted:
+		return "Accepted"
+	case OrderFilled:
+		return "Filled"
+	case OrderAmended:
+		return "Amended"
+	case OrderTriggered:
+		return "Triggered"
+	case OrderCancelled:
+		return "Cancelled"
+	case OrderExpired:
+		return "Expired"
+	case OrderRejected:
+		return "Rejected"
+	default:
+		return "Unknown"
+	}
+}
+
+// NOTE: OrderEvent is one change of an order's state, Fill is set for OrderFilled only.
+type OrderEvent struct {
+	Kind  OrderEventKind
+	Order *Order
+	Fill  *Trade
+}
+
+/*
+SetOrderListener registers fn to be called every time an order changes state. It runs synchronously inside the
+book operation, right after the change: an incoming order is accepted before it trades, and each fill is reported
+to the maker then to the taker.
+*/
+func (ob *Orderbook) SetOrderListener(fn func(OrderEvent)) {
+	ob.onUpdate = fn
+}
+
+// NOTE: SetFeeFunc sets what prices the fees of each trade before anyone sees it. Without one trades are free.
+func (ob *Orderbook) SetFeeFunc(fn func(maker, taker *Order, trade *Trade)) {
+	ob.fees = fn
+}
+
+func (ob *Orderbook) notify(kind OrderEventKind, order *Order, fill *Trade) {
+	if ob.onUpdate != nil {
+		ob.onUpdate(OrderEvent{Kind: kind, Order: order, Fill: fill})
+	}
+}
+
+func (ob *Orderbook) now() uint32 {
+	return uint32(ob.clock().Unix())
+}
+
+// NOTE: GetOrder looks up a resting order, or a stop order waiting for its trigger, by its ID.
+func (ob *Orderbook) GetOrder(id uint) (*Order, bool) {
+	if stop, ok := ob.stops[id]; ok {
+		return stop, true
+	}
+	ro, ok := ob.resting[id]
+	if !ok {
+		return nil, false
+	}
+	return ro.order(), true
+}
+
+func (ob *Orderbook) sideOf(side OrderSide) *bookSide {
+	if side == Buy {
+		return ob.bidOrders
+	}
+	return ob.askOrders
+}
+
+// NOTE: BestBid returns the highest priority resting buy order without removing it.
+func (ob *Orderbook) BestBid() (*Order, bool) {
+	return ob.bidOrders.front()
+}
+
+// NOTE: BestAsk returns the highest priority resting sell order without removing it.
+func (ob *Orderbook) BestAsk() (*Order, bool) {
+	return ob.askOrders.front()
+}
+
+// NOTE: OpenOrders returns the resting orders and held stops of owner, oldest first.
+func (ob *Orderbook) OpenOrders(owner string) []*Order {
+	var orders []*Order
+	for _, ro := range ob.resting {
+		if ro.order().OwnerUsername == owner {
+			orders = append(orders, ro.order())
+		}
+	}
+	for _, stop := range ob.stops {
+		if stop.OwnerUsername == owner {
+			orders = append(orders, stop)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return orders
+}
+
+// NOTE: BidCount and AskCount return the number of resting orders on each side.
+func (ob *Orderbook) BidCount() int { return ob.bidOrders.orders }
+func (ob *Orderbook) AskCount() int { return ob.askOrders.orders }
+
+// NOTE: Depth returns up to n aggregated levels of one side, best price first (n <= 0 means all).
+func (ob *Orderbook) Depth(side OrderSide, n int) []DepthLevel {
+	return ob.sideOf(side).depth(n)
+}
+
+// NOTE: DepthAt returns the aggregated level resting at price, the zero quantity if there is none.
+func (ob *Orderbook) DepthAt(side OrderSide, price Decimal) DepthLevel {
+	lvl, ok := ob.sideOf(side).by_price[price]
+	if !ok {
+		return DepthLevel{Price: price}
+	}
+	return DepthLevel{Price: price, Quantity: lvl.TotalQuantity, OrderCount: lvl.OrderCount()}
 }
 
 /*
 OrderBook
- ├── BidOrders (max heap or map) → {Price → {Order1, Order2, ...}}  # Sorted by highest price first
- └── AskOrders (min heap or map) → {Price → {Order1, Order2, ...}}  # Sorted by lowest price first
+ ├── BidOrders (sorted price levels) → {Price → {Order1, Order2, ...}}  # Sorted by highest price first
+ └── AskOrders (sorted price levels) → {Price → {Order1, Order2, ...}}  # Sorted by lowest price first
 
 Order
  ├── ID: Unique identifier
@@ -87,19 +342,30 @@ Order
  └── OwnerID: ID of the user who placed the order
 */
 
+var ErrNoJWTSecret = errors.New("no JWT secret configured")
+
 type Config struct {
-	Host     string
-	Port     string
-	Password string
-	User     string
-	DBName   string
-	SSLMode  string
+	Host        string
+	Port        string
+	Password    string
+	User        string
+	DBName      string
+	SSLMode     string
+	MarketsFile string
+	JournalFile string
+	JWTSecret   string
+	Admins      []string
+	ListenAddr  string // NOTE: where the gRPC services are served, ":8080" unless LISTEN_ADDR_ORDERBOOK says otherwise
+
+	SnapshotDir      string
+	SnapshotInterval time.Duration
+	ExpiryInterval   time.Duration // NOTE: how often the engine looks for GTD/DAY orders to expire
 }
 
 func (conf *Config) ExtractDbConfig() (Config, error) {
-	err := godotenv.Load()
-	if err != nil {
-		return Config{}, fmt.Errorf("error occurred in opening .env")
+	// NOTE: without a .env the settings come from the environment alone
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("error occurred in opening .env: %v", err)
 	}
 	db_conf := Config{
 		Host:     os.Getenv("DB_HOST_ORDERBOOK"),
@@ -108,6 +374,49 @@ func (conf *Config) ExtractDbConfig() (Config, error) {
 		User:     os.Getenv("DB_USER_ORDERBOOK"),
 		DBName:   os.Getenv("DB_NAME_ORDERBOOK"),
 		SSLMode:  os.Getenv("DB_SSLMODE_ORDERBOOK"),
+
+		MarketsFile: os.Getenv("MARKETS_FILE_ORDERBOOK"),
+		JournalFile: os.Getenv("JOURNAL_FILE_ORDERBOOK"),
+		JWTSecret:   os.Getenv("JWT_SECRET"),
+		ListenAddr:  os.Getenv("LISTEN_ADDR_ORDERBOOK"),
+
+		SnapshotDir:      os.Getenv("SNAPSHOT_DIR_ORDERBOOK"),
+		SnapshotInterval: time.Minute,
+		ExpiryInterval:   time.Second,
+	}
+	if db_conf.MarketsFile == "" {
+		db_conf.MarketsFile = "config/markets.json"
+	}
+	if db_conf.JournalFile == "" {
+		db_conf.JournalFile = "data/orderbook.journal"
+	}
+	if db_conf.ListenAddr == "" {
+		db_conf.ListenAddr = ":8080"
+	}
+	if db_conf.SnapshotDir == "" {
+		db_conf.SnapshotDir = "data/snapshots"
+	}
+	if interval := os.Getenv("SNAPSHOT_INTERVAL_ORDERBOOK"); interval != "" {
+		parsed, err := time.ParseDuration(interval)
+		if err != nil || parsed <= 0 {
+			return Config{}, fmt.Errorf("invalid SNAPSHOT_INTERVAL_ORDERBOOK %q", interval)
+		}
+		db_conf.SnapshotInterval = parsed
+	}
+	if interval := os.Getenv("EXPIRY_INTERVAL_ORDERBOOK"); interval != "" {
+		parsed, err := time.ParseDuration(interval)
+		if err != nil || parsed <= 0 {
+			return Config{}, fmt.Errorf("invalid EXPIRY_INTERVAL_ORDERBOOK %q", interval)
+		}
+		db_conf.ExpiryInterval = parsed
+	}
+	for _, admin := range strings.Split(os.Getenv("ADMINS_ORDERBOOK"), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" {
+			db_conf.Admins = append(db_conf.Admins, admin)
+		}
+	}
+	if db_conf.JWTSecret == "" {
+		return Config{}, fmt.Errorf("JWT_SECRET, the key user_auth signs tokens with, is required: %w", ErrNoJWTSecret)
 	}
 
 	return db_conf, nil
@@ -117,9 +426,10 @@ type Database struct {
 	DB *gorm.DB
 }
 
-func (db *Database) getOrderById(encoded_to_order *Order, id int) error {
-	if err := db.DB.Where("ID == ?", id).First(&encoded_to_order).Error; err != nil {
-		return fmt.Errorf("error: %s", err.Error())
+// NOTE: GetOrderById loads a persisted order, the error wraps gorm.ErrRecordNotFound when there is none.
+func (db *Database) GetOrderById(encoded_to_order *Order, id uint) error {
+	if err := db.DB.Where("id = ?", id).First(encoded_to_order).Error; err != nil {
+		return fmt.Errorf("error: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,78 @@
+package models
+
+import "fmt"
+
+type RejectReason int
+
+const (
+	RejectNone RejectReason = iota
+	RejectPostOnlyWouldCross
+	RejectSelfTradePrevented // NOTE: also on orders self-trade prevention cancelled, see SelfTradePrevention
+)
+
+func (reason RejectReason) String() string {
+	switch reason {
+	case RejectNone:
+		return "None"
+	case RejectPostOnlyWouldCross:
+		return "PostOnlyWouldCross"
+	case RejectSelfTradePrevented:
+		return "SelfTradePrevented"
+	default:
+		return "Unknown"
+	}
+}
+
+// NOTE: reject marks an order the engine refused to accept, it never touches the book.
+func (order *Order) reject(reason RejectReason) {
+	order.Status = StatusRejected
+	order.RejectReason = reason
+}
+
+// NOTE: SetTickSize sets the minimum price increment, used to reprice post-only orders.
+func (ob *Orderbook) SetTickSize(tick Decimal) error {
+	if !tick.IsPositive() {
+		return fmt.Errorf("tick size must be greater than zero")
+	}
+	ob.tickSize = tick
+	return nil
+}
+
+func validatePostOnly(order *Order) error {
+	if !order.PostOnly {
+		return nil
+	}
+	if order.Type != LimitOrder {
+		return fmt.Errorf("%s orders can't be post-only", order.Type)
+	}
+	if order.TimeInForce == IOC || order.TimeInForce == FOK {
+		return fmt.Errorf("post-only orders can't be %s", order.TimeInForce)
+	}
+	return nil
+}
+
+/*
+postOnlyPrice decides what happens to a post-only order at price that would take liquidity.
+It returns the price the order can rest at (unchanged if it doesn't cross) and false if it has to be rejected:
+with PostOnlyReprice the order is moved one tick behind the best opposite price, otherwise it's rejected.
+*/
+func (ob *Orderbook) postOnlyPrice(order *Order, price Decimal) (Decimal, bool) {
+	opposite := ob.askOrders
+	if order.Side == Sell {
+		opposite = ob.bidOrders
+	}
+	best := opposite.best()
+	if best == nil || !crosses(order.Side, price, best.Price) {
+		return price, true
+	}
+	if !order.PostOnlyReprice {
+		return Zero, false
+	}
+	if order.Side == Sell {
+		return best.Price.Add(ob.tickSize), true
+	}
+	if best.Price.Cmp(ob.tickSize) <= 0 {
+		return Zero, false
+	}
+	return best.Price.Sub(ob.tickSize), true
+}
@@ -0,0 +1,157 @@
+package models
+
+import "container/list"
+
+// NOTE: PriceLevel holds every resting order at a single price in time priority (FIFO).
+// TotalQuantity is the aggregated visible quantity (hidden iceberg reserve excluded), kept up to date on every fill.
+type PriceLevel struct {
+	Price         Decimal
+	TotalQuantity Decimal
+	orders        *list.List
+}
+
+func newPriceLevel(price Decimal) *PriceLevel {
+	return &PriceLevel{Price: price, orders: list.New()}
+}
+
+func (lvl *PriceLevel) OrderCount() int { return lvl.orders.Len() }
+
+// NOTE: Front is the order that is matched next at this price.
+func (lvl *PriceLevel) Front() *Order {
+	if e := lvl.orders.Front(); e != nil {
+		return e.Value.(*Order)
+	}
+	return nil
+}
+
+// NOTE: Orders returns a copy of the queue, front first.
+func (lvl *PriceLevel) Orders() []*Order {
+	orders := make([]*Order, 0, lvl.orders.Len())
+	for e := lvl.orders.Front(); e != nil; e = e.Next() {
+		orders = append(orders, e.Value.(*Order))
+	}
+	return orders
+}
+
+// NOTE: orders almost always arrive in time order so this walks back from the tail,
+// only an order carrying an older Timestamp is slotted in ahead of later ones.
+func (lvl *PriceLevel) enqueue(order *Order) *list.Element {
+	lvl.TotalQuantity = lvl.TotalQuantity.Add(order.visible())
+	for e := lvl.orders.Back(); e != nil; e = e.Prev() {
+		if !hasPriority(order, e.Value.(*Order)) {
+			return lvl.orders.InsertAfter(order, e)
+		}
+	}
+	return lvl.orders.PushFront(order)
+}
+
+func (lvl *PriceLevel) remove(e *list.Element) {
+	lvl.TotalQuantity = lvl.TotalQuantity.Sub(e.Value.(*Order).visible())
+	lvl.orders.Remove(e)
+}
+
+// NOTE: hasPriority is the time part of price-time priority within one level.
+func hasPriority(a, b *Order) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp < b.Timestamp
+	}
+	return a.Sequence < b.Sequence
+}
+
+// NOTE: DepthLevel is the aggregated view of a price level exposed to callers.
+type DepthLevel struct {
+	Price      Decimal `json:"price"`
+	Quantity   Decimal `json:"quantity"`
+	OrderCount int     `json:"order_count"`
+}
+
+// NOTE: bookSide keeps the levels of one side sorted best first (highest bid / lowest ask) in a levelList,
+// plus a price index so depth-at-price and level lookup don't need a search.
+type bookSide struct {
+	side     OrderSide
+	levels   *levelList
+	by_price map[Decimal]*PriceLevel
+	orders   int
+	changed  map[Decimal]struct{} // nil unless the book tracks level changes
+}
+
+func newBookSide(side OrderSide) *bookSide {
+	bs := &bookSide{side: side, by_price: make(map[Decimal]*PriceLevel)}
+	bs.levels = newLevelList(bs.better)
+	return bs
+}
+
+// NOTE: better reports whether price a sits ahead of price b on this side.
+func (bs *bookSide) better(a, b Decimal) bool {
+	if bs.side == Buy {
+		return a.GreaterThan(b)
+	}
+	return a.LessThan(b)
+}
+
+func (bs *bookSide) best() *PriceLevel {
+	return bs.levels.first()
+}
+
+func (bs *bookSide) level(price Decimal) *PriceLevel {
+	if lvl, ok := bs.by_price[price]; ok {
+		return lvl
+	}
+	lvl := newPriceLevel(price)
+	bs.levels.insert(lvl)
+	bs.by_price[price] = lvl
+	return lvl
+}
+
+// NOTE: touch records that the level at price changed since the last LevelChanges call.
+func (bs *bookSide) touch(price Decimal) {
+	if bs.changed != nil {
+		bs.changed[price] = struct{}{}
+	}
+}
+
+func (bs *bookSide) add(order *Order) *list.Element {
+	bs.touch(order.Price)
+	bs.orders++
+	return bs.level(order.Price).enqueue(order)
+}
+
+// NOTE: remove takes the order out of its level and drops the level once it is empty.
+func (bs *bookSide) remove(lvl *PriceLevel, e *list.Element) {
+	bs.touch(lvl.Price)
+	lvl.remove(e)
+	bs.orders--
+	if lvl.OrderCount() == 0 {
+		bs.removeLevel(lvl.Price)
+	}
+}
+
+func (bs *bookSide) removeLevel(price Decimal) {
+	if _, ok := bs.by_price[price]; !ok {
+		return
+	}
+	delete(bs.by_price, price)
+	bs.levels.delete(price)
+}
+
+func (bs *bookSide) depth(n int) []DepthLevel {
+	if n <= 0 || n > bs.levels.len {
+		n = bs.levels.len
+	}
+	depth := make([]DepthLevel, 0, n)
+	for lvl := range bs.levels.all() {
+		if len(depth) == n {
+			break
+		}
+		depth = append(depth, DepthLevel{Price: lvl.Price, Quantity: lvl.TotalQuantity, OrderCount: lvl.OrderCount()})
+	}
+	return depth
+}
+
+func (bs *bookSide) front() (*Order, bool) {
+	lvl := bs.best()
+	if lvl == nil {
+		return nil, false
+	}
+	return lvl.Front(), true
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+)
+
+/*
+SelfTradePrevention is what happens when an order would trade with a resting order of its own owner. The
+incoming order's mode decides, no trade is printed whatever it is:
+  - CancelNewest: the incoming order is cancelled, the resting one stays.
+  - CancelOldest: the resting order is cancelled and the incoming one carries on matching.
+  - CancelBoth: both are cancelled.
+  - DecrementAndCancel: both give up the smaller of their two remaining quantities, whichever has nothing left
+    is cancelled (both when they were equal), the other carries on.
+
+An order placed without a mode takes its owner's account setting (see PinSelfTradePrevention), STPNone on both
+lets an owner trade with themselves.
+*/
+type SelfTradePrevention int
+
+const (
+	STPNone SelfTradePrevention = iota
+	STPCancelNewest
+	STPCancelOldest
+	STPCancelBoth
+	STPDecrementAndCancel
+)
+
+func (mode SelfTradePrevention) String() string {
+	switch mode {
+	case STPNone:
+		return "None"
+	case STPCancelNewest:
+		return "CancelNewest"
+	case STPCancelOldest:
+		return "CancelOldest"
+	case STPCancelBoth:
+		return "CancelBoth"
+	case STPDecrementAndCancel:
+		return "DecrementAndCancel"
+	default:
+		return "Unknown"
+	}
+}
+
+func validateSelfTradePrevention(mode SelfTradePrevention) error {
+	if mode < STPNone || mode > STPDecrementAndCancel {
+		return fmt.Errorf("unknown self-trade prevention mode: %d", mode)
+	}
+	return nil
+}
+
+// NOTE: AccountSelfTradePrevention is an owner's account setting, as kept in the registry state.
+type AccountSelfTradePrevention struct {
+	Owner string              `json:"owner"`
+	Mode  SelfTradePrevention `json:"mode"`
+}
+
+// NOTE: SetSelfTradePrevention sets the mode owner's orders get when they're placed without one, STPNone clears it.
+func (r *MarketRegistry) SetSelfTradePrevention(owner string, mode SelfTradePrevention) error {
+	if owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if err := validateSelfTradePrevention(mode); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if mode == STPNone {
+		delete(r.selfTrade, owner)
+	} else {
+		r.selfTrade[owner] = mode
+	}
+	return nil
+}
+
+func (r *MarketRegistry) SelfTradePrevention(owner string) SelfTradePrevention {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.selfTrade[owner]
+}
+
+/*
+PinSelfTradePrevention gives an order placed without a mode its owner's, it keeps it for its life. It's done
+before the order is journaled: AddOrder goes by the order's own mode only, so a replay can't see a setting
+changed between journaling the order and applying it.
+*/
+func (r *MarketRegistry) PinSelfTradePrevention(order *Order) {
+	if order.SelfTradePrevention == STPNone {
+		order.SelfTradePrevention = r.SelfTradePrevention(order.OwnerUsername)
+	}
+}
+
+func (r *MarketRegistry) selfTradeState() []AccountSelfTradePrevention {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var state []AccountSelfTradePrevention
+	for owner, mode := range r.selfTrade {
+		state = append(state, AccountSelfTradePrevention{Owner: owner, Mode: mode})
+	}
+	sort.Slice(state, func(i, j int) bool { return state[i].Owner < state[j].Owner })
+	return state
+}
+
+func (r *MarketRegistry) restoreSelfTrade(state []AccountSelfTradePrevention) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.selfTrade = make(map[string]SelfTradePrevention)
+	for _, account := range state {
+		r.selfTrade[account.Owner] = account.Mode
+	}
+}
+
+// NOTE: selfTrade reports whether taker has to be kept from trading with maker.
+func (taker *Order) selfTrade(maker *Order) bool {
+	return taker.SelfTradePrevention != STPNone && taker.OwnerUsername == maker.OwnerUsername
+}
+
+/*
+preventSelfTrade applies taker's mode against maker, the front order of lvl, instead of a fill. It reports whether
+taker may go on matching. Cancelled orders are reported with RejectSelfTradePrevented, a decremented order that
+stays open with OrderAmended.
+*/
+func (ob *Orderbook) preventSelfTrade(taker *Order, opposite *bookSide, lvl *PriceLevel, e *list.Element) bool {
+	maker := e.Value.(*Order)
+	switch taker.SelfTradePrevention {
+	case STPCancelNewest:
+		ob.cancelSelfTrade(taker)
+		return false
+	case STPCancelOldest:
+		ob.pullSelfTrade(opposite, lvl, e)
+		return true
+	case STPCancelBoth:
+		ob.pullSelfTrade(opposite, lvl, e)
+		ob.cancelSelfTrade(taker)
+		return false
+	}
+
+	qty := MinDecimal(taker.Remaining(), maker.Remaining())
+	if qty.Cmp(maker.Remaining()) == 0 {
+		opposite.remove(lvl, e)
+		delete(ob.resting, maker.ID)
+		maker.Quantity = maker.Quantity.Sub(qty)
+		ob.cancelSelfTrade(maker)
+	} else {
+		shown := maker.visible()
+		maker.Quantity = maker.Quantity.Sub(qty)
+		if maker.isIceberg() {
+			maker.VisibleQuantity = MinDecimal(maker.VisibleQuantity, maker.Remaining())
+		}
+		lvl.TotalQuantity = lvl.TotalQuantity.Sub(shown.Sub(maker.visible()))
+		opposite.touch(lvl.Price)
+		if maker.visible().IsZero() {
+			ob.replenish(lvl, e)
+		}
+		ob.notify(OrderAmended, maker, nil)
+	}
+	taker.Quantity = taker.Quantity.Sub(qty)
+	if taker.Remaining().IsZero() {
+		ob.cancelSelfTrade(taker)
+		return false
+	}
+	ob.notify(OrderAmended, taker, nil)
+	return true
+}
+
+// NOTE: pullSelfTrade takes a resting order out of the book and cancels it.
+func (ob *Orderbook) pullSelfTrade(opposite *bookSide, lvl *PriceLevel, e *list.Element) {
+	maker := e.Value.(*Order)
+	opposite.remove(lvl, e)
+	delete(ob.resting, maker.ID)
+	ob.cancelSelfTrade(maker)
+}
+
+func (ob *Orderbook) cancelSelfTrade(order *Order) {
+	order.Status = StatusCancelled
+	order.RejectReason = RejectSelfTradePrevented
+	ob.notify(OrderCancelled, order, nil)
+}
+
+/*
+selfTradeUse is for looking ahead at what taker, with remaining left, would do at its own maker: how much of
+remaining it gives up there without trading (DecrementAndCancel) and whether it stops matching there (CancelNewest,
+CancelBoth). CancelOldest gives up nothing and goes past it.
+*/
+func (taker *Order) selfTradeUse(maker *Order, remaining Decimal) (Decimal, bool) {
+	switch taker.SelfTradePrevention {
+	case STPCancelOldest:
+		return Zero, false
+	case STPDecrementAndCancel:
+		return MinDecimal(remaining, maker.Remaining()), false
+	default:
+		return Zero, true
+	}
+}
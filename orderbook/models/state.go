@@ -0,0 +1,160 @@
+package models
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// NOTE: RestingState is a resting order with what is left of its visible (iceberg) slice, which isn't in its JSON.
+type RestingState struct {
+	Order   Order   `json:"order"`
+	Visible Decimal `json:"visible"`
+}
+
+/*
+BookState is everything needed to rebuild a book exactly as it was: the resting orders in priority order (bids
+then asks, best level first, FIFO within a level), the held stops in arrival order, the counters that hand out
+IDs and sequences, the last price and the trade tape.
+*/
+type BookState struct {
+	Symbol            string         `json:"symbol"`
+	Status            MarketStatus   `json:"status"`
+	Orders            []RestingState `json:"orders"`
+	Stops             []Order        `json:"stops"`
+	LastPrice         Decimal        `json:"last_price"`
+	NextOrderID       uint           `json:"next_order_id"`
+	NextSequence      uint64         `json:"next_sequence"`
+	NextTradeSequence uint64         `json:"next_trade_sequence"`
+	RecentTrades      []Trade        `json:"recent_trades"`
+	Volumes           []OwnerVolume  `json:"volumes,omitempty"` // NOTE: the market's, for its fee tiers
+}
+
+type RegistryState struct {
+	NextOrderID uint             `json:"next_order_id"`
+	Markets     []BookState      `json:"markets"`
+	Ledger      *LedgerState     `json:"ledger,omitempty"`
+	Transfers   *TransfersState  `json:"transfers,omitempty"`
+	Executions  *ExecutionsState `json:"executions,omitempty"`
+
+	SelfTradePrevention []AccountSelfTradePrevention `json:"self_trade_prevention,omitempty"`
+}
+
+func (ob *Orderbook) State() BookState {
+	state := BookState{
+		Symbol:            ob.Symbol,
+		LastPrice:         ob.lastPrice,
+		NextOrderID:       ob.nextOrderID,
+		NextSequence:      ob.nextSequence,
+		NextTradeSequence: ob.nextTradeSequence,
+		RecentTrades:      append([]Trade(nil), ob.recentTrades...),
+	}
+	for _, bs := range []*bookSide{ob.bidOrders, ob.askOrders} {
+		for lvl := range bs.levels.all() {
+			for _, order := range lvl.Orders() {
+				state.Orders = append(state.Orders, RestingState{Order: *order, Visible: order.VisibleQuantity})
+			}
+		}
+	}
+	for _, stop := range ob.stops {
+		state.Stops = append(state.Stops, *stop)
+	}
+	sort.Slice(state.Stops, func(i, j int) bool { return state.Stops[i].Sequence < state.Stops[j].Sequence })
+	return state
+}
+
+// NOTE: Restore loads state into an empty book. Settings (tick size, clock, listener) are left as they are.
+func (ob *Orderbook) Restore(state BookState) error {
+	if len(ob.resting) > 0 || len(ob.stops) > 0 {
+		return fmt.Errorf("book %s isn't empty", ob.Symbol)
+	}
+	for i := range state.Orders {
+		order := state.Orders[i].Order
+		order.VisibleQuantity = state.Orders[i].Visible
+		if _, ok := ob.resting[order.ID]; ok || order.ID == 0 {
+			return fmt.Errorf("book %s: bad resting order %d", ob.Symbol, order.ID)
+		}
+		bs := ob.sideOf(order.Side)
+		e := bs.add(&order)
+		ob.resting[order.ID] = &restingOrder{side: bs, level: bs.by_price[order.Price], elem: e}
+		if order.ExpiresAt > 0 {
+			heap.Push(ob.expiries, expiry{id: order.ID, at: order.ExpiresAt})
+		}
+	}
+	for i := range state.Stops {
+		stop := state.Stops[i]
+		ob.holdStop(&stop)
+	}
+	ob.lastPrice = state.LastPrice
+	ob.nextOrderID = state.NextOrderID
+	ob.nextSequence = state.NextSequence
+	ob.nextTradeSequence = state.NextTradeSequence
+	ob.recentTrades = append([]Trade(nil), state.RecentTrades...)
+	return nil
+}
+
+// NOTE: State captures every market's book, callers hold the lock that guards the books.
+func (r *MarketRegistry) State() RegistryState {
+	r.mu.RLock()
+	state := RegistryState{NextOrderID: r.nextOrderID}
+	r.mu.RUnlock()
+	state.SelfTradePrevention = r.selfTradeState()
+	for _, m := range r.Markets() {
+		book := m.Book.State()
+		book.Status = r.statusOf(m)
+		book.Volumes = m.volumeState()
+		state.Markets = append(state.Markets, book)
+	}
+	if balances := r.Balances(); balances != nil {
+		ledger, transfers := balances.Ledger().State(), balances.Transfers().State()
+		state.Ledger, state.Transfers = &ledger, &transfers
+	}
+	if executions := r.Executions(); executions != nil {
+		reports := executions.State()
+		state.Executions = &reports
+	}
+	return state
+}
+
+// NOTE: Restore loads state into the registered markets, which must all be empty. Every market in state has to be listed.
+func (r *MarketRegistry) Restore(state RegistryState) error {
+	for _, book := range state.Markets {
+		m, err := r.Market(book.Symbol)
+		if err != nil {
+			return err
+		}
+		if err := m.Book.Restore(book); err != nil {
+			return err
+		}
+		if err := r.SetStatus(m.Symbol, book.Status); err != nil {
+			return err
+		}
+		m.restoreVolumes(book.Volumes)
+		r.mu.Lock()
+		for _, resting := range book.Orders {
+			r.openOrders[resting.Order.ID] = m.Symbol
+		}
+		for _, stop := range book.Stops {
+			r.openOrders[stop.ID] = m.Symbol
+		}
+		r.mu.Unlock()
+	}
+	r.mu.Lock()
+	r.nextOrderID = state.NextOrderID
+	r.mu.Unlock()
+	r.restoreSelfTrade(state.SelfTradePrevention)
+	if executions := r.Executions(); executions != nil && state.Executions != nil {
+		executions.Restore(*state.Executions)
+	}
+	balances := r.Balances()
+	if balances == nil {
+		return nil
+	}
+	if state.Transfers != nil {
+		balances.Transfers().Restore(*state.Transfers)
+	}
+	if state.Ledger != nil {
+		return balances.Ledger().Restore(*state.Ledger)
+	}
+	return nil
+}
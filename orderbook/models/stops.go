@@ -0,0 +1,149 @@
+package models
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// NOTE: isMarket covers plain market orders and stop-market orders once they are triggered.
+func (order *Order) isMarket() bool {
+	return order.Type == MarketOrder || order.Type == StopMarketOrder
+}
+
+func (order *Order) isStop() bool {
+	return order.Type == StopMarketOrder || order.Type == StopLimitOrder
+}
+
+// NOTE: triggeredBy reports whether a trade at last crosses the order's stop price.
+func (order *Order) triggeredBy(last Decimal) bool {
+	if last.IsZero() {
+		return false
+	}
+	if order.Side == Buy {
+		return last.Cmp(order.StopPrice) >= 0
+	}
+	return last.Cmp(order.StopPrice) <= 0
+}
+
+func validateStop(order *Order) error {
+	if !order.isStop() {
+		return nil
+	}
+	if !order.StopPrice.IsPositive() {
+		return fmt.Errorf("stop price must be greater than zero")
+	}
+	return nil
+}
+
+// NOTE: LastTradePrice is the price of the most recent trade, 0 before the first one.
+func (ob *Orderbook) LastTradePrice() Decimal { return ob.lastPrice }
+
+// NOTE: StopCount returns the number of stop orders waiting for their trigger on each side.
+func (ob *Orderbook) StopCount(side OrderSide) int {
+	count := 0
+	for _, order := range ob.stops {
+		if order.Side == side {
+			count++
+		}
+	}
+	return count
+}
+
+func (ob *Orderbook) holdStop(order *Order) {
+	ob.stops[order.ID] = order
+	if order.Side == Buy {
+		heap.Push(ob.buyStops, order)
+	} else {
+		heap.Push(ob.sellStops, order)
+	}
+	if order.ExpiresAt > 0 {
+		heap.Push(ob.expiries, expiry{id: order.ID, at: order.ExpiresAt})
+	}
+}
+
+/*
+fireStops triggers every held stop order crossed by the last traded price and runs it through matching.
+Fills caused by a triggered stop move the last price again, so the loop keeps going until no held stop is crossed.
+Ordering is deterministic: each side's trigger book yields the stop closest to the market first (lowest buy stop,
+highest sell stop, then arrival sequence) and when both sides have a triggered stop the one that arrived first fires.
+*/
+func (ob *Orderbook) fireStops() []Trade {
+	var trades []Trade
+	for {
+		buy, sell := ob.buyStops.next(ob.stops), ob.sellStops.next(ob.stops)
+		buy_fires := buy != nil && buy.triggeredBy(ob.lastPrice)
+		sell_fires := sell != nil && sell.triggeredBy(ob.lastPrice)
+
+		var order *Order
+		switch {
+		case buy_fires && sell_fires:
+			order = buy
+			if sell.Sequence < buy.Sequence {
+				order = sell
+			}
+		case buy_fires:
+			order = buy
+		case sell_fires:
+			order = sell
+		default:
+			return trades
+		}
+		if order.Side == Buy {
+			heap.Pop(ob.buyStops)
+		} else {
+			heap.Pop(ob.sellStops)
+		}
+		delete(ob.stops, order.ID)
+
+		order.Triggered = true
+		order.Timestamp = ob.now()
+		ob.stamp(order)
+		if order.expiredBy(order.Timestamp) {
+			ob.expire(order)
+			continue
+		}
+		ob.notify(OrderTriggered, order, nil)
+		trades = append(trades, ob.execute(order)...)
+	}
+}
+
+// NOTE: stopHeap is one side of the trigger book, the stop nearest to the market on top.
+type stopHeap []*Order
+
+func (h stopHeap) Len() int { return len(h) }
+
+func (h stopHeap) Less(i, j int) bool {
+	if h[i].StopPrice != h[j].StopPrice {
+		if h[i].Side.getString() == "Buy" {
+			return h[i].StopPrice.LessThan(h[j].StopPrice) // buy stops fire as the price rises
+		}
+		return h[i].StopPrice.GreaterThan(h[j].StopPrice) // sell stops fire as the price falls
+	}
+	return h[i].Sequence < h[j].Sequence
+}
+
+func (h stopHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *stopHeap) Push(x interface{}) {
+	*h = append(*h, x.(*Order))
+}
+
+func (h *stopHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[0 : n-1]
+	return item
+}
+
+// NOTE: next drops stops cancelled since they were held (lazy removal) and returns the top one.
+func (h *stopHeap) next(held map[uint]*Order) *Order {
+	for h.Len() > 0 {
+		top := (*h)[0]
+		if held[top.ID] == top {
+			return top
+		}
+		heap.Pop(h)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TimeInForce int
+
+var ErrExpiresAtNotGTD = errors.New("expires_at is only taken for GTD orders")
+
+const (
+	GTC TimeInForce = iota // good till cancelled
+	IOC                    // immediate or cancel
+	FOK                    // fill or kill
+	GTD                    // good till date (ExpiresAt)
+	DAY                    // good for the trading session
+)
+
+func (tif TimeInForce) String() string {
+	switch tif {
+	case GTC:
+		return "GTC"
+	case IOC:
+		return "IOC"
+	case FOK:
+		return "FOK"
+	case GTD:
+		return "GTD"
+	case DAY:
+		return "DAY"
+	default:
+		return "Unknown"
+	}
+}
+
+// NOTE: validateTimeInForce takes ExpiresAt from GTD orders only, the book sets that of a DAY order itself.
+func (ob *Orderbook) validateTimeInForce(order *Order) error {
+	if order.TimeInForce != GTD && order.ExpiresAt != 0 {
+		return fmt.Errorf("%w, not %s", ErrExpiresAtNotGTD, order.TimeInForce)
+	}
+	switch order.TimeInForce {
+	case GTC, IOC, FOK:
+		return nil
+	case GTD:
+		if order.isMarket() {
+			return fmt.Errorf("market orders can't be %s", order.TimeInForce)
+		}
+		if order.ExpiresAt <= ob.now() {
+			return fmt.Errorf("GTD order must expire in the future")
+		}
+		return nil
+	case DAY:
+		if order.isMarket() {
+			return fmt.Errorf("market orders can't be %s", order.TimeInForce)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown time in force: %d", order.TimeInForce)
+	}
+}
+
+// NOTE: SetSessionClose sets the time of day (UTC) at which DAY orders expire.
+func (ob *Orderbook) SetSessionClose(hour, minute int) error {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return fmt.Errorf("invalid session close %02d:%02d", hour, minute)
+	}
+	ob.sessionClose = time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	return nil
+}
+
+// NOTE: nextSessionClose is the first session close strictly after the book's current time.
+func (ob *Orderbook) nextSessionClose() uint32 {
+	now := ob.clock().UTC()
+	close_at := now.Truncate(24 * time.Hour).Add(ob.sessionClose)
+	if !close_at.After(now) {
+		close_at = close_at.Add(24 * time.Hour)
+	}
+	return uint32(close_at.Unix())
+}
+
+// NOTE: canFillCompletely is the FOK pre-check: is there enough crossing liquidity for the whole order.
+func (ob *Orderbook) canFillCompletely(order *Order) bool {
+	opposite := ob.askOrders
+	if order.Side == Sell {
+		opposite = ob.bidOrders
+	}
+	limit := ob.takerLimit(order, opposite)
+	needed, now := order.Remaining(), ob.now()
+	for lvl := range opposite.levels.all() {
+		if !crosses(order.Side, limit, lvl.Price) {
+			break
+		}
+		for _, maker := range lvl.Orders() {
+			if maker.expiredBy(now) {
+				continue // NOTE: matching expires it instead of filling it
+			}
+			qty := MinDecimal(needed, maker.Remaining())
+			if order.selfTrade(maker) {
+				var stop bool
+				if qty, stop = order.selfTradeUse(maker, needed); stop {
+					return false
+				}
+			}
+			if needed = needed.Sub(qty); !needed.IsPositive() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NOTE: expiredBy reports whether a GTD/DAY order's expiry is at or before now.
+func (order *Order) expiredBy(now uint32) bool {
+	return order.ExpiresAt > 0 && order.ExpiresAt <= now
+}
+
+// NOTE: expire ends an order that left the book (or the trigger book) because its time ran out.
+func (ob *Orderbook) expire(order *Order) {
+	order.Status = StatusExpired
+	ob.notify(OrderExpired, order, nil)
+}
+
+/*
+ExpireOrders removes every resting GTD/DAY order, and every held GTD stop, whose expiry is at or before now and
+returns them. The expiry heap is lazy: cancelled, filled, triggered or amended orders are skipped when they surface.
+*/
+func (ob *Orderbook) ExpireOrders(now time.Time) []*Order {
+	var expired []*Order
+	for ob.ExpiryDue(now) {
+		next := heap.Pop(ob.expiries).(expiry)
+		if stop, ok := ob.stops[next.id]; ok && stop.ExpiresAt == next.at {
+			delete(ob.stops, next.id) // NOTE: the trigger book drops it lazily
+			ob.expire(stop)
+			expired = append(expired, stop)
+			continue
+		}
+		ro, ok := ob.resting[next.id]
+		if !ok || ro.order().ExpiresAt != next.at {
+			continue
+		}
+		order := ro.order()
+		ob.unrest(ro)
+		ob.expire(order)
+		expired = append(expired, order)
+	}
+	return expired
+}
+
+// NOTE: ExpiryDue reports whether ExpireOrders has anything to look at, now or an earlier time.
+func (ob *Orderbook) ExpiryDue(now time.Time) bool {
+	return ob.expiries.Len() > 0 && (*ob.expiries)[0].at <= uint32(now.Unix())
+}
+
+type expiry struct {
+	id uint
+	at uint32
+}
+
+// NOTE: expiryHeap is a min-heap of expiry times, the soonest expiry on top.
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int { return len(h) }
+func (h expiryHeap) Less(i, j int) bool {
+	if h[i].at != h[j].at {
+		return h[i].at < h[j].at
+	}
+	return h[i].id < h[j].id
+}
+func (h expiryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x interface{}) {
+	*h = append(*h, x.(expiry))
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[0 : n-1]
+	return item
+}
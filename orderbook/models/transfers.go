@@ -0,0 +1,400 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferStatus   = errors.New("transfer can't move to that status")
+	ErrWithdrawalLimit  = errors.New("withdrawal limit reached")
+	ErrNotApprover      = errors.New("not allowed to approve withdrawal")
+)
+
+// NOTE: withdrawalWindow is how far back an asset's WithdrawalLimit looks.
+const withdrawalWindow = 24 * time.Hour
+
+type TransferKind int
+
+const (
+	TransferDeposit TransferKind = iota + 1
+	TransferWithdrawal
+)
+
+func (kind TransferKind) String() string {
+	switch kind {
+	case TransferDeposit:
+		return "Deposit"
+	case TransferWithdrawal:
+		return "Withdrawal"
+	default:
+		return "Unknown"
+	}
+}
+
+/*
+TransferStatus is where a deposit or a withdrawal stands:
+  - Pending: a deposit waits for custody to see the funds arrive, a large withdrawal for an admin to approve it.
+  - Confirmed: custody saw the deposit, or the withdrawal is approved and handed to custody to pay out.
+  - Completed: the funds moved, credited to the owner or sent out of custody. Final.
+  - Rejected: refused or failed, a withdrawal's funds go back to the owner. Final.
+*/
+type TransferStatus int
+
+const (
+	TransferPending TransferStatus = iota + 1
+	TransferConfirmed
+	TransferRejected
+	TransferCompleted
+)
+
+func (status TransferStatus) String() string {
+	switch status {
+	case TransferPending:
+		return "Pending"
+	case TransferConfirmed:
+		return "Confirmed"
+	case TransferRejected:
+		return "Rejected"
+	case TransferCompleted:
+		return "Completed"
+	default:
+		return "Unknown"
+	}
+}
+
+/*
+Transfer is a deposit into the exchange or a withdrawal out of it. A withdrawal holds its amount from the moment
+it's requested, so it can't be traded away while it waits; a deposit is only credited once it completes.
+*/
+type Transfer struct {
+	ID            uint64         `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Kind          TransferKind   `json:"kind"`
+	Owner         string         `json:"owner" gorm:"index"`
+	Asset         string         `json:"asset"`
+	Amount        Decimal        `json:"amount" gorm:"type:numeric(36,18)"`
+	Address       string         `json:"address,omitempty"`
+	Status        TransferStatus `json:"status" gorm:"index"`
+	NeedsApproval bool           `json:"needs_approval,omitempty"`
+	ApprovedBy    string         `json:"approved_by,omitempty"`
+	TxID          string         `json:"tx_id,omitempty"`
+	Reason        string         `json:"reason,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NOTE: Reference is how the ledger and the reservations name the transfer ("withdrawal 3").
+func (transfer *Transfer) Reference() string {
+	return fmt.Sprintf("%s %d", strings.ToLower(transfer.Kind.String()), transfer.ID)
+}
+
+type TransfersState struct {
+	NextTransferID uint64     `json:"next_transfer_id"`
+	Transfers      []Transfer `json:"transfers"`
+}
+
+/*
+Transfers keeps every deposit and withdrawal by ID. Like order IDs, transfer IDs are handed out before the
+request is journaled, so a replay gives each transfer the ID it had.
+*/
+type Transfers struct {
+	mu        sync.Mutex
+	transfers map[uint64]*Transfer
+	nextID    uint64
+}
+
+func NewTransfers() *Transfers {
+	return &Transfers{transfers: make(map[uint64]*Transfer)}
+}
+
+func (t *Transfers) NewID() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	return t.nextID
+}
+
+func (t *Transfers) Get(id uint64) (Transfer, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	transfer, ok := t.transfers[id]
+	if !ok {
+		return Transfer{}, false
+	}
+	return *transfer, true
+}
+
+// NOTE: List returns the transfers of owner (everyone's when empty) in status (any when zero), oldest first.
+func (t *Transfers) List(owner string, status TransferStatus) []Transfer {
+	t.mu.Lock()
+	var list []Transfer
+	for _, transfer := range t.transfers {
+		if (owner == "" || transfer.Owner == owner) && (status == 0 || transfer.Status == status) {
+			list = append(list, *transfer)
+		}
+	}
+	t.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// NOTE: withdrawn is what owner asked to withdraw of asset since since, rejected withdrawals aside.
+func (t *Transfers) withdrawn(owner, asset string, since time.Time) Decimal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := Zero
+	for _, transfer := range t.transfers {
+		if transfer.Kind == TransferWithdrawal && transfer.Owner == owner && transfer.Asset == asset &&
+			transfer.Status != TransferRejected && transfer.CreatedAt.After(since) {
+			total = total.Add(transfer.Amount)
+		}
+	}
+	return total
+}
+
+// NOTE: seen catches the counter up with an ID from a replayed journal, the request may still be refused.
+func (t *Transfers) seen(id uint64) {
+	t.mu.Lock()
+	if id > t.nextID {
+		t.nextID = id
+	}
+	t.mu.Unlock()
+}
+
+func (t *Transfers) add(transfer Transfer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if transfer.ID == 0 {
+		return fmt.Errorf("%s needs an id", strings.ToLower(transfer.Kind.String()))
+	}
+	if _, ok := t.transfers[transfer.ID]; ok {
+		return fmt.Errorf("transfer %d already exists", transfer.ID)
+	}
+	t.transfers[transfer.ID] = &transfer
+	return nil
+}
+
+// NOTE: move runs fn on transfer id if it's in one of the statuses from and returns the transfer as it left it.
+func (t *Transfers) move(id uint64, at time.Time, fn func(*Transfer) error, from ...TransferStatus) (Transfer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	transfer, ok := t.transfers[id]
+	if !ok {
+		return Transfer{}, fmt.Errorf("transfer %d: %w", id, ErrTransferNotFound)
+	}
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || transfer.Status == status
+	}
+	if !allowed {
+		return *transfer, fmt.Errorf("%s %d is %s: %w", transfer.Kind, id, transfer.Status, ErrTransferStatus)
+	}
+	if err := fn(transfer); err != nil {
+		return *transfer, err
+	}
+	transfer.UpdatedAt = at
+	return *transfer, nil
+}
+
+func (t *Transfers) State() TransfersState {
+	state := TransfersState{Transfers: t.List("", 0)}
+	t.mu.Lock()
+	state.NextTransferID = t.nextID
+	t.mu.Unlock()
+	return state
+}
+
+func (t *Transfers) Restore(state TransfersState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.transfers = make(map[uint64]*Transfer)
+	for i := range state.Transfers {
+		transfer := state.Transfers[i]
+		t.transfers[transfer.ID] = &transfer
+	}
+	t.nextID = state.NextTransferID
+}
+
+// NOTE: transferAsset checks amount of asset can be moved and returns the asset.
+func (r *MarketRegistry) transferAsset(code string, amount Decimal) (Asset, error) {
+	asset, err := r.Asset(code)
+	if err != nil {
+		return Asset{}, err
+	}
+	if !amount.IsPositive() {
+		return Asset{}, fmt.Errorf("amount must be greater than zero")
+	}
+	if !amount.FitsScale(asset.Scale) {
+		return Asset{}, fmt.Errorf("amount %s has more than %d decimals (%s)", amount, asset.Scale, asset.Code)
+	}
+	return asset, nil
+}
+
+func (r *MarketRegistry) transferBalances() (*Balances, error) {
+	balances := r.Balances()
+	if balances == nil {
+		return nil, fmt.Errorf("transfers need balances")
+	}
+	return balances, nil
+}
+
+// NOTE: RequestDeposit opens deposit id: owner says amount of asset is on its way to address.
+func (r *MarketRegistry) RequestDeposit(id uint64, owner, code string, amount Decimal, address string, at time.Time) (Transfer, error) {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return Transfer{}, err
+	}
+	balances.Transfers().seen(id)
+	asset, err := r.transferAsset(code, amount)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if owner == "" {
+		return Transfer{}, fmt.Errorf("deposit needs an owner")
+	}
+	deposit := Transfer{ID: id, Kind: TransferDeposit, Owner: owner, Asset: asset.Code, Amount: amount, Address: address,
+		Status: TransferPending, CreatedAt: at, UpdatedAt: at}
+	return deposit, balances.Transfers().add(deposit)
+}
+
+// NOTE: ReserveWithdrawal reserves the funds of withdrawal id before it's journaled, see Balances.
+func (r *MarketRegistry) ReserveWithdrawal(id uint64, owner, asset string, amount Decimal) error {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return err
+	}
+	withdrawal := Transfer{ID: id, Kind: TransferWithdrawal}
+	return balances.Reserve(withdrawal.Reference(), owner, strings.ToUpper(asset), amount)
+}
+
+func (r *MarketRegistry) UnreserveWithdrawal(id uint64) {
+	if balances := r.Balances(); balances != nil {
+		withdrawal := Transfer{ID: id, Kind: TransferWithdrawal}
+		balances.Unreserve(withdrawal.Reference())
+	}
+}
+
+/*
+RequestWithdrawal opens withdrawal id of amount of asset to address and holds the funds. It's refused when it
+would take what owner withdrew of the asset over the last 24 hours past the asset's WithdrawalLimit. One above
+the asset's ApprovalThreshold stays pending until an admin approves it, any other is confirmed straight away.
+*/
+func (r *MarketRegistry) RequestWithdrawal(id uint64, owner, code string, amount Decimal, address string, at time.Time) (Transfer, error) {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return Transfer{}, err
+	}
+	balances.Transfers().seen(id)
+	asset, err := r.transferAsset(code, amount)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if owner == "" || address == "" {
+		return Transfer{}, fmt.Errorf("withdrawal needs an owner and an address")
+	}
+	if asset.WithdrawalLimit.IsPositive() {
+		withdrawn := balances.Transfers().withdrawn(owner, asset.Code, at.Add(-withdrawalWindow))
+		if asset.WithdrawalLimit.LessThan(withdrawn.Add(amount)) {
+			return Transfer{}, fmt.Errorf("%s withdrew %s %s in the last 24 hours, the limit is %s: %w", owner, withdrawn, asset.Code, asset.WithdrawalLimit, ErrWithdrawalLimit)
+		}
+	}
+	withdrawal := Transfer{ID: id, Kind: TransferWithdrawal, Owner: owner, Asset: asset.Code, Amount: amount, Address: address,
+		Status: TransferConfirmed, CreatedAt: at, UpdatedAt: at}
+	if asset.ApprovalThreshold.IsPositive() && asset.ApprovalThreshold.LessThan(amount) {
+		withdrawal.Status, withdrawal.NeedsApproval = TransferPending, true
+	}
+	if err := balances.Transfers().add(withdrawal); err != nil {
+		return Transfer{}, err
+	}
+	balances.lock(&r.accountIDs, withdrawal.Reference(), owner, asset.Code, amount)
+	return withdrawal, nil
+}
+
+/*
+SetApprovers names the admins who may approve withdrawals from now on. Set it once the journal is replayed: an
+approval replayed was checked against the admins of its time, who may not be admins anymore. Until it's set any
+second user may approve.
+*/
+func (r *MarketRegistry) SetApprovers(names []string) {
+	approvers := make(map[string]bool, len(names))
+	for _, name := range names {
+		approvers[name] = true
+	}
+	r.mu.Lock()
+	r.approvers = approvers
+	r.mu.Unlock()
+}
+
+// NOTE: approve checks that by may approve withdrawal: an approver (see SetApprovers) other than its owner.
+func (r *MarketRegistry) approve(withdrawal *Transfer, by string) error {
+	if by == "" {
+		return fmt.Errorf("withdrawal %d needs an admin to approve it: %w", withdrawal.ID, ErrNotApprover)
+	}
+	if by == withdrawal.Owner {
+		return fmt.Errorf("%s is %w %d, it's their own: a second admin has to", by, ErrNotApprover, withdrawal.ID)
+	}
+	r.mu.RLock()
+	approvers := r.approvers
+	r.mu.RUnlock()
+	if approvers != nil && !approvers[by] {
+		return fmt.Errorf("%s is %w %d, only admins are", by, ErrNotApprover, withdrawal.ID)
+	}
+	return nil
+}
+
+// NOTE: ConfirmTransfer moves a pending transfer on: custody saw the deposit, or admin by approves the withdrawal.
+func (r *MarketRegistry) ConfirmTransfer(id uint64, by string, at time.Time) (Transfer, error) {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return Transfer{}, err
+	}
+	return balances.Transfers().move(id, at, func(transfer *Transfer) error {
+		if transfer.Kind == TransferWithdrawal {
+			if err := r.approve(transfer, by); err != nil {
+				return err
+			}
+			transfer.ApprovedBy = by
+		}
+		transfer.Status = TransferConfirmed
+		return nil
+	}, TransferPending)
+}
+
+// NOTE: CompleteTransfer books a confirmed transfer custody settled as txID: the deposit is credited, the withdrawal leaves.
+func (r *MarketRegistry) CompleteTransfer(id uint64, txID string, at time.Time) (Transfer, error) {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return Transfer{}, err
+	}
+	return balances.Transfers().move(id, at, func(transfer *Transfer) error {
+		if transfer.Kind == TransferDeposit {
+			if err := balances.deposit(&r.accountIDs, transfer.Owner, transfer.Asset, transfer.Amount, transfer.Reference()); err != nil {
+				return err
+			}
+		} else {
+			balances.withdraw(&r.accountIDs, transfer.Reference(), transfer.Owner, transfer.Asset, transfer.Amount)
+		}
+		transfer.Status, transfer.TxID = TransferCompleted, txID
+		return nil
+	}, TransferConfirmed)
+}
+
+// NOTE: RejectTransfer refuses a transfer that isn't done yet, a withdrawal gives its funds back.
+func (r *MarketRegistry) RejectTransfer(id uint64, reason string, at time.Time) (Transfer, error) {
+	balances, err := r.transferBalances()
+	if err != nil {
+		return Transfer{}, err
+	}
+	return balances.Transfers().move(id, at, func(transfer *Transfer) error {
+		if transfer.Kind == TransferWithdrawal {
+			balances.lock(&r.accountIDs, transfer.Reference(), transfer.Owner, transfer.Asset, transfer.Amount.Neg())
+		}
+		transfer.Status, transfer.Reason = TransferRejected, reason
+		return nil
+	}, TransferPending, TransferConfirmed)
+}
@@ -45,6 +45,274 @@ func (EnumSide) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_e6b2982dec9a4117, []int{0}
 }
 
+type TimeInForce int32
+
+const (
+	TimeInForce_GTC TimeInForce = 0
+	TimeInForce_IOC TimeInForce = 1
+	TimeInForce_FOK TimeInForce = 2
+	TimeInForce_GTD TimeInForce = 3
+	TimeInForce_DAY TimeInForce = 4
+)
+
+var TimeInForce_name = map[int32]string{
+	0: "GTC",
+	1: "IOC",
+	2: "FOK",
+	3: "GTD",
+	4: "DAY",
+}
+
+var TimeInForce_value = map[string]int32{
+	"GTC": 0,
+	"IOC": 1,
+	"FOK": 2,
+	"GTD": 3,
+	"DAY": 4,
+}
+
+func (x TimeInForce) String() string {
+	return proto.EnumName(TimeInForce_name, int32(x))
+}
+
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{1}
+}
+
+type RejectReason int32
+
+const (
+	RejectReason_REJECT_NONE           RejectReason = 0
+	RejectReason_POST_ONLY_WOULD_CROSS RejectReason = 1
+	RejectReason_SELF_TRADE_PREVENTED  RejectReason = 2
+)
+
+var RejectReason_name = map[int32]string{
+	0: "REJECT_NONE",
+	1: "POST_ONLY_WOULD_CROSS",
+	2: "SELF_TRADE_PREVENTED",
+}
+
+var RejectReason_value = map[string]int32{
+	"REJECT_NONE":           0,
+	"POST_ONLY_WOULD_CROSS": 1,
+	"SELF_TRADE_PREVENTED":  2,
+}
+
+func (x RejectReason) String() string {
+	return proto.EnumName(RejectReason_name, int32(x))
+}
+
+func (RejectReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{2}
+}
+
+// What happens when an order would trade with a resting order of the same owner, the incoming order's mode
+// decides. STP_NONE on an order means its owner's account setting, on both it lets the owner trade with themselves.
+type SelfTradePrevention int32
+
+const (
+	SelfTradePrevention_STP_NONE                 SelfTradePrevention = 0
+	SelfTradePrevention_STP_CANCEL_NEWEST        SelfTradePrevention = 1
+	SelfTradePrevention_STP_CANCEL_OLDEST        SelfTradePrevention = 2
+	SelfTradePrevention_STP_CANCEL_BOTH          SelfTradePrevention = 3
+	SelfTradePrevention_STP_DECREMENT_AND_CANCEL SelfTradePrevention = 4
+)
+
+var SelfTradePrevention_name = map[int32]string{
+	0: "STP_NONE",
+	1: "STP_CANCEL_NEWEST",
+	2: "STP_CANCEL_OLDEST",
+	3: "STP_CANCEL_BOTH",
+	4: "STP_DECREMENT_AND_CANCEL",
+}
+
+var SelfTradePrevention_value = map[string]int32{
+	"STP_NONE":                 0,
+	"STP_CANCEL_NEWEST":        1,
+	"STP_CANCEL_OLDEST":        2,
+	"STP_CANCEL_BOTH":          3,
+	"STP_DECREMENT_AND_CANCEL": 4,
+}
+
+func (x SelfTradePrevention) String() string {
+	return proto.EnumName(SelfTradePrevention_name, int32(x))
+}
+
+func (SelfTradePrevention) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{3}
+}
+
+type OrderType int32
+
+const (
+	OrderType_LIMIT       OrderType = 0
+	OrderType_MARKET      OrderType = 1
+	OrderType_STOP_MARKET OrderType = 2
+	OrderType_STOP_LIMIT  OrderType = 3
+)
+
+var OrderType_name = map[int32]string{
+	0: "LIMIT",
+	1: "MARKET",
+	2: "STOP_MARKET",
+	3: "STOP_LIMIT",
+}
+
+var OrderType_value = map[string]int32{
+	"LIMIT":       0,
+	"MARKET":      1,
+	"STOP_MARKET": 2,
+	"STOP_LIMIT":  3,
+}
+
+func (x OrderType) String() string {
+	return proto.EnumName(OrderType_name, int32(x))
+}
+
+func (OrderType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{4}
+}
+
+type OrderStatus int32
+
+const (
+	OrderStatus_NEW              OrderStatus = 0
+	OrderStatus_PARTIALLY_FILLED OrderStatus = 1
+	OrderStatus_FILLED           OrderStatus = 2
+	OrderStatus_CANCELLED        OrderStatus = 3
+	OrderStatus_EXPIRED          OrderStatus = 4
+	OrderStatus_REJECTED         OrderStatus = 5
+)
+
+var OrderStatus_name = map[int32]string{
+	0: "NEW",
+	1: "PARTIALLY_FILLED",
+	2: "FILLED",
+	3: "CANCELLED",
+	4: "EXPIRED",
+	5: "REJECTED",
+}
+
+var OrderStatus_value = map[string]int32{
+	"NEW":              0,
+	"PARTIALLY_FILLED": 1,
+	"FILLED":           2,
+	"CANCELLED":        3,
+	"EXPIRED":          4,
+	"REJECTED":         5,
+}
+
+func (x OrderStatus) String() string {
+	return proto.EnumName(OrderStatus_name, int32(x))
+}
+
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{5}
+}
+
+type TransferKind int32
+
+const (
+	TransferKind_TRANSFER_KIND_UNSPECIFIED TransferKind = 0
+	TransferKind_DEPOSIT                   TransferKind = 1
+	TransferKind_WITHDRAWAL                TransferKind = 2
+)
+
+var TransferKind_name = map[int32]string{
+	0: "TRANSFER_KIND_UNSPECIFIED",
+	1: "DEPOSIT",
+	2: "WITHDRAWAL",
+}
+
+var TransferKind_value = map[string]int32{
+	"TRANSFER_KIND_UNSPECIFIED": 0,
+	"DEPOSIT":                   1,
+	"WITHDRAWAL":                2,
+}
+
+func (x TransferKind) String() string {
+	return proto.EnumName(TransferKind_name, int32(x))
+}
+
+func (TransferKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{6}
+}
+
+type TransferStatus int32
+
+const (
+	TransferStatus_TRANSFER_ANY       TransferStatus = 0
+	TransferStatus_TRANSFER_PENDING   TransferStatus = 1
+	TransferStatus_TRANSFER_CONFIRMED TransferStatus = 2
+	TransferStatus_TRANSFER_REJECTED  TransferStatus = 3
+	TransferStatus_TRANSFER_COMPLETED TransferStatus = 4
+)
+
+var TransferStatus_name = map[int32]string{
+	0: "TRANSFER_ANY",
+	1: "TRANSFER_PENDING",
+	2: "TRANSFER_CONFIRMED",
+	3: "TRANSFER_REJECTED",
+	4: "TRANSFER_COMPLETED",
+}
+
+var TransferStatus_value = map[string]int32{
+	"TRANSFER_ANY":       0,
+	"TRANSFER_PENDING":   1,
+	"TRANSFER_CONFIRMED": 2,
+	"TRANSFER_REJECTED":  3,
+	"TRANSFER_COMPLETED": 4,
+}
+
+func (x TransferStatus) String() string {
+	return proto.EnumName(TransferStatus_name, int32(x))
+}
+
+func (TransferStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{7}
+}
+
+type ExecType int32
+
+const (
+	ExecType_EXEC_ACCEPTED  ExecType = 0
+	ExecType_EXEC_FILL      ExecType = 1
+	ExecType_EXEC_AMENDED   ExecType = 2
+	ExecType_EXEC_TRIGGERED ExecType = 3
+	ExecType_EXEC_CANCELLED ExecType = 4
+	ExecType_EXEC_EXPIRED   ExecType = 5
+	ExecType_EXEC_REJECTED  ExecType = 6
+)
+
+var ExecType_name = map[int32]string{
+	0: "EXEC_ACCEPTED",
+	1: "EXEC_FILL",
+	2: "EXEC_AMENDED",
+	3: "EXEC_TRIGGERED",
+	4: "EXEC_CANCELLED",
+	5: "EXEC_EXPIRED",
+	6: "EXEC_REJECTED",
+}
+
+var ExecType_value = map[string]int32{
+	"EXEC_ACCEPTED":  0,
+	"EXEC_FILL":      1,
+	"EXEC_AMENDED":   2,
+	"EXEC_TRIGGERED": 3,
+	"EXEC_CANCELLED": 4,
+	"EXEC_EXPIRED":   5,
+	"EXEC_REJECTED":  6,
+}
+
+func (x ExecType) String() string {
+	return proto.EnumName(ExecType_name, int32(x))
+}
+
+func (ExecType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{8}
+}
+
 type OrderInfoRequest struct {
 	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -124,17 +392,35 @@ func (m *OrderInfoReply) GetOrder() *Order {
 }
 
 type Order struct {
-	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Price                float64  `protobuf:"fixed64,2,opt,name=Price,proto3" json:"Price,omitempty"`
-	Quantity             float64  `protobuf:"fixed64,3,opt,name=Quantity,proto3" json:"Quantity,omitempty"`
-	Side                 EnumSide `protobuf:"varint,4,opt,name=Side,proto3,enum=orderbook.EnumSide" json:"Side,omitempty"`
-	Timestamp            uint32   `protobuf:"varint,5,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
-	OwnerUsername        string   `protobuf:"bytes,6,opt,name=OwnerUsername,proto3" json:"OwnerUsername,omitempty"`
-	CreatedAt            string   `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt            string   `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Id                   uint64              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Side                 EnumSide            `protobuf:"varint,4,opt,name=Side,proto3,enum=orderbook.EnumSide" json:"Side,omitempty"`
+	Timestamp            uint32              `protobuf:"varint,5,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	OwnerUsername        string              `protobuf:"bytes,6,opt,name=OwnerUsername,proto3" json:"OwnerUsername,omitempty"`
+	CreatedAt            string              `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            string              `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	TimeInForce          TimeInForce         `protobuf:"varint,9,opt,name=time_in_force,json=timeInForce,proto3,enum=orderbook.TimeInForce" json:"time_in_force,omitempty"`
+	ExpiresAt            uint32              `protobuf:"varint,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	PostOnly             bool                `protobuf:"varint,11,opt,name=post_only,json=postOnly,proto3" json:"post_only,omitempty"`
+	PostOnlyReprice      bool                `protobuf:"varint,12,opt,name=post_only_reprice,json=postOnlyReprice,proto3" json:"post_only_reprice,omitempty"`
+	RejectReason         RejectReason        `protobuf:"varint,13,opt,name=reject_reason,json=rejectReason,proto3,enum=orderbook.RejectReason" json:"reject_reason,omitempty"`
+	Symbol               string              `protobuf:"bytes,14,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Type                 OrderType           `protobuf:"varint,15,opt,name=type,proto3,enum=orderbook.OrderType" json:"type,omitempty"`
+	Status               OrderStatus         `protobuf:"varint,16,opt,name=status,proto3,enum=orderbook.OrderStatus" json:"status,omitempty"`
+	FilledQuantity       string              `protobuf:"bytes,17,opt,name=filled_quantity,json=filledQuantity,proto3" json:"filled_quantity,omitempty"`
+	StopPrice            string              `protobuf:"bytes,18,opt,name=stop_price,json=stopPrice,proto3" json:"stop_price,omitempty"`
+	ProtectionPrice      string              `protobuf:"bytes,19,opt,name=protection_price,json=protectionPrice,proto3" json:"protection_price,omitempty"`
+	MaxSlippageBps       uint32              `protobuf:"varint,20,opt,name=max_slippage_bps,json=maxSlippageBps,proto3" json:"max_slippage_bps,omitempty"`
+	DisplayQuantity      string              `protobuf:"bytes,21,opt,name=display_quantity,json=displayQuantity,proto3" json:"display_quantity,omitempty"`
+	Triggered            bool                `protobuf:"varint,22,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	Held                 string              `protobuf:"bytes,23,opt,name=held,proto3" json:"held,omitempty"`
+	MakerFeeBps          string              `protobuf:"bytes,24,opt,name=maker_fee_bps,json=makerFeeBps,proto3" json:"maker_fee_bps,omitempty"`
+	TakerFeeBps          string              `protobuf:"bytes,25,opt,name=taker_fee_bps,json=takerFeeBps,proto3" json:"taker_fee_bps,omitempty"`
+	SelfTradePrevention  SelfTradePrevention `protobuf:"varint,26,opt,name=self_trade_prevention,json=selfTradePrevention,proto3,enum=orderbook.SelfTradePrevention" json:"self_trade_prevention,omitempty"`
+	Price                string              `protobuf:"bytes,27,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string              `protobuf:"bytes,28,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *Order) Reset()         { *m = Order{} }
@@ -169,20 +455,6 @@ func (m *Order) GetId() uint64 {
 	return 0
 }
 
-func (m *Order) GetPrice() float64 {
-	if m != nil {
-		return m.Price
-	}
-	return 0
-}
-
-func (m *Order) GetQuantity() float64 {
-	if m != nil {
-		return m.Quantity
-	}
-	return 0
-}
-
 func (m *Order) GetSide() EnumSide {
 	if m != nil {
 		return m.Side
@@ -218,91 +490,2639 @@ func (m *Order) GetUpdatedAt() string {
 	return ""
 }
 
-type GreetingServiceRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Order) GetTimeInForce() TimeInForce {
+	if m != nil {
+		return m.TimeInForce
+	}
+	return TimeInForce_GTC
 }
 
-func (m *GreetingServiceRequest) Reset()         { *m = GreetingServiceRequest{} }
-func (m *GreetingServiceRequest) String() string { return proto.CompactTextString(m) }
-func (*GreetingServiceRequest) ProtoMessage()    {}
-func (*GreetingServiceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6b2982dec9a4117, []int{3}
+func (m *Order) GetExpiresAt() uint32 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
 }
 
-func (m *GreetingServiceRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GreetingServiceRequest.Unmarshal(m, b)
+func (m *Order) GetPostOnly() bool {
+	if m != nil {
+		return m.PostOnly
+	}
+	return false
 }
-func (m *GreetingServiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GreetingServiceRequest.Marshal(b, m, deterministic)
+
+func (m *Order) GetPostOnlyReprice() bool {
+	if m != nil {
+		return m.PostOnlyReprice
+	}
+	return false
 }
-func (m *GreetingServiceRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GreetingServiceRequest.Merge(m, src)
+
+func (m *Order) GetRejectReason() RejectReason {
+	if m != nil {
+		return m.RejectReason
+	}
+	return RejectReason_REJECT_NONE
 }
-func (m *GreetingServiceRequest) XXX_Size() int {
-	return xxx_messageInfo_GreetingServiceRequest.Size(m)
+
+func (m *Order) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
 }
-func (m *GreetingServiceRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GreetingServiceRequest.DiscardUnknown(m)
+
+func (m *Order) GetType() OrderType {
+	if m != nil {
+		return m.Type
+	}
+	return OrderType_LIMIT
 }
 
-var xxx_messageInfo_GreetingServiceRequest proto.InternalMessageInfo
+func (m *Order) GetStatus() OrderStatus {
+	if m != nil {
+		return m.Status
+	}
+	return OrderStatus_NEW
+}
 
-func (m *GreetingServiceRequest) GetName() string {
+func (m *Order) GetFilledQuantity() string {
 	if m != nil {
-		return m.Name
+		return m.FilledQuantity
 	}
 	return ""
 }
 
-type GreetingServiceReply struct {
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+func (m *Order) GetStopPrice() string {
+	if m != nil {
+		return m.StopPrice
+	}
+	return ""
+}
+
+func (m *Order) GetProtectionPrice() string {
+	if m != nil {
+		return m.ProtectionPrice
+	}
+	return ""
+}
+
+func (m *Order) GetMaxSlippageBps() uint32 {
+	if m != nil {
+		return m.MaxSlippageBps
+	}
+	return 0
+}
+
+func (m *Order) GetDisplayQuantity() string {
+	if m != nil {
+		return m.DisplayQuantity
+	}
+	return ""
+}
+
+func (m *Order) GetTriggered() bool {
+	if m != nil {
+		return m.Triggered
+	}
+	return false
+}
+
+func (m *Order) GetHeld() string {
+	if m != nil {
+		return m.Held
+	}
+	return ""
+}
+
+func (m *Order) GetMakerFeeBps() string {
+	if m != nil {
+		return m.MakerFeeBps
+	}
+	return ""
+}
+
+func (m *Order) GetTakerFeeBps() string {
+	if m != nil {
+		return m.TakerFeeBps
+	}
+	return ""
+}
+
+func (m *Order) GetSelfTradePrevention() SelfTradePrevention {
+	if m != nil {
+		return m.SelfTradePrevention
+	}
+	return SelfTradePrevention_STP_NONE
+}
+
+func (m *Order) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *Order) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+type Trade struct {
+	Symbol               string   `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	MakerOrderId         uint64   `protobuf:"varint,2,opt,name=maker_order_id,json=makerOrderId,proto3" json:"maker_order_id,omitempty"`
+	TakerOrderId         uint64   `protobuf:"varint,3,opt,name=taker_order_id,json=takerOrderId,proto3" json:"taker_order_id,omitempty"`
+	Price                string   `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string   `protobuf:"bytes,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	AggressorSide        EnumSide `protobuf:"varint,6,opt,name=aggressor_side,json=aggressorSide,proto3,enum=orderbook.EnumSide" json:"aggressor_side,omitempty"`
+	Timestamp            uint32   `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Sequence             uint64   `protobuf:"varint,8,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	MakerFee             string   `protobuf:"bytes,9,opt,name=maker_fee,json=makerFee,proto3" json:"maker_fee,omitempty"`
+	TakerFee             string   `protobuf:"bytes,10,opt,name=taker_fee,json=takerFee,proto3" json:"taker_fee,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GreetingServiceReply) Reset()         { *m = GreetingServiceReply{} }
-func (m *GreetingServiceReply) String() string { return proto.CompactTextString(m) }
-func (*GreetingServiceReply) ProtoMessage()    {}
-func (*GreetingServiceReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6b2982dec9a4117, []int{4}
+func (m *Trade) Reset()         { *m = Trade{} }
+func (m *Trade) String() string { return proto.CompactTextString(m) }
+func (*Trade) ProtoMessage()    {}
+func (*Trade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{3}
 }
 
-func (m *GreetingServiceReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GreetingServiceReply.Unmarshal(m, b)
+func (m *Trade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Trade.Unmarshal(m, b)
 }
-func (m *GreetingServiceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GreetingServiceReply.Marshal(b, m, deterministic)
+func (m *Trade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Trade.Marshal(b, m, deterministic)
 }
-func (m *GreetingServiceReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GreetingServiceReply.Merge(m, src)
+func (m *Trade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trade.Merge(m, src)
 }
-func (m *GreetingServiceReply) XXX_Size() int {
-	return xxx_messageInfo_GreetingServiceReply.Size(m)
+func (m *Trade) XXX_Size() int {
+	return xxx_messageInfo_Trade.Size(m)
 }
-func (m *GreetingServiceReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_GreetingServiceReply.DiscardUnknown(m)
+func (m *Trade) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trade.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GreetingServiceReply proto.InternalMessageInfo
+var xxx_messageInfo_Trade proto.InternalMessageInfo
 
-func (m *GreetingServiceReply) GetMessage() string {
+func (m *Trade) GetSymbol() string {
 	if m != nil {
-		return m.Message
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *Trade) GetMakerOrderId() uint64 {
+	if m != nil {
+		return m.MakerOrderId
+	}
+	return 0
+}
+
+func (m *Trade) GetTakerOrderId() uint64 {
+	if m != nil {
+		return m.TakerOrderId
+	}
+	return 0
+}
+
+func (m *Trade) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *Trade) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *Trade) GetAggressorSide() EnumSide {
+	if m != nil {
+		return m.AggressorSide
+	}
+	return EnumSide_BUY
+}
+
+func (m *Trade) GetTimestamp() uint32 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Trade) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Trade) GetMakerFee() string {
+	if m != nil {
+		return m.MakerFee
+	}
+	return ""
+}
+
+func (m *Trade) GetTakerFee() string {
+	if m != nil {
+		return m.TakerFee
+	}
+	return ""
+}
+
+// A trade seen from one of its two sides, with the fee that side paid (quote asset).
+type OwnTrade struct {
+	Trade                *Trade   `protobuf:"bytes,1,opt,name=trade,proto3" json:"trade,omitempty"`
+	OrderId              uint64   `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Side                 EnumSide `protobuf:"varint,3,opt,name=side,proto3,enum=orderbook.EnumSide" json:"side,omitempty"`
+	Maker                bool     `protobuf:"varint,4,opt,name=maker,proto3" json:"maker,omitempty"`
+	Fee                  string   `protobuf:"bytes,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OwnTrade) Reset()         { *m = OwnTrade{} }
+func (m *OwnTrade) String() string { return proto.CompactTextString(m) }
+func (*OwnTrade) ProtoMessage()    {}
+func (*OwnTrade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{4}
+}
+
+func (m *OwnTrade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OwnTrade.Unmarshal(m, b)
+}
+func (m *OwnTrade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OwnTrade.Marshal(b, m, deterministic)
+}
+func (m *OwnTrade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OwnTrade.Merge(m, src)
+}
+func (m *OwnTrade) XXX_Size() int {
+	return xxx_messageInfo_OwnTrade.Size(m)
+}
+func (m *OwnTrade) XXX_DiscardUnknown() {
+	xxx_messageInfo_OwnTrade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OwnTrade proto.InternalMessageInfo
+
+func (m *OwnTrade) GetTrade() *Trade {
+	if m != nil {
+		return m.Trade
+	}
+	return nil
+}
+
+func (m *OwnTrade) GetOrderId() uint64 {
+	if m != nil {
+		return m.OrderId
+	}
+	return 0
+}
+
+func (m *OwnTrade) GetSide() EnumSide {
+	if m != nil {
+		return m.Side
+	}
+	return EnumSide_BUY
+}
+
+func (m *OwnTrade) GetMaker() bool {
+	if m != nil {
+		return m.Maker
+	}
+	return false
+}
+
+func (m *OwnTrade) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+// Amounts are decimal strings, optional ones may be left empty.
+type PlaceOrderRequest struct {
+	Symbol               string              `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side                 EnumSide            `protobuf:"varint,2,opt,name=side,proto3,enum=orderbook.EnumSide" json:"side,omitempty"`
+	Type                 OrderType           `protobuf:"varint,3,opt,name=type,proto3,enum=orderbook.OrderType" json:"type,omitempty"`
+	Price                string              `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string              `protobuf:"bytes,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	TimeInForce          TimeInForce         `protobuf:"varint,6,opt,name=time_in_force,json=timeInForce,proto3,enum=orderbook.TimeInForce" json:"time_in_force,omitempty"`
+	ExpiresAt            uint32              `protobuf:"varint,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	PostOnly             bool                `protobuf:"varint,8,opt,name=post_only,json=postOnly,proto3" json:"post_only,omitempty"`
+	PostOnlyReprice      bool                `protobuf:"varint,9,opt,name=post_only_reprice,json=postOnlyReprice,proto3" json:"post_only_reprice,omitempty"`
+	StopPrice            string              `protobuf:"bytes,10,opt,name=stop_price,json=stopPrice,proto3" json:"stop_price,omitempty"`
+	ProtectionPrice      string              `protobuf:"bytes,11,opt,name=protection_price,json=protectionPrice,proto3" json:"protection_price,omitempty"`
+	MaxSlippageBps       uint32              `protobuf:"varint,12,opt,name=max_slippage_bps,json=maxSlippageBps,proto3" json:"max_slippage_bps,omitempty"`
+	DisplayQuantity      string              `protobuf:"bytes,13,opt,name=display_quantity,json=displayQuantity,proto3" json:"display_quantity,omitempty"`
+	OwnerUsername        string              `protobuf:"bytes,14,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	SelfTradePrevention  SelfTradePrevention `protobuf:"varint,15,opt,name=self_trade_prevention,json=selfTradePrevention,proto3,enum=orderbook.SelfTradePrevention" json:"self_trade_prevention,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PlaceOrderRequest) Reset()         { *m = PlaceOrderRequest{} }
+func (m *PlaceOrderRequest) String() string { return proto.CompactTextString(m) }
+func (*PlaceOrderRequest) ProtoMessage()    {}
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{5}
+}
+
+func (m *PlaceOrderRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlaceOrderRequest.Unmarshal(m, b)
+}
+func (m *PlaceOrderRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlaceOrderRequest.Marshal(b, m, deterministic)
+}
+func (m *PlaceOrderRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlaceOrderRequest.Merge(m, src)
+}
+func (m *PlaceOrderRequest) XXX_Size() int {
+	return xxx_messageInfo_PlaceOrderRequest.Size(m)
+}
+func (m *PlaceOrderRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlaceOrderRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlaceOrderRequest proto.InternalMessageInfo
+
+func (m *PlaceOrderRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetSide() EnumSide {
+	if m != nil {
+		return m.Side
+	}
+	return EnumSide_BUY
+}
+
+func (m *PlaceOrderRequest) GetType() OrderType {
+	if m != nil {
+		return m.Type
+	}
+	return OrderType_LIMIT
+}
+
+func (m *PlaceOrderRequest) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetTimeInForce() TimeInForce {
+	if m != nil {
+		return m.TimeInForce
+	}
+	return TimeInForce_GTC
+}
+
+func (m *PlaceOrderRequest) GetExpiresAt() uint32 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *PlaceOrderRequest) GetPostOnly() bool {
+	if m != nil {
+		return m.PostOnly
+	}
+	return false
+}
+
+func (m *PlaceOrderRequest) GetPostOnlyReprice() bool {
+	if m != nil {
+		return m.PostOnlyReprice
+	}
+	return false
+}
+
+func (m *PlaceOrderRequest) GetStopPrice() string {
+	if m != nil {
+		return m.StopPrice
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetProtectionPrice() string {
+	if m != nil {
+		return m.ProtectionPrice
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetMaxSlippageBps() uint32 {
+	if m != nil {
+		return m.MaxSlippageBps
+	}
+	return 0
+}
+
+func (m *PlaceOrderRequest) GetDisplayQuantity() string {
+	if m != nil {
+		return m.DisplayQuantity
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *PlaceOrderRequest) GetSelfTradePrevention() SelfTradePrevention {
+	if m != nil {
+		return m.SelfTradePrevention
+	}
+	return SelfTradePrevention_STP_NONE
+}
+
+// A rejected order (e.g. post-only that would cross) comes back with status REJECTED and its reject_reason.
+type PlaceOrderReply struct {
+	Order                *Order   `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Fills                []*Trade `protobuf:"bytes,2,rep,name=fills,proto3" json:"fills,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlaceOrderReply) Reset()         { *m = PlaceOrderReply{} }
+func (m *PlaceOrderReply) String() string { return proto.CompactTextString(m) }
+func (*PlaceOrderReply) ProtoMessage()    {}
+func (*PlaceOrderReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{6}
+}
+
+func (m *PlaceOrderReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlaceOrderReply.Unmarshal(m, b)
+}
+func (m *PlaceOrderReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlaceOrderReply.Marshal(b, m, deterministic)
+}
+func (m *PlaceOrderReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlaceOrderReply.Merge(m, src)
+}
+func (m *PlaceOrderReply) XXX_Size() int {
+	return xxx_messageInfo_PlaceOrderReply.Size(m)
+}
+func (m *PlaceOrderReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlaceOrderReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlaceOrderReply proto.InternalMessageInfo
+
+func (m *PlaceOrderReply) GetOrder() *Order {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *PlaceOrderReply) GetFills() []*Trade {
+	if m != nil {
+		return m.Fills
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OwnerUsername        string   `protobuf:"bytes,2,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelOrderRequest) Reset()         { *m = CancelOrderRequest{} }
+func (m *CancelOrderRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelOrderRequest) ProtoMessage()    {}
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{7}
+}
+
+func (m *CancelOrderRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOrderRequest.Unmarshal(m, b)
+}
+func (m *CancelOrderRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOrderRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelOrderRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOrderRequest.Merge(m, src)
+}
+func (m *CancelOrderRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelOrderRequest.Size(m)
+}
+func (m *CancelOrderRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOrderRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOrderRequest proto.InternalMessageInfo
+
+func (m *CancelOrderRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CancelOrderRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *CancelOrderRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+type CancelOrderReply struct {
+	Order                *Order   `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelOrderReply) Reset()         { *m = CancelOrderReply{} }
+func (m *CancelOrderReply) String() string { return proto.CompactTextString(m) }
+func (*CancelOrderReply) ProtoMessage()    {}
+func (*CancelOrderReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{8}
+}
+
+func (m *CancelOrderReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOrderReply.Unmarshal(m, b)
+}
+func (m *CancelOrderReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOrderReply.Marshal(b, m, deterministic)
+}
+func (m *CancelOrderReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOrderReply.Merge(m, src)
+}
+func (m *CancelOrderReply) XXX_Size() int {
+	return xxx_messageInfo_CancelOrderReply.Size(m)
+}
+func (m *CancelOrderReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOrderReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOrderReply proto.InternalMessageInfo
+
+func (m *CancelOrderReply) GetOrder() *Order {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+type AmendOrderRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Price                string   `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string   `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OwnerUsername        string   `protobuf:"bytes,4,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AmendOrderRequest) Reset()         { *m = AmendOrderRequest{} }
+func (m *AmendOrderRequest) String() string { return proto.CompactTextString(m) }
+func (*AmendOrderRequest) ProtoMessage()    {}
+func (*AmendOrderRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{9}
+}
+
+func (m *AmendOrderRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AmendOrderRequest.Unmarshal(m, b)
+}
+func (m *AmendOrderRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AmendOrderRequest.Marshal(b, m, deterministic)
+}
+func (m *AmendOrderRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AmendOrderRequest.Merge(m, src)
+}
+func (m *AmendOrderRequest) XXX_Size() int {
+	return xxx_messageInfo_AmendOrderRequest.Size(m)
+}
+func (m *AmendOrderRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AmendOrderRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AmendOrderRequest proto.InternalMessageInfo
+
+func (m *AmendOrderRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AmendOrderRequest) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *AmendOrderRequest) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *AmendOrderRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *AmendOrderRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+type AmendOrderReply struct {
+	Order                *Order   `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Fills                []*Trade `protobuf:"bytes,2,rep,name=fills,proto3" json:"fills,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AmendOrderReply) Reset()         { *m = AmendOrderReply{} }
+func (m *AmendOrderReply) String() string { return proto.CompactTextString(m) }
+func (*AmendOrderReply) ProtoMessage()    {}
+func (*AmendOrderReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{10}
+}
+
+func (m *AmendOrderReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AmendOrderReply.Unmarshal(m, b)
+}
+func (m *AmendOrderReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AmendOrderReply.Marshal(b, m, deterministic)
+}
+func (m *AmendOrderReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AmendOrderReply.Merge(m, src)
+}
+func (m *AmendOrderReply) XXX_Size() int {
+	return xxx_messageInfo_AmendOrderReply.Size(m)
+}
+func (m *AmendOrderReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AmendOrderReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AmendOrderReply proto.InternalMessageInfo
+
+func (m *AmendOrderReply) GetOrder() *Order {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *AmendOrderReply) GetFills() []*Trade {
+	if m != nil {
+		return m.Fills
+	}
+	return nil
+}
+
+type ListOpenOrdersRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOpenOrdersRequest) Reset()         { *m = ListOpenOrdersRequest{} }
+func (m *ListOpenOrdersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOpenOrdersRequest) ProtoMessage()    {}
+func (*ListOpenOrdersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{11}
+}
+
+func (m *ListOpenOrdersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOpenOrdersRequest.Unmarshal(m, b)
+}
+func (m *ListOpenOrdersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOpenOrdersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOpenOrdersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOpenOrdersRequest.Merge(m, src)
+}
+func (m *ListOpenOrdersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOpenOrdersRequest.Size(m)
+}
+func (m *ListOpenOrdersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOpenOrdersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOpenOrdersRequest proto.InternalMessageInfo
+
+func (m *ListOpenOrdersRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *ListOpenOrdersRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+type ListOpenOrdersReply struct {
+	Orders               []*Order `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOpenOrdersReply) Reset()         { *m = ListOpenOrdersReply{} }
+func (m *ListOpenOrdersReply) String() string { return proto.CompactTextString(m) }
+func (*ListOpenOrdersReply) ProtoMessage()    {}
+func (*ListOpenOrdersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{12}
+}
+
+func (m *ListOpenOrdersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOpenOrdersReply.Unmarshal(m, b)
+}
+func (m *ListOpenOrdersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOpenOrdersReply.Marshal(b, m, deterministic)
+}
+func (m *ListOpenOrdersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOpenOrdersReply.Merge(m, src)
+}
+func (m *ListOpenOrdersReply) XXX_Size() int {
+	return xxx_messageInfo_ListOpenOrdersReply.Size(m)
+}
+func (m *ListOpenOrdersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOpenOrdersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOpenOrdersReply proto.InternalMessageInfo
+
+func (m *ListOpenOrdersReply) GetOrders() []*Order {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+// Newest first. page_token is the next_page_token of the previous page, empty for the first one.
+type OrderHistoryRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	PageSize             uint32   `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderHistoryRequest) Reset()         { *m = OrderHistoryRequest{} }
+func (m *OrderHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*OrderHistoryRequest) ProtoMessage()    {}
+func (*OrderHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{13}
+}
+
+func (m *OrderHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderHistoryRequest.Unmarshal(m, b)
+}
+func (m *OrderHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *OrderHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderHistoryRequest.Merge(m, src)
+}
+func (m *OrderHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_OrderHistoryRequest.Size(m)
+}
+func (m *OrderHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderHistoryRequest proto.InternalMessageInfo
+
+func (m *OrderHistoryRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *OrderHistoryRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *OrderHistoryRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *OrderHistoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type OrderHistoryReply struct {
+	Orders               []*Order `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderHistoryReply) Reset()         { *m = OrderHistoryReply{} }
+func (m *OrderHistoryReply) String() string { return proto.CompactTextString(m) }
+func (*OrderHistoryReply) ProtoMessage()    {}
+func (*OrderHistoryReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{14}
+}
+
+func (m *OrderHistoryReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderHistoryReply.Unmarshal(m, b)
+}
+func (m *OrderHistoryReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderHistoryReply.Marshal(b, m, deterministic)
+}
+func (m *OrderHistoryReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderHistoryReply.Merge(m, src)
+}
+func (m *OrderHistoryReply) XXX_Size() int {
+	return xxx_messageInfo_OrderHistoryReply.Size(m)
+}
+func (m *OrderHistoryReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderHistoryReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderHistoryReply proto.InternalMessageInfo
+
+func (m *OrderHistoryReply) GetOrders() []*Order {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+func (m *OrderHistoryReply) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// Newest first, paginated like OrderHistoryRequest.
+type TradeHistoryRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	PageSize             uint32   `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TradeHistoryRequest) Reset()         { *m = TradeHistoryRequest{} }
+func (m *TradeHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*TradeHistoryRequest) ProtoMessage()    {}
+func (*TradeHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{15}
+}
+
+func (m *TradeHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradeHistoryRequest.Unmarshal(m, b)
+}
+func (m *TradeHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradeHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *TradeHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradeHistoryRequest.Merge(m, src)
+}
+func (m *TradeHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_TradeHistoryRequest.Size(m)
+}
+func (m *TradeHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradeHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradeHistoryRequest proto.InternalMessageInfo
+
+func (m *TradeHistoryRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *TradeHistoryRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *TradeHistoryRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *TradeHistoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type TradeHistoryReply struct {
+	Trades               []*OwnTrade `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	NextPageToken        string      `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TradeHistoryReply) Reset()         { *m = TradeHistoryReply{} }
+func (m *TradeHistoryReply) String() string { return proto.CompactTextString(m) }
+func (*TradeHistoryReply) ProtoMessage()    {}
+func (*TradeHistoryReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{16}
+}
+
+func (m *TradeHistoryReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradeHistoryReply.Unmarshal(m, b)
+}
+func (m *TradeHistoryReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradeHistoryReply.Marshal(b, m, deterministic)
+}
+func (m *TradeHistoryReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradeHistoryReply.Merge(m, src)
+}
+func (m *TradeHistoryReply) XXX_Size() int {
+	return xxx_messageInfo_TradeHistoryReply.Size(m)
+}
+func (m *TradeHistoryReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradeHistoryReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradeHistoryReply proto.InternalMessageInfo
+
+func (m *TradeHistoryReply) GetTrades() []*OwnTrade {
+	if m != nil {
+		return m.Trades
+	}
+	return nil
+}
+
+func (m *TradeHistoryReply) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type FeeRatesRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Symbol               string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeeRatesRequest) Reset()         { *m = FeeRatesRequest{} }
+func (m *FeeRatesRequest) String() string { return proto.CompactTextString(m) }
+func (*FeeRatesRequest) ProtoMessage()    {}
+func (*FeeRatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{17}
+}
+
+func (m *FeeRatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeeRatesRequest.Unmarshal(m, b)
+}
+func (m *FeeRatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeeRatesRequest.Marshal(b, m, deterministic)
+}
+func (m *FeeRatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeeRatesRequest.Merge(m, src)
+}
+func (m *FeeRatesRequest) XXX_Size() int {
+	return xxx_messageInfo_FeeRatesRequest.Size(m)
+}
+func (m *FeeRatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeeRatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeeRatesRequest proto.InternalMessageInfo
+
+func (m *FeeRatesRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *FeeRatesRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+// The rates new orders get: the tier of volume_30d (quote asset), traded in the market over the last 30 days.
+type FeeRatesReply struct {
+	Symbol               string   `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Volume_30D           string   `protobuf:"bytes,2,opt,name=volume_30d,json=volume30d,proto3" json:"volume_30d,omitempty"`
+	MakerBps             string   `protobuf:"bytes,3,opt,name=maker_bps,json=makerBps,proto3" json:"maker_bps,omitempty"`
+	TakerBps             string   `protobuf:"bytes,4,opt,name=taker_bps,json=takerBps,proto3" json:"taker_bps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeeRatesReply) Reset()         { *m = FeeRatesReply{} }
+func (m *FeeRatesReply) String() string { return proto.CompactTextString(m) }
+func (*FeeRatesReply) ProtoMessage()    {}
+func (*FeeRatesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{18}
+}
+
+func (m *FeeRatesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeeRatesReply.Unmarshal(m, b)
+}
+func (m *FeeRatesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeeRatesReply.Marshal(b, m, deterministic)
+}
+func (m *FeeRatesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeeRatesReply.Merge(m, src)
+}
+func (m *FeeRatesReply) XXX_Size() int {
+	return xxx_messageInfo_FeeRatesReply.Size(m)
+}
+func (m *FeeRatesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeeRatesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeeRatesReply proto.InternalMessageInfo
+
+func (m *FeeRatesReply) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *FeeRatesReply) GetVolume_30D() string {
+	if m != nil {
+		return m.Volume_30D
+	}
+	return ""
+}
+
+func (m *FeeRatesReply) GetMakerBps() string {
+	if m != nil {
+		return m.MakerBps
+	}
+	return ""
+}
+
+func (m *FeeRatesReply) GetTakerBps() string {
+	if m != nil {
+		return m.TakerBps
+	}
+	return ""
+}
+
+type GetSelfTradePreventionRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSelfTradePreventionRequest) Reset()         { *m = GetSelfTradePreventionRequest{} }
+func (m *GetSelfTradePreventionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSelfTradePreventionRequest) ProtoMessage()    {}
+func (*GetSelfTradePreventionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{19}
+}
+
+func (m *GetSelfTradePreventionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSelfTradePreventionRequest.Unmarshal(m, b)
+}
+func (m *GetSelfTradePreventionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSelfTradePreventionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSelfTradePreventionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSelfTradePreventionRequest.Merge(m, src)
+}
+func (m *GetSelfTradePreventionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSelfTradePreventionRequest.Size(m)
+}
+func (m *GetSelfTradePreventionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSelfTradePreventionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSelfTradePreventionRequest proto.InternalMessageInfo
+
+func (m *GetSelfTradePreventionRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+// The account setting applies to orders placed after it's set, open orders keep the mode they were placed with.
+type SetSelfTradePreventionRequest struct {
+	OwnerUsername        string              `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Mode                 SelfTradePrevention `protobuf:"varint,2,opt,name=mode,proto3,enum=orderbook.SelfTradePrevention" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SetSelfTradePreventionRequest) Reset()         { *m = SetSelfTradePreventionRequest{} }
+func (m *SetSelfTradePreventionRequest) String() string { return proto.CompactTextString(m) }
+func (*SetSelfTradePreventionRequest) ProtoMessage()    {}
+func (*SetSelfTradePreventionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{20}
+}
+
+func (m *SetSelfTradePreventionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSelfTradePreventionRequest.Unmarshal(m, b)
+}
+func (m *SetSelfTradePreventionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSelfTradePreventionRequest.Marshal(b, m, deterministic)
+}
+func (m *SetSelfTradePreventionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSelfTradePreventionRequest.Merge(m, src)
+}
+func (m *SetSelfTradePreventionRequest) XXX_Size() int {
+	return xxx_messageInfo_SetSelfTradePreventionRequest.Size(m)
+}
+func (m *SetSelfTradePreventionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSelfTradePreventionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSelfTradePreventionRequest proto.InternalMessageInfo
+
+func (m *SetSelfTradePreventionRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *SetSelfTradePreventionRequest) GetMode() SelfTradePrevention {
+	if m != nil {
+		return m.Mode
+	}
+	return SelfTradePrevention_STP_NONE
+}
+
+type SelfTradePreventionReply struct {
+	OwnerUsername        string              `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Mode                 SelfTradePrevention `protobuf:"varint,2,opt,name=mode,proto3,enum=orderbook.SelfTradePrevention" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *SelfTradePreventionReply) Reset()         { *m = SelfTradePreventionReply{} }
+func (m *SelfTradePreventionReply) String() string { return proto.CompactTextString(m) }
+func (*SelfTradePreventionReply) ProtoMessage()    {}
+func (*SelfTradePreventionReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{21}
+}
+
+func (m *SelfTradePreventionReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SelfTradePreventionReply.Unmarshal(m, b)
+}
+func (m *SelfTradePreventionReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SelfTradePreventionReply.Marshal(b, m, deterministic)
+}
+func (m *SelfTradePreventionReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SelfTradePreventionReply.Merge(m, src)
+}
+func (m *SelfTradePreventionReply) XXX_Size() int {
+	return xxx_messageInfo_SelfTradePreventionReply.Size(m)
+}
+func (m *SelfTradePreventionReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SelfTradePreventionReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SelfTradePreventionReply proto.InternalMessageInfo
+
+func (m *SelfTradePreventionReply) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *SelfTradePreventionReply) GetMode() SelfTradePrevention {
+	if m != nil {
+		return m.Mode
+	}
+	return SelfTradePrevention_STP_NONE
+}
+
+type GreetingServiceRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GreetingServiceRequest) Reset()         { *m = GreetingServiceRequest{} }
+func (m *GreetingServiceRequest) String() string { return proto.CompactTextString(m) }
+func (*GreetingServiceRequest) ProtoMessage()    {}
+func (*GreetingServiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{22}
+}
+
+func (m *GreetingServiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GreetingServiceRequest.Unmarshal(m, b)
+}
+func (m *GreetingServiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GreetingServiceRequest.Marshal(b, m, deterministic)
+}
+func (m *GreetingServiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GreetingServiceRequest.Merge(m, src)
+}
+func (m *GreetingServiceRequest) XXX_Size() int {
+	return xxx_messageInfo_GreetingServiceRequest.Size(m)
+}
+func (m *GreetingServiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GreetingServiceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GreetingServiceRequest proto.InternalMessageInfo
+
+func (m *GreetingServiceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GreetingServiceReply struct {
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GreetingServiceReply) Reset()         { *m = GreetingServiceReply{} }
+func (m *GreetingServiceReply) String() string { return proto.CompactTextString(m) }
+func (*GreetingServiceReply) ProtoMessage()    {}
+func (*GreetingServiceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{23}
+}
+
+func (m *GreetingServiceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GreetingServiceReply.Unmarshal(m, b)
+}
+func (m *GreetingServiceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GreetingServiceReply.Marshal(b, m, deterministic)
+}
+func (m *GreetingServiceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GreetingServiceReply.Merge(m, src)
+}
+func (m *GreetingServiceReply) XXX_Size() int {
+	return xxx_messageInfo_GreetingServiceReply.Size(m)
+}
+func (m *GreetingServiceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GreetingServiceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GreetingServiceReply proto.InternalMessageInfo
+
+func (m *GreetingServiceReply) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type RecentTradesRequest struct {
+	Symbol               string   `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Limit                uint32   `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecentTradesRequest) Reset()         { *m = RecentTradesRequest{} }
+func (m *RecentTradesRequest) String() string { return proto.CompactTextString(m) }
+func (*RecentTradesRequest) ProtoMessage()    {}
+func (*RecentTradesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{24}
+}
+
+func (m *RecentTradesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecentTradesRequest.Unmarshal(m, b)
+}
+func (m *RecentTradesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecentTradesRequest.Marshal(b, m, deterministic)
+}
+func (m *RecentTradesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecentTradesRequest.Merge(m, src)
+}
+func (m *RecentTradesRequest) XXX_Size() int {
+	return xxx_messageInfo_RecentTradesRequest.Size(m)
+}
+func (m *RecentTradesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecentTradesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecentTradesRequest proto.InternalMessageInfo
+
+func (m *RecentTradesRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *RecentTradesRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// Newest first.
+type RecentTradesReply struct {
+	Trades               []*Trade `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecentTradesReply) Reset()         { *m = RecentTradesReply{} }
+func (m *RecentTradesReply) String() string { return proto.CompactTextString(m) }
+func (*RecentTradesReply) ProtoMessage()    {}
+func (*RecentTradesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{25}
+}
+
+func (m *RecentTradesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecentTradesReply.Unmarshal(m, b)
+}
+func (m *RecentTradesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecentTradesReply.Marshal(b, m, deterministic)
+}
+func (m *RecentTradesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecentTradesReply.Merge(m, src)
+}
+func (m *RecentTradesReply) XXX_Size() int {
+	return xxx_messageInfo_RecentTradesReply.Size(m)
+}
+func (m *RecentTradesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecentTradesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecentTradesReply proto.InternalMessageInfo
+
+func (m *RecentTradesReply) GetTrades() []*Trade {
+	if m != nil {
+		return m.Trades
+	}
+	return nil
+}
+
+type MarketDataRequest struct {
+	Symbol               string   `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth                uint32   `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MarketDataRequest) Reset()         { *m = MarketDataRequest{} }
+func (m *MarketDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MarketDataRequest) ProtoMessage()    {}
+func (*MarketDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{26}
+}
+
+func (m *MarketDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MarketDataRequest.Unmarshal(m, b)
+}
+func (m *MarketDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MarketDataRequest.Marshal(b, m, deterministic)
+}
+func (m *MarketDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MarketDataRequest.Merge(m, src)
+}
+func (m *MarketDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MarketDataRequest.Size(m)
+}
+func (m *MarketDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MarketDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MarketDataRequest proto.InternalMessageInfo
+
+func (m *MarketDataRequest) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *MarketDataRequest) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+type PriceLevel struct {
+	Price                string   `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string   `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OrderCount           uint32   `protobuf:"varint,3,opt,name=order_count,json=orderCount,proto3" json:"order_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PriceLevel) Reset()         { *m = PriceLevel{} }
+func (m *PriceLevel) String() string { return proto.CompactTextString(m) }
+func (*PriceLevel) ProtoMessage()    {}
+func (*PriceLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{27}
+}
+
+func (m *PriceLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PriceLevel.Unmarshal(m, b)
+}
+func (m *PriceLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PriceLevel.Marshal(b, m, deterministic)
+}
+func (m *PriceLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriceLevel.Merge(m, src)
+}
+func (m *PriceLevel) XXX_Size() int {
+	return xxx_messageInfo_PriceLevel.Size(m)
+}
+func (m *PriceLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriceLevel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriceLevel proto.InternalMessageInfo
+
+func (m *PriceLevel) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *PriceLevel) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *PriceLevel) GetOrderCount() uint32 {
+	if m != nil {
+		return m.OrderCount
+	}
+	return 0
+}
+
+type DepthSnapshot struct {
+	Bids                 []*PriceLevel `protobuf:"bytes,1,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks                 []*PriceLevel `protobuf:"bytes,2,rep,name=asks,proto3" json:"asks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DepthSnapshot) Reset()         { *m = DepthSnapshot{} }
+func (m *DepthSnapshot) String() string { return proto.CompactTextString(m) }
+func (*DepthSnapshot) ProtoMessage()    {}
+func (*DepthSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{28}
+}
+
+func (m *DepthSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DepthSnapshot.Unmarshal(m, b)
+}
+func (m *DepthSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DepthSnapshot.Marshal(b, m, deterministic)
+}
+func (m *DepthSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DepthSnapshot.Merge(m, src)
+}
+func (m *DepthSnapshot) XXX_Size() int {
+	return xxx_messageInfo_DepthSnapshot.Size(m)
+}
+func (m *DepthSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_DepthSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DepthSnapshot proto.InternalMessageInfo
+
+func (m *DepthSnapshot) GetBids() []*PriceLevel {
+	if m != nil {
+		return m.Bids
+	}
+	return nil
+}
+
+func (m *DepthSnapshot) GetAsks() []*PriceLevel {
+	if m != nil {
+		return m.Asks
+	}
+	return nil
+}
+
+type LevelUpdate struct {
+	Side                 EnumSide    `protobuf:"varint,1,opt,name=side,proto3,enum=orderbook.EnumSide" json:"side,omitempty"`
+	Level                *PriceLevel `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *LevelUpdate) Reset()         { *m = LevelUpdate{} }
+func (m *LevelUpdate) String() string { return proto.CompactTextString(m) }
+func (*LevelUpdate) ProtoMessage()    {}
+func (*LevelUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{29}
+}
+
+func (m *LevelUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LevelUpdate.Unmarshal(m, b)
+}
+func (m *LevelUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LevelUpdate.Marshal(b, m, deterministic)
+}
+func (m *LevelUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LevelUpdate.Merge(m, src)
+}
+func (m *LevelUpdate) XXX_Size() int {
+	return xxx_messageInfo_LevelUpdate.Size(m)
+}
+func (m *LevelUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_LevelUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LevelUpdate proto.InternalMessageInfo
+
+func (m *LevelUpdate) GetSide() EnumSide {
+	if m != nil {
+		return m.Side
+	}
+	return EnumSide_BUY
+}
+
+func (m *LevelUpdate) GetLevel() *PriceLevel {
+	if m != nil {
+		return m.Level
+	}
+	return nil
+}
+
+// The first event is a snapshot, every later one has sequence one higher than the one before.
+// A gap means events were missed: subscribe again. The stream ends with RESOURCE_EXHAUSTED
+// when the client falls too far behind.
+type MarketDataEvent struct {
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Symbol   string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//	*MarketDataEvent_Snapshot
+	//	*MarketDataEvent_Level
+	//	*MarketDataEvent_Trade
+	Event                isMarketDataEvent_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *MarketDataEvent) Reset()         { *m = MarketDataEvent{} }
+func (m *MarketDataEvent) String() string { return proto.CompactTextString(m) }
+func (*MarketDataEvent) ProtoMessage()    {}
+func (*MarketDataEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{30}
+}
+
+func (m *MarketDataEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MarketDataEvent.Unmarshal(m, b)
+}
+func (m *MarketDataEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MarketDataEvent.Marshal(b, m, deterministic)
+}
+func (m *MarketDataEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MarketDataEvent.Merge(m, src)
+}
+func (m *MarketDataEvent) XXX_Size() int {
+	return xxx_messageInfo_MarketDataEvent.Size(m)
+}
+func (m *MarketDataEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_MarketDataEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MarketDataEvent proto.InternalMessageInfo
+
+func (m *MarketDataEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *MarketDataEvent) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+type isMarketDataEvent_Event interface {
+	isMarketDataEvent_Event()
+}
+
+type MarketDataEvent_Snapshot struct {
+	Snapshot *DepthSnapshot `protobuf:"bytes,3,opt,name=snapshot,proto3,oneof"`
+}
+
+type MarketDataEvent_Level struct {
+	Level *LevelUpdate `protobuf:"bytes,4,opt,name=level,proto3,oneof"`
+}
+
+type MarketDataEvent_Trade struct {
+	Trade *Trade `protobuf:"bytes,5,opt,name=trade,proto3,oneof"`
+}
+
+func (*MarketDataEvent_Snapshot) isMarketDataEvent_Event() {}
+
+func (*MarketDataEvent_Level) isMarketDataEvent_Event() {}
+
+func (*MarketDataEvent_Trade) isMarketDataEvent_Event() {}
+
+func (m *MarketDataEvent) GetEvent() isMarketDataEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *MarketDataEvent) GetSnapshot() *DepthSnapshot {
+	if x, ok := m.GetEvent().(*MarketDataEvent_Snapshot); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (m *MarketDataEvent) GetLevel() *LevelUpdate {
+	if x, ok := m.GetEvent().(*MarketDataEvent_Level); ok {
+		return x.Level
+	}
+	return nil
+}
+
+func (m *MarketDataEvent) GetTrade() *Trade {
+	if x, ok := m.GetEvent().(*MarketDataEvent_Trade); ok {
+		return x.Trade
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*MarketDataEvent) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*MarketDataEvent_Snapshot)(nil),
+		(*MarketDataEvent_Level)(nil),
+		(*MarketDataEvent_Trade)(nil),
+	}
+}
+
+type BalancesRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Asset                string   `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalancesRequest) Reset()         { *m = BalancesRequest{} }
+func (m *BalancesRequest) String() string { return proto.CompactTextString(m) }
+func (*BalancesRequest) ProtoMessage()    {}
+func (*BalancesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{31}
+}
+
+func (m *BalancesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancesRequest.Unmarshal(m, b)
+}
+func (m *BalancesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancesRequest.Marshal(b, m, deterministic)
+}
+func (m *BalancesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancesRequest.Merge(m, src)
+}
+func (m *BalancesRequest) XXX_Size() int {
+	return xxx_messageInfo_BalancesRequest.Size(m)
+}
+func (m *BalancesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalancesRequest proto.InternalMessageInfo
+
+func (m *BalancesRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *BalancesRequest) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+// available is free to trade, locked is held by open orders.
+type Balance struct {
+	Asset                string   `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Available            string   `protobuf:"bytes,2,opt,name=available,proto3" json:"available,omitempty"`
+	Locked               string   `protobuf:"bytes,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	Total                string   `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{32}
+}
+
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (m *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(m, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+func (m *Balance) GetAvailable() string {
+	if m != nil {
+		return m.Available
+	}
+	return ""
+}
+
+func (m *Balance) GetLocked() string {
+	if m != nil {
+		return m.Locked
+	}
+	return ""
+}
+
+func (m *Balance) GetTotal() string {
+	if m != nil {
+		return m.Total
+	}
+	return ""
+}
+
+type BalancesReply struct {
+	Balances             []*Balance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *BalancesReply) Reset()         { *m = BalancesReply{} }
+func (m *BalancesReply) String() string { return proto.CompactTextString(m) }
+func (*BalancesReply) ProtoMessage()    {}
+func (*BalancesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{33}
+}
+
+func (m *BalancesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancesReply.Unmarshal(m, b)
+}
+func (m *BalancesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancesReply.Marshal(b, m, deterministic)
+}
+func (m *BalancesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancesReply.Merge(m, src)
+}
+func (m *BalancesReply) XXX_Size() int {
+	return xxx_messageInfo_BalancesReply.Size(m)
+}
+func (m *BalancesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalancesReply proto.InternalMessageInfo
+
+func (m *BalancesReply) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type DepositRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Asset                string   `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount               string   `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DepositRequest) Reset()         { *m = DepositRequest{} }
+func (m *DepositRequest) String() string { return proto.CompactTextString(m) }
+func (*DepositRequest) ProtoMessage()    {}
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{34}
+}
+
+func (m *DepositRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DepositRequest.Unmarshal(m, b)
+}
+func (m *DepositRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DepositRequest.Marshal(b, m, deterministic)
+}
+func (m *DepositRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DepositRequest.Merge(m, src)
+}
+func (m *DepositRequest) XXX_Size() int {
+	return xxx_messageInfo_DepositRequest.Size(m)
+}
+func (m *DepositRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DepositRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DepositRequest proto.InternalMessageInfo
+
+func (m *DepositRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *DepositRequest) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+func (m *DepositRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type WithdrawalRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Asset                string   `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount               string   `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Address              string   `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawalRequest) Reset()         { *m = WithdrawalRequest{} }
+func (m *WithdrawalRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawalRequest) ProtoMessage()    {}
+func (*WithdrawalRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{35}
+}
+
+func (m *WithdrawalRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawalRequest.Unmarshal(m, b)
+}
+func (m *WithdrawalRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawalRequest.Marshal(b, m, deterministic)
+}
+func (m *WithdrawalRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawalRequest.Merge(m, src)
+}
+func (m *WithdrawalRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawalRequest.Size(m)
+}
+func (m *WithdrawalRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawalRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawalRequest proto.InternalMessageInfo
+
+func (m *WithdrawalRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *WithdrawalRequest) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+func (m *WithdrawalRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *WithdrawalRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type TransfersRequest struct {
+	OwnerUsername        string         `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Status               TransferStatus `protobuf:"varint,2,opt,name=status,proto3,enum=orderbook.TransferStatus" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TransfersRequest) Reset()         { *m = TransfersRequest{} }
+func (m *TransfersRequest) String() string { return proto.CompactTextString(m) }
+func (*TransfersRequest) ProtoMessage()    {}
+func (*TransfersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{36}
+}
+
+func (m *TransfersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransfersRequest.Unmarshal(m, b)
+}
+func (m *TransfersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransfersRequest.Marshal(b, m, deterministic)
+}
+func (m *TransfersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransfersRequest.Merge(m, src)
+}
+func (m *TransfersRequest) XXX_Size() int {
+	return xxx_messageInfo_TransfersRequest.Size(m)
+}
+func (m *TransfersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransfersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransfersRequest proto.InternalMessageInfo
+
+func (m *TransfersRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *TransfersRequest) GetStatus() TransferStatus {
+	if m != nil {
+		return m.Status
+	}
+	return TransferStatus_TRANSFER_ANY
+}
+
+type PendingApprovalsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingApprovalsRequest) Reset()         { *m = PendingApprovalsRequest{} }
+func (m *PendingApprovalsRequest) String() string { return proto.CompactTextString(m) }
+func (*PendingApprovalsRequest) ProtoMessage()    {}
+func (*PendingApprovalsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{37}
+}
+
+func (m *PendingApprovalsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingApprovalsRequest.Unmarshal(m, b)
+}
+func (m *PendingApprovalsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingApprovalsRequest.Marshal(b, m, deterministic)
+}
+func (m *PendingApprovalsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingApprovalsRequest.Merge(m, src)
+}
+func (m *PendingApprovalsRequest) XXX_Size() int {
+	return xxx_messageInfo_PendingApprovalsRequest.Size(m)
+}
+func (m *PendingApprovalsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingApprovalsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingApprovalsRequest proto.InternalMessageInfo
+
+type ReviewWithdrawalRequest struct {
+	TransferId           uint64   `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReviewWithdrawalRequest) Reset()         { *m = ReviewWithdrawalRequest{} }
+func (m *ReviewWithdrawalRequest) String() string { return proto.CompactTextString(m) }
+func (*ReviewWithdrawalRequest) ProtoMessage()    {}
+func (*ReviewWithdrawalRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{38}
+}
+
+func (m *ReviewWithdrawalRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReviewWithdrawalRequest.Unmarshal(m, b)
+}
+func (m *ReviewWithdrawalRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReviewWithdrawalRequest.Marshal(b, m, deterministic)
+}
+func (m *ReviewWithdrawalRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReviewWithdrawalRequest.Merge(m, src)
+}
+func (m *ReviewWithdrawalRequest) XXX_Size() int {
+	return xxx_messageInfo_ReviewWithdrawalRequest.Size(m)
+}
+func (m *ReviewWithdrawalRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReviewWithdrawalRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReviewWithdrawalRequest proto.InternalMessageInfo
+
+func (m *ReviewWithdrawalRequest) GetTransferId() uint64 {
+	if m != nil {
+		return m.TransferId
+	}
+	return 0
+}
+
+func (m *ReviewWithdrawalRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// For a deposit, address is where to send the funds.
+type Transfer struct {
+	Id                   uint64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind                 TransferKind   `protobuf:"varint,2,opt,name=kind,proto3,enum=orderbook.TransferKind" json:"kind,omitempty"`
+	OwnerUsername        string         `protobuf:"bytes,3,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Asset                string         `protobuf:"bytes,4,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount               string         `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Address              string         `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	Status               TransferStatus `protobuf:"varint,7,opt,name=status,proto3,enum=orderbook.TransferStatus" json:"status,omitempty"`
+	NeedsApproval        bool           `protobuf:"varint,8,opt,name=needs_approval,json=needsApproval,proto3" json:"needs_approval,omitempty"`
+	ApprovedBy           string         `protobuf:"bytes,9,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	TxId                 string         `protobuf:"bytes,10,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Reason               string         `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt            string         `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            string         `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Transfer) Reset()         { *m = Transfer{} }
+func (m *Transfer) String() string { return proto.CompactTextString(m) }
+func (*Transfer) ProtoMessage()    {}
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{39}
+}
+
+func (m *Transfer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transfer.Unmarshal(m, b)
+}
+func (m *Transfer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transfer.Marshal(b, m, deterministic)
+}
+func (m *Transfer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transfer.Merge(m, src)
+}
+func (m *Transfer) XXX_Size() int {
+	return xxx_messageInfo_Transfer.Size(m)
+}
+func (m *Transfer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transfer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transfer proto.InternalMessageInfo
+
+func (m *Transfer) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Transfer) GetKind() TransferKind {
+	if m != nil {
+		return m.Kind
+	}
+	return TransferKind_TRANSFER_KIND_UNSPECIFIED
+}
+
+func (m *Transfer) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *Transfer) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+func (m *Transfer) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *Transfer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Transfer) GetStatus() TransferStatus {
+	if m != nil {
+		return m.Status
+	}
+	return TransferStatus_TRANSFER_ANY
+}
+
+func (m *Transfer) GetNeedsApproval() bool {
+	if m != nil {
+		return m.NeedsApproval
+	}
+	return false
+}
+
+func (m *Transfer) GetApprovedBy() string {
+	if m != nil {
+		return m.ApprovedBy
+	}
+	return ""
+}
+
+func (m *Transfer) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *Transfer) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Transfer) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+func (m *Transfer) GetUpdatedAt() string {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return ""
+}
+
+type TransferReply struct {
+	Transfer             *Transfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *TransferReply) Reset()         { *m = TransferReply{} }
+func (m *TransferReply) String() string { return proto.CompactTextString(m) }
+func (*TransferReply) ProtoMessage()    {}
+func (*TransferReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{40}
+}
+
+func (m *TransferReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferReply.Unmarshal(m, b)
+}
+func (m *TransferReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferReply.Marshal(b, m, deterministic)
+}
+func (m *TransferReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferReply.Merge(m, src)
+}
+func (m *TransferReply) XXX_Size() int {
+	return xxx_messageInfo_TransferReply.Size(m)
+}
+func (m *TransferReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferReply proto.InternalMessageInfo
+
+func (m *TransferReply) GetTransfer() *Transfer {
+	if m != nil {
+		return m.Transfer
+	}
+	return nil
+}
+
+type TransfersReply struct {
+	Transfers            []*Transfer `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TransfersReply) Reset()         { *m = TransfersReply{} }
+func (m *TransfersReply) String() string { return proto.CompactTextString(m) }
+func (*TransfersReply) ProtoMessage()    {}
+func (*TransfersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{41}
+}
+
+func (m *TransfersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransfersReply.Unmarshal(m, b)
+}
+func (m *TransfersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransfersReply.Marshal(b, m, deterministic)
+}
+func (m *TransfersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransfersReply.Merge(m, src)
+}
+func (m *TransfersReply) XXX_Size() int {
+	return xxx_messageInfo_TransfersReply.Size(m)
+}
+func (m *TransfersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransfersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransfersReply proto.InternalMessageInfo
+
+func (m *TransfersReply) GetTransfers() []*Transfer {
+	if m != nil {
+		return m.Transfers
+	}
+	return nil
+}
+
+// after_sequence is the last sequence the client saw, 0 for new reports only.
+// FAILED_PRECONDITION means the reports after it are gone: reload open orders and subscribe from 0.
+type ExecutionsRequest struct {
+	OwnerUsername        string   `protobuf:"bytes,1,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	AfterSequence        uint64   `protobuf:"varint,2,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecutionsRequest) Reset()         { *m = ExecutionsRequest{} }
+func (m *ExecutionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecutionsRequest) ProtoMessage()    {}
+func (*ExecutionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{42}
+}
+
+func (m *ExecutionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutionsRequest.Unmarshal(m, b)
+}
+func (m *ExecutionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecutionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutionsRequest.Merge(m, src)
+}
+func (m *ExecutionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecutionsRequest.Size(m)
+}
+func (m *ExecutionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecutionsRequest proto.InternalMessageInfo
+
+func (m *ExecutionsRequest) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *ExecutionsRequest) GetAfterSequence() uint64 {
+	if m != nil {
+		return m.AfterSequence
+	}
+	return 0
+}
+
+// Sequence is per user and has no gaps. last_price, last_quantity and maker are set for EXEC_FILL.
+type ExecutionReport struct {
+	Sequence             uint64       `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	OrderId              uint64       `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Symbol               string       `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	ExecType             ExecType     `protobuf:"varint,4,opt,name=exec_type,json=execType,proto3,enum=orderbook.ExecType" json:"exec_type,omitempty"`
+	Status               OrderStatus  `protobuf:"varint,5,opt,name=status,proto3,enum=orderbook.OrderStatus" json:"status,omitempty"`
+	Side                 EnumSide     `protobuf:"varint,6,opt,name=side,proto3,enum=orderbook.EnumSide" json:"side,omitempty"`
+	Price                string       `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string       `protobuf:"bytes,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	FilledQuantity       string       `protobuf:"bytes,9,opt,name=filled_quantity,json=filledQuantity,proto3" json:"filled_quantity,omitempty"`
+	LastPrice            string       `protobuf:"bytes,10,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	LastQuantity         string       `protobuf:"bytes,11,opt,name=last_quantity,json=lastQuantity,proto3" json:"last_quantity,omitempty"`
+	Maker                bool         `protobuf:"varint,12,opt,name=maker,proto3" json:"maker,omitempty"`
+	RejectReason         RejectReason `protobuf:"varint,13,opt,name=reject_reason,json=rejectReason,proto3,enum=orderbook.RejectReason" json:"reject_reason,omitempty"`
+	Timestamp            uint32       `protobuf:"varint,14,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	OwnerUsername        string       `protobuf:"bytes,15,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	Fee                  string       `protobuf:"bytes,16,opt,name=fee,proto3" json:"fee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ExecutionReport) Reset()         { *m = ExecutionReport{} }
+func (m *ExecutionReport) String() string { return proto.CompactTextString(m) }
+func (*ExecutionReport) ProtoMessage()    {}
+func (*ExecutionReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6b2982dec9a4117, []int{43}
+}
+
+func (m *ExecutionReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutionReport.Unmarshal(m, b)
+}
+func (m *ExecutionReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutionReport.Marshal(b, m, deterministic)
+}
+func (m *ExecutionReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutionReport.Merge(m, src)
+}
+func (m *ExecutionReport) XXX_Size() int {
+	return xxx_messageInfo_ExecutionReport.Size(m)
+}
+func (m *ExecutionReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutionReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecutionReport proto.InternalMessageInfo
+
+func (m *ExecutionReport) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ExecutionReport) GetOrderId() uint64 {
+	if m != nil {
+		return m.OrderId
+	}
+	return 0
+}
+
+func (m *ExecutionReport) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetExecType() ExecType {
+	if m != nil {
+		return m.ExecType
+	}
+	return ExecType_EXEC_ACCEPTED
+}
+
+func (m *ExecutionReport) GetStatus() OrderStatus {
+	if m != nil {
+		return m.Status
+	}
+	return OrderStatus_NEW
+}
+
+func (m *ExecutionReport) GetSide() EnumSide {
+	if m != nil {
+		return m.Side
+	}
+	return EnumSide_BUY
+}
+
+func (m *ExecutionReport) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetFilledQuantity() string {
+	if m != nil {
+		return m.FilledQuantity
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetLastPrice() string {
+	if m != nil {
+		return m.LastPrice
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetLastQuantity() string {
+	if m != nil {
+		return m.LastQuantity
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetMaker() bool {
+	if m != nil {
+		return m.Maker
+	}
+	return false
+}
+
+func (m *ExecutionReport) GetRejectReason() RejectReason {
+	if m != nil {
+		return m.RejectReason
+	}
+	return RejectReason_REJECT_NONE
+}
+
+func (m *ExecutionReport) GetTimestamp() uint32 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *ExecutionReport) GetOwnerUsername() string {
+	if m != nil {
+		return m.OwnerUsername
+	}
+	return ""
+}
+
+func (m *ExecutionReport) GetFee() string {
+	if m != nil {
+		return m.Fee
 	}
 	return ""
 }
 
 func init() {
 	proto.RegisterEnum("orderbook.EnumSide", EnumSide_name, EnumSide_value)
+	proto.RegisterEnum("orderbook.TimeInForce", TimeInForce_name, TimeInForce_value)
+	proto.RegisterEnum("orderbook.RejectReason", RejectReason_name, RejectReason_value)
+	proto.RegisterEnum("orderbook.SelfTradePrevention", SelfTradePrevention_name, SelfTradePrevention_value)
+	proto.RegisterEnum("orderbook.OrderType", OrderType_name, OrderType_value)
+	proto.RegisterEnum("orderbook.OrderStatus", OrderStatus_name, OrderStatus_value)
+	proto.RegisterEnum("orderbook.TransferKind", TransferKind_name, TransferKind_value)
+	proto.RegisterEnum("orderbook.TransferStatus", TransferStatus_name, TransferStatus_value)
+	proto.RegisterEnum("orderbook.ExecType", ExecType_name, ExecType_value)
 	proto.RegisterType((*OrderInfoRequest)(nil), "orderbook.OrderInfoRequest")
 	proto.RegisterType((*OrderInfoReply)(nil), "orderbook.OrderInfoReply")
 	proto.RegisterType((*Order)(nil), "orderbook.Order")
+	proto.RegisterType((*Trade)(nil), "orderbook.Trade")
+	proto.RegisterType((*OwnTrade)(nil), "orderbook.OwnTrade")
+	proto.RegisterType((*PlaceOrderRequest)(nil), "orderbook.PlaceOrderRequest")
+	proto.RegisterType((*PlaceOrderReply)(nil), "orderbook.PlaceOrderReply")
+	proto.RegisterType((*CancelOrderRequest)(nil), "orderbook.CancelOrderRequest")
+	proto.RegisterType((*CancelOrderReply)(nil), "orderbook.CancelOrderReply")
+	proto.RegisterType((*AmendOrderRequest)(nil), "orderbook.AmendOrderRequest")
+	proto.RegisterType((*AmendOrderReply)(nil), "orderbook.AmendOrderReply")
+	proto.RegisterType((*ListOpenOrdersRequest)(nil), "orderbook.ListOpenOrdersRequest")
+	proto.RegisterType((*ListOpenOrdersReply)(nil), "orderbook.ListOpenOrdersReply")
+	proto.RegisterType((*OrderHistoryRequest)(nil), "orderbook.OrderHistoryRequest")
+	proto.RegisterType((*OrderHistoryReply)(nil), "orderbook.OrderHistoryReply")
+	proto.RegisterType((*TradeHistoryRequest)(nil), "orderbook.TradeHistoryRequest")
+	proto.RegisterType((*TradeHistoryReply)(nil), "orderbook.TradeHistoryReply")
+	proto.RegisterType((*FeeRatesRequest)(nil), "orderbook.FeeRatesRequest")
+	proto.RegisterType((*FeeRatesReply)(nil), "orderbook.FeeRatesReply")
+	proto.RegisterType((*GetSelfTradePreventionRequest)(nil), "orderbook.GetSelfTradePreventionRequest")
+	proto.RegisterType((*SetSelfTradePreventionRequest)(nil), "orderbook.SetSelfTradePreventionRequest")
+	proto.RegisterType((*SelfTradePreventionReply)(nil), "orderbook.SelfTradePreventionReply")
 	proto.RegisterType((*GreetingServiceRequest)(nil), "orderbook.GreetingServiceRequest")
 	proto.RegisterType((*GreetingServiceReply)(nil), "orderbook.GreetingServiceReply")
+	proto.RegisterType((*RecentTradesRequest)(nil), "orderbook.RecentTradesRequest")
+	proto.RegisterType((*RecentTradesReply)(nil), "orderbook.RecentTradesReply")
+	proto.RegisterType((*MarketDataRequest)(nil), "orderbook.MarketDataRequest")
+	proto.RegisterType((*PriceLevel)(nil), "orderbook.PriceLevel")
+	proto.RegisterType((*DepthSnapshot)(nil), "orderbook.DepthSnapshot")
+	proto.RegisterType((*LevelUpdate)(nil), "orderbook.LevelUpdate")
+	proto.RegisterType((*MarketDataEvent)(nil), "orderbook.MarketDataEvent")
+	proto.RegisterType((*BalancesRequest)(nil), "orderbook.BalancesRequest")
+	proto.RegisterType((*Balance)(nil), "orderbook.Balance")
+	proto.RegisterType((*BalancesReply)(nil), "orderbook.BalancesReply")
+	proto.RegisterType((*DepositRequest)(nil), "orderbook.DepositRequest")
+	proto.RegisterType((*WithdrawalRequest)(nil), "orderbook.WithdrawalRequest")
+	proto.RegisterType((*TransfersRequest)(nil), "orderbook.TransfersRequest")
+	proto.RegisterType((*PendingApprovalsRequest)(nil), "orderbook.PendingApprovalsRequest")
+	proto.RegisterType((*ReviewWithdrawalRequest)(nil), "orderbook.ReviewWithdrawalRequest")
+	proto.RegisterType((*Transfer)(nil), "orderbook.Transfer")
+	proto.RegisterType((*TransferReply)(nil), "orderbook.TransferReply")
+	proto.RegisterType((*TransfersReply)(nil), "orderbook.TransfersReply")
+	proto.RegisterType((*ExecutionsRequest)(nil), "orderbook.ExecutionsRequest")
+	proto.RegisterType((*ExecutionReport)(nil), "orderbook.ExecutionReport")
 }
 
 func init() {
@@ -310,30 +3130,186 @@ func init() {
 }
 
 var fileDescriptor_e6b2982dec9a4117 = []byte{
-	// 395 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x4f, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0xbb, 0x89, 0xdd, 0xd8, 0x03, 0x0d, 0xd6, 0x50, 0xd0, 0x12, 0xa8, 0x30, 0x16, 0x02,
-	0x0b, 0xa1, 0x16, 0x99, 0x0b, 0xd7, 0x56, 0xaa, 0x0a, 0x52, 0xa5, 0x96, 0x0d, 0x3d, 0x80, 0x90,
-	0x90, 0x63, 0x0f, 0xd1, 0x8a, 0xf8, 0x0f, 0xeb, 0x35, 0x28, 0xdf, 0x9b, 0x0f, 0x80, 0xbc, 0x8e,
-	0x13, 0x13, 0xa2, 0xde, 0xfc, 0x7e, 0xef, 0xed, 0x8c, 0x77, 0x76, 0xe0, 0x41, 0xa9, 0x0a, 0x5d,
-	0x9c, 0x14, 0x2a, 0x25, 0x35, 0x2b, 0x8a, 0x1f, 0xc7, 0x46, 0xa3, 0xbb, 0x06, 0x41, 0x00, 0xde,
-	0x55, 0x23, 0x3e, 0xe4, 0xdf, 0x0b, 0x41, 0x3f, 0x6b, 0xaa, 0x34, 0x8e, 0x61, 0x20, 0x53, 0xce,
-	0x7c, 0x16, 0x5a, 0x62, 0x20, 0xd3, 0xe0, 0x1d, 0x8c, 0x7b, 0x99, 0x72, 0xb1, 0xc4, 0x17, 0x60,
-	0x9b, 0x12, 0x26, 0x74, 0x27, 0xf2, 0x8e, 0x37, 0x1d, 0x4c, 0x52, 0xb4, 0x76, 0xf0, 0x87, 0x81,
-	0x6d, 0xc0, 0x76, 0x4d, 0x3c, 0x04, 0xfb, 0x5a, 0xc9, 0x84, 0xf8, 0xc0, 0x67, 0x21, 0x13, 0xad,
-	0xc0, 0x09, 0x38, 0x1f, 0xeb, 0x38, 0xd7, 0x52, 0x2f, 0xf9, 0xd0, 0x18, 0x6b, 0x8d, 0x2f, 0xc1,
-	0x9a, 0xca, 0x94, 0xb8, 0xe5, 0xb3, 0x70, 0x1c, 0xdd, 0xef, 0xb5, 0x3c, 0xcf, 0xeb, 0xac, 0xb1,
-	0x84, 0x09, 0xe0, 0x13, 0x70, 0x3f, 0xc9, 0x8c, 0x2a, 0x1d, 0x67, 0x25, 0xb7, 0x7d, 0x16, 0x1e,
-	0x88, 0x0d, 0xc0, 0xe7, 0x70, 0x70, 0xf5, 0x3b, 0x27, 0x75, 0x53, 0x91, 0xca, 0xe3, 0x8c, 0xf8,
-	0xbe, 0xcf, 0x42, 0x57, 0xfc, 0x0b, 0xf1, 0x08, 0x20, 0x51, 0x14, 0x6b, 0x4a, 0xbf, 0xc5, 0x9a,
-	0x8f, 0x4c, 0xc4, 0x5d, 0x91, 0x53, 0xdd, 0xd8, 0x75, 0x99, 0x76, 0xb6, 0xd3, 0xda, 0x2b, 0x72,
-	0xaa, 0x83, 0xd7, 0xf0, 0xf0, 0x42, 0x11, 0x69, 0x99, 0xcf, 0xa7, 0xa4, 0x7e, 0xc9, 0x84, 0xba,
-	0xd1, 0x22, 0x58, 0xa6, 0x29, 0x33, 0x47, 0xcc, 0x77, 0xf0, 0x06, 0x0e, 0xff, 0x4b, 0x37, 0x43,
-	0xe6, 0x30, 0xca, 0xa8, 0xaa, 0xe2, 0x79, 0x3b, 0x24, 0x57, 0x74, 0xf2, 0xd5, 0x11, 0x38, 0xdd,
-	0x9d, 0x71, 0x04, 0xc3, 0xb3, 0x9b, 0xcf, 0xde, 0x1e, 0x3a, 0x60, 0x4d, 0xcf, 0x2f, 0x2f, 0x3d,
-	0x16, 0x7d, 0xed, 0xbd, 0xe9, 0xaa, 0x22, 0xbe, 0x87, 0xbb, 0x17, 0xa4, 0xd7, 0x18, 0x1f, 0x6f,
-	0x3f, 0x59, 0x6f, 0x01, 0x26, 0x8f, 0x76, 0x9b, 0xe5, 0x62, 0x19, 0xec, 0x45, 0x09, 0xdc, 0xdb,
-	0xfa, 0x5d, 0xbc, 0x06, 0xa7, 0x43, 0xf8, 0xac, 0x77, 0x76, 0xf7, 0x10, 0x26, 0x4f, 0x6f, 0x8b,
-	0x98, 0x26, 0x67, 0xf6, 0x97, 0xe1, 0x49, 0x39, 0x9b, 0xed, 0x9b, 0x7d, 0x7d, 0xfb, 0x37, 0x00,
-	0x00, 0xff, 0xff, 0x1d, 0x01, 0x32, 0xc9, 0xc8, 0x02, 0x00, 0x00,
+	// 2894 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x5a, 0xcf, 0x6e, 0xe3, 0xc8,
+	0xd1, 0x37, 0x25, 0xca, 0x96, 0x4a, 0x96, 0x44, 0xb5, 0xed, 0x19, 0x5a, 0x63, 0xef, 0xfa, 0xe3,
+	0xfe, 0xf3, 0x7a, 0x3f, 0xcc, 0xce, 0x7a, 0x81, 0x0f, 0x1f, 0x06, 0x09, 0x36, 0xb2, 0x44, 0xdb,
+	0x9a, 0x91, 0x25, 0x2d, 0xa5, 0x59, 0xef, 0x04, 0x01, 0x08, 0x5a, 0x6c, 0x7b, 0x18, 0x53, 0xa4,
+	0x96, 0x6c, 0x7b, 0xec, 0xbd, 0x24, 0x08, 0x72, 0x4a, 0x2e, 0x39, 0xe4, 0x18, 0xe4, 0x2d, 0x72,
+	0xca, 0x33, 0xe4, 0x1d, 0x72, 0xce, 0x21, 0xa7, 0x3c, 0x40, 0xd0, 0xdd, 0x24, 0x45, 0x4a, 0x94,
+	0x6c, 0x6f, 0x66, 0x81, 0xdc, 0xd4, 0x55, 0xc5, 0xfa, 0xd7, 0xdd, 0xd5, 0x55, 0x3f, 0x1b, 0x36,
+	0xc6, 0x9e, 0x4b, 0xdc, 0xcf, 0x5d, 0xcf, 0xc4, 0xde, 0x99, 0xeb, 0x5e, 0x3e, 0x65, 0x6b, 0x54,
+	0x88, 0x08, 0x8a, 0x02, 0x52, 0x97, 0x2e, 0x5a, 0xce, 0xb9, 0xab, 0xe1, 0xef, 0xae, 0xb0, 0x4f,
+	0x50, 0x19, 0x32, 0x96, 0x29, 0x0b, 0x3b, 0xc2, 0xae, 0xa8, 0x65, 0x2c, 0x53, 0xf9, 0x7f, 0x28,
+	0xc7, 0x64, 0xc6, 0xf6, 0x2d, 0xfa, 0x18, 0x72, 0x4c, 0x05, 0x13, 0x2a, 0xee, 0x4b, 0x4f, 0x27,
+	0x16, 0x98, 0xa4, 0xc6, 0xd9, 0xca, 0x3f, 0x56, 0x20, 0xc7, 0x08, 0xd3, 0x3a, 0xd1, 0x27, 0x20,
+	0xf6, 0x2d, 0x13, 0xcb, 0xe2, 0x8e, 0xb0, 0x5b, 0xde, 0x5f, 0x8b, 0x29, 0x50, 0x9d, 0xab, 0x11,
+	0x65, 0x69, 0x4c, 0x00, 0x6d, 0x41, 0x61, 0x60, 0x8d, 0xb0, 0x4f, 0x8c, 0xd1, 0x58, 0xce, 0xed,
+	0x08, 0xbb, 0x25, 0x6d, 0x42, 0x40, 0x1f, 0x42, 0xa9, 0xfb, 0xd6, 0xc1, 0xde, 0x2b, 0x1f, 0x7b,
+	0x8e, 0x31, 0xc2, 0xf2, 0xf2, 0x8e, 0xb0, 0x5b, 0xd0, 0x92, 0x44, 0xb4, 0x0d, 0x30, 0xf4, 0xb0,
+	0x41, 0xb0, 0xa9, 0x1b, 0x44, 0x5e, 0x61, 0x22, 0x85, 0x80, 0x52, 0x27, 0x94, 0x7d, 0x35, 0x36,
+	0x43, 0x76, 0x9e, 0xb3, 0x03, 0x4a, 0x9d, 0xa0, 0xe7, 0x50, 0x22, 0xd6, 0x08, 0xeb, 0x96, 0xa3,
+	0x9f, 0xbb, 0xde, 0x10, 0xcb, 0x05, 0xe6, 0xf3, 0xa3, 0x98, 0xcf, 0xd4, 0xa1, 0x96, 0x73, 0x48,
+	0xb9, 0x5a, 0x91, 0x4c, 0x16, 0x54, 0x35, 0xbe, 0x19, 0x5b, 0x1e, 0xf6, 0xa9, 0x6a, 0xe0, 0xee,
+	0x07, 0x94, 0x3a, 0x41, 0x4f, 0xa0, 0x30, 0x76, 0x7d, 0xa2, 0xbb, 0x8e, 0x7d, 0x2b, 0x17, 0x77,
+	0x84, 0xdd, 0xbc, 0x96, 0xa7, 0x84, 0xae, 0x63, 0xdf, 0xa2, 0x3d, 0xa8, 0x46, 0x4c, 0xdd, 0xc3,
+	0x63, 0xcf, 0x1a, 0x62, 0x79, 0x95, 0x09, 0x55, 0x42, 0x21, 0x8d, 0x93, 0xd1, 0x4f, 0xa0, 0xe4,
+	0xe1, 0x5f, 0xe2, 0x21, 0xd1, 0x3d, 0x6c, 0xf8, 0xae, 0x23, 0x97, 0x98, 0x8f, 0x8f, 0x63, 0x3e,
+	0x6a, 0x8c, 0xaf, 0x31, 0xb6, 0xb6, 0xea, 0xc5, 0x56, 0xe8, 0x11, 0x2c, 0xfb, 0xb7, 0xa3, 0x33,
+	0xd7, 0x96, 0xcb, 0x2c, 0xf8, 0x60, 0x85, 0x76, 0x41, 0x24, 0xb7, 0x63, 0x2c, 0x57, 0x98, 0xb2,
+	0xf5, 0xe9, 0x5d, 0x1e, 0xdc, 0x8e, 0xb1, 0xc6, 0x24, 0xd0, 0x53, 0x58, 0xf6, 0x89, 0x41, 0xae,
+	0x7c, 0x59, 0x9a, 0x49, 0x0e, 0x93, 0xed, 0x33, 0xae, 0x16, 0x48, 0xa1, 0x4f, 0xa0, 0x72, 0x6e,
+	0xd9, 0x36, 0x36, 0xf5, 0xef, 0xae, 0x0c, 0x87, 0x58, 0xe4, 0x56, 0xae, 0x32, 0xd3, 0x65, 0x4e,
+	0xfe, 0x3a, 0xa0, 0xd2, 0x04, 0xfa, 0xc4, 0x1d, 0xeb, 0x3c, 0x7a, 0xc4, 0xf7, 0x86, 0x52, 0x7a,
+	0x2c, 0xee, 0x4f, 0x41, 0xa2, 0x47, 0x1a, 0x0f, 0x89, 0xe5, 0x3a, 0x81, 0xd0, 0x1a, 0x13, 0xaa,
+	0x4c, 0xe8, 0x5c, 0x74, 0x17, 0xa4, 0x91, 0x71, 0xa3, 0xfb, 0xb6, 0x35, 0x1e, 0x1b, 0x17, 0x58,
+	0x3f, 0x1b, 0xfb, 0xf2, 0x3a, 0xdb, 0x90, 0xf2, 0xc8, 0xb8, 0xe9, 0x07, 0xe4, 0x83, 0xb1, 0x4f,
+	0x95, 0x9a, 0x96, 0x3f, 0xb6, 0x8d, 0xdb, 0x89, 0x77, 0x1b, 0x5c, 0x69, 0x40, 0x8f, 0xdc, 0xdb,
+	0x82, 0x02, 0xf1, 0xac, 0x8b, 0x0b, 0xec, 0x61, 0x53, 0x7e, 0xc4, 0xf6, 0x66, 0x42, 0x40, 0x08,
+	0xc4, 0x37, 0xd8, 0x36, 0xe5, 0xc7, 0xec, 0x63, 0xf6, 0x1b, 0x29, 0x50, 0x1a, 0x19, 0x97, 0xd8,
+	0xd3, 0xcf, 0x31, 0xf7, 0x41, 0x66, 0xcc, 0x22, 0x23, 0x1e, 0x62, 0xe6, 0x80, 0x02, 0x25, 0x92,
+	0x90, 0xd9, 0xe4, 0x32, 0x24, 0x26, 0xa3, 0xc1, 0x86, 0x8f, 0xed, 0x73, 0x9d, 0x78, 0x86, 0x89,
+	0xf5, 0xb1, 0x87, 0xaf, 0xb1, 0x43, 0x83, 0x95, 0x6b, 0x6c, 0x03, 0xde, 0x8b, 0x6d, 0x40, 0x1f,
+	0xdb, 0xe7, 0x03, 0x2a, 0xd6, 0x8b, 0xa4, 0xb4, 0x35, 0x7f, 0x96, 0x88, 0xd6, 0x21, 0xc7, 0x53,
+	0xf8, 0x84, 0xd9, 0xe3, 0x0b, 0x54, 0x83, 0x7c, 0x94, 0x86, 0x2d, 0xc6, 0x88, 0xd6, 0x2f, 0xc4,
+	0x7c, 0x46, 0xca, 0xbe, 0x10, 0xf3, 0x59, 0x49, 0x54, 0xfe, 0x96, 0x81, 0x1c, 0xd3, 0x18, 0x3b,
+	0x4f, 0x42, 0xe2, 0x3c, 0x7d, 0x08, 0x65, 0x1e, 0x3b, 0xf3, 0x4d, 0xb7, 0x4c, 0x39, 0xc3, 0x0a,
+	0xc2, 0x2a, 0xa3, 0xf2, 0x1a, 0x63, 0x52, 0x29, 0x92, 0x94, 0xca, 0x72, 0x29, 0x12, 0x97, 0x8a,
+	0x7c, 0x15, 0xe7, 0xf9, 0x9a, 0x4b, 0xfa, 0x8a, 0x9e, 0x43, 0xd9, 0xb8, 0xb8, 0xf0, 0xb0, 0xef,
+	0xbb, 0x9e, 0xee, 0x5b, 0x26, 0x2f, 0x16, 0x73, 0x8a, 0x4f, 0x29, 0x12, 0x0d, 0xab, 0x10, 0x89,
+	0xaa, 0xd0, 0x0a, 0xbf, 0xc6, 0x11, 0x81, 0x5a, 0xf5, 0x69, 0xed, 0x74, 0x86, 0x98, 0x95, 0x0f,
+	0x51, 0x8b, 0xd6, 0xf4, 0x8a, 0x47, 0xfb, 0xcd, 0x2a, 0x47, 0x41, 0xcb, 0x87, 0x7b, 0x4d, 0x99,
+	0xd1, 0x46, 0xb3, 0xea, 0x50, 0xd0, 0xf2, 0xe1, 0x26, 0x2b, 0x7f, 0x16, 0x20, 0xdf, 0x7d, 0xeb,
+	0xf0, 0x94, 0x7e, 0x0c, 0x39, 0xb6, 0xd3, 0x29, 0x15, 0x97, 0x09, 0x68, 0x9c, 0x8d, 0x36, 0x21,
+	0x3f, 0x95, 0xdc, 0x15, 0x37, 0xc8, 0xd8, 0x27, 0x20, 0xb2, 0xa8, 0xb3, 0x0b, 0x4a, 0x2e, 0x15,
+	0xa0, 0xa9, 0x65, 0x1e, 0xb2, 0xd4, 0xe6, 0x35, 0xbe, 0x40, 0x12, 0x64, 0xa9, 0x97, 0x3c, 0xab,
+	0xf4, 0xa7, 0xf2, 0x4f, 0x11, 0xaa, 0x3d, 0xdb, 0x18, 0x62, 0x5e, 0xf3, 0x83, 0xd7, 0x63, 0xde,
+	0xe6, 0x87, 0xe6, 0x33, 0x77, 0x99, 0x0f, 0xab, 0x4e, 0xf6, 0xce, 0xaa, 0xf3, 0x43, 0xce, 0xc0,
+	0x54, 0x2d, 0x5f, 0xfe, 0xa1, 0xb5, 0x7c, 0x65, 0x61, 0x2d, 0xcf, 0xdf, 0xa7, 0x96, 0x17, 0xd2,
+	0x6b, 0x79, 0xb2, 0xe4, 0xc1, 0x7d, 0x4a, 0x5e, 0xf1, 0xfe, 0x25, 0x6f, 0xf5, 0xde, 0x25, 0xaf,
+	0x94, 0x5e, 0xf2, 0x3e, 0x82, 0xb2, 0x4b, 0x5f, 0x57, 0xfd, 0x2a, 0x7c, 0x73, 0xf9, 0xa3, 0x51,
+	0x72, 0x13, 0x6f, 0xee, 0xdc, 0xfa, 0x54, 0xf9, 0xc1, 0xf5, 0x49, 0x31, 0xa0, 0x12, 0x3f, 0x6f,
+	0x0f, 0xe8, 0x44, 0xa8, 0x1c, 0x7d, 0x59, 0x7c, 0x39, 0xb3, 0x93, 0x4d, 0xbf, 0x3f, 0x8c, 0xad,
+	0x0c, 0x01, 0x35, 0x0c, 0x67, 0x88, 0xed, 0xc4, 0x99, 0x9e, 0xee, 0x5e, 0x66, 0x73, 0x90, 0x49,
+	0xcb, 0xc1, 0xe4, 0x2a, 0x64, 0xe3, 0x57, 0x41, 0x79, 0x0e, 0x52, 0xc2, 0xc8, 0x43, 0x5a, 0xaa,
+	0x3f, 0x0a, 0x50, 0xad, 0x8f, 0xb0, 0x63, 0x2e, 0x74, 0x30, 0xba, 0x19, 0x99, 0x79, 0x37, 0x23,
+	0x3b, 0x75, 0x33, 0x66, 0x43, 0x12, 0x17, 0x87, 0x94, 0x4b, 0x84, 0x64, 0x40, 0x25, 0xee, 0xd5,
+	0x8f, 0xb1, 0x35, 0xdf, 0xc0, 0x46, 0xdb, 0xf2, 0x49, 0x77, 0x8c, 0x1d, 0xf6, 0xbd, 0x1f, 0x06,
+	0x3f, 0xeb, 0xba, 0xb0, 0xd8, 0xf5, 0x4c, 0xc2, 0xf5, 0xaf, 0x60, 0x6d, 0x5a, 0x2f, 0x75, 0x7f,
+	0x17, 0x96, 0x99, 0x23, 0xbe, 0x2c, 0xcc, 0xf8, 0xc5, 0xfd, 0x0f, 0xf8, 0xca, 0x1f, 0x04, 0x58,
+	0x63, 0x94, 0x63, 0xcb, 0x27, 0xae, 0x77, 0xfb, 0x6e, 0xfc, 0x62, 0x05, 0x85, 0xde, 0x5a, 0xdf,
+	0xfa, 0x9e, 0x17, 0xc3, 0x92, 0x96, 0xa7, 0x84, 0xbe, 0xf5, 0x3d, 0x2b, 0x12, 0x8c, 0x49, 0xdc,
+	0x4b, 0xec, 0x04, 0x5b, 0xc5, 0xc4, 0x07, 0x94, 0xa0, 0x60, 0xa8, 0x26, 0x3d, 0x7a, 0x50, 0x44,
+	0xe8, 0x63, 0xa8, 0x38, 0xf8, 0x86, 0xe8, 0x31, 0x13, 0xc1, 0x01, 0xa7, 0xe4, 0x5e, 0x64, 0x86,
+	0x46, 0xce, 0xf6, 0xe8, 0xbf, 0x27, 0xf2, 0x37, 0x50, 0x4d, 0x7a, 0x44, 0x23, 0xff, 0x0c, 0x96,
+	0x59, 0x1d, 0x0a, 0x23, 0x8f, 0xbf, 0x3e, 0xe1, 0x13, 0xab, 0x05, 0x22, 0xf7, 0x0e, 0xbe, 0x07,
+	0x95, 0x43, 0x8c, 0x35, 0x83, 0xe0, 0x77, 0x75, 0x12, 0x7f, 0x2d, 0x40, 0x69, 0xa2, 0x92, 0x3a,
+	0x3e, 0xef, 0x31, 0xdd, 0x06, 0xb8, 0x76, 0xed, 0xab, 0x11, 0xd6, 0xbf, 0x7c, 0x66, 0x06, 0x5a,
+	0x0a, 0x9c, 0xf2, 0xe5, 0x33, 0x73, 0xd2, 0x74, 0xd0, 0x8a, 0x9f, 0x8d, 0x35, 0x1d, 0xb4, 0xd6,
+	0x47, 0x4d, 0x07, 0x65, 0x8a, 0xb1, 0xa6, 0xe3, 0x60, 0xec, 0x2b, 0x87, 0xb0, 0x7d, 0x84, 0x49,
+	0x5a, 0x45, 0x7e, 0x50, 0x88, 0xca, 0xf7, 0xb0, 0xdd, 0x7f, 0x07, 0x7a, 0xd0, 0x3e, 0x88, 0x23,
+	0x37, 0xea, 0x1a, 0xee, 0x7a, 0x35, 0x98, 0xac, 0x72, 0x05, 0x72, 0xaa, 0x61, 0x9a, 0xd0, 0x1f,
+	0xd1, 0xec, 0xff, 0xc2, 0xa3, 0x23, 0x0f, 0x63, 0x62, 0x39, 0x17, 0x7d, 0xec, 0x5d, 0x5b, 0x43,
+	0x1c, 0xc6, 0x8a, 0x40, 0x8c, 0x99, 0x62, 0xbf, 0x95, 0x67, 0xb0, 0x3e, 0x23, 0x4d, 0x1d, 0x94,
+	0x61, 0x65, 0x84, 0x7d, 0xdf, 0xb8, 0x08, 0x6b, 0x77, 0xb8, 0x54, 0x1a, 0xb0, 0xa6, 0xe1, 0x21,
+	0x76, 0x08, 0x33, 0xef, 0xdf, 0xd5, 0x6f, 0xad, 0x43, 0xce, 0xb6, 0x46, 0x16, 0x61, 0x6a, 0x4a,
+	0x1a, 0x5f, 0x28, 0x3f, 0x85, 0x6a, 0x52, 0x49, 0x50, 0x18, 0x12, 0xd7, 0x63, 0xb6, 0x04, 0x07,
+	0x7c, 0xa5, 0x0e, 0xd5, 0x13, 0xc3, 0xbb, 0xc4, 0xa4, 0x69, 0x10, 0xe3, 0x1e, 0x1e, 0x98, 0x78,
+	0x4c, 0xde, 0x84, 0x1e, 0xb0, 0x85, 0xa2, 0x03, 0xb0, 0xee, 0xa4, 0x8d, 0xaf, 0xb1, 0x3d, 0x79,
+	0xa8, 0x84, 0x79, 0x0f, 0x55, 0x66, 0xea, 0xa1, 0x7a, 0x1f, 0x8a, 0xbc, 0xc3, 0x1d, 0xba, 0x57,
+	0x0e, 0x09, 0xca, 0x03, 0x30, 0x52, 0x83, 0x52, 0x14, 0x0c, 0xa5, 0x26, 0xb5, 0xd4, 0x77, 0x8c,
+	0xb1, 0xff, 0xc6, 0x25, 0xe8, 0x53, 0x10, 0xcf, 0x2c, 0x33, 0x0c, 0x6e, 0x23, 0x16, 0xdc, 0xc4,
+	0x11, 0x8d, 0x89, 0x50, 0x51, 0xc3, 0xbf, 0x0c, 0x9f, 0xa2, 0x79, 0xa2, 0x54, 0x44, 0x19, 0x42,
+	0x91, 0x2d, 0x5f, 0x31, 0xa0, 0x20, 0x6a, 0x6f, 0x85, 0xbb, 0xda, 0xdb, 0xcf, 0x20, 0x67, 0xd3,
+	0xef, 0x58, 0x60, 0x73, 0x6d, 0x70, 0x19, 0xe5, 0xef, 0x02, 0x54, 0x26, 0x09, 0x57, 0xe9, 0x81,
+	0x4b, 0x4c, 0x1b, 0xc2, 0xd4, 0xb4, 0x31, 0xaf, 0xa2, 0xfe, 0x1f, 0xe4, 0xfd, 0x20, 0x1d, 0x2c,
+	0x63, 0xc5, 0x7d, 0x39, 0x66, 0x37, 0x91, 0xae, 0xe3, 0x25, 0x2d, 0x92, 0x45, 0x4f, 0x43, 0x67,
+	0x45, 0xf6, 0x51, 0xbc, 0x4f, 0x8e, 0x05, 0x7f, 0xbc, 0x14, 0xf8, 0x8b, 0x76, 0xc3, 0x31, 0x25,
+	0x97, 0x3e, 0xa6, 0x50, 0x49, 0x26, 0x70, 0xb0, 0x02, 0x39, 0x76, 0x7f, 0x94, 0x0e, 0x54, 0x0e,
+	0x0c, 0x9b, 0xb6, 0x43, 0x0f, 0x2d, 0xa3, 0xeb, 0x90, 0x33, 0x7c, 0x1f, 0x93, 0xb0, 0xc9, 0x61,
+	0x0b, 0xe5, 0x12, 0x56, 0x02, 0x7d, 0x13, 0x01, 0x21, 0x26, 0x40, 0x67, 0x39, 0xe3, 0xda, 0xb0,
+	0x6c, 0xe3, 0xcc, 0x0e, 0xef, 0xd8, 0x84, 0x40, 0x33, 0x68, 0xbb, 0xc3, 0x4b, 0x6c, 0x86, 0x3d,
+	0x1b, 0x5f, 0x51, 0x5d, 0xc4, 0x25, 0x86, 0x1d, 0xce, 0x1a, 0x6c, 0xa1, 0x7c, 0x05, 0xa5, 0x89,
+	0xf3, 0xf4, 0x2a, 0x3d, 0x85, 0xfc, 0x59, 0x40, 0x08, 0xce, 0x1b, 0x8a, 0xe5, 0x20, 0x90, 0xd5,
+	0x22, 0x19, 0x05, 0x43, 0xb9, 0x89, 0xc7, 0xae, 0x6f, 0x91, 0x77, 0x11, 0x3c, 0xf5, 0xde, 0x18,
+	0x45, 0xf7, 0xa2, 0xa0, 0x05, 0x2b, 0xe5, 0x37, 0x02, 0x54, 0x4f, 0x2d, 0xf2, 0xc6, 0xf4, 0x8c,
+	0xb7, 0x86, 0xfd, 0x63, 0x9a, 0xa2, 0x05, 0xcc, 0x30, 0x4d, 0x0f, 0xfb, 0xe1, 0xe3, 0x12, 0x2e,
+	0x15, 0x1b, 0xa4, 0x81, 0x67, 0x38, 0xfe, 0xf9, 0xc3, 0x7b, 0xb7, 0x2f, 0x22, 0x7c, 0x89, 0x57,
+	0xe4, 0xcd, 0xe4, 0xc1, 0x62, 0x3a, 0x93, 0x10, 0x93, 0xb2, 0x09, 0x8f, 0x7b, 0xd8, 0x31, 0x2d,
+	0xe7, 0xa2, 0x3e, 0x1e, 0x7b, 0xee, 0xb5, 0x61, 0x87, 0x46, 0x15, 0x0d, 0x1e, 0x6b, 0xf8, 0xda,
+	0xc2, 0x6f, 0x67, 0x53, 0xf2, 0x3e, 0x14, 0x49, 0xa0, 0x4f, 0x8f, 0x3a, 0x6a, 0x08, 0x49, 0x2d,
+	0x93, 0x86, 0x1d, 0x40, 0x6c, 0xc1, 0x0d, 0xe3, 0x2b, 0xe5, 0x4f, 0x59, 0xc8, 0x87, 0x9e, 0xcc,
+	0xb4, 0xe3, 0x9f, 0x81, 0x78, 0x69, 0x39, 0xa6, 0x9c, 0x99, 0x41, 0xe5, 0xc2, 0x4f, 0x5e, 0x5a,
+	0x8e, 0xa9, 0x31, 0xa1, 0x94, 0x94, 0x64, 0x17, 0xee, 0x8a, 0x98, 0xbe, 0x2b, 0xb9, 0x79, 0xbb,
+	0xb2, 0x9c, 0xd8, 0x95, 0x58, 0x6a, 0x57, 0xee, 0x99, 0x5a, 0xea, 0xa1, 0x83, 0xb1, 0xe9, 0xeb,
+	0x46, 0x90, 0xd9, 0x60, 0xde, 0x2d, 0x31, 0x6a, 0x98, 0x6e, 0x9a, 0x4b, 0x2e, 0x80, 0x4d, 0xfd,
+	0xec, 0x36, 0x00, 0x3f, 0x20, 0x24, 0x1d, 0xdc, 0xa2, 0x35, 0xc8, 0x91, 0x1b, 0x9a, 0x66, 0x3e,
+	0xe4, 0x8a, 0xe4, 0x26, 0x91, 0xe0, 0x62, 0x3c, 0xc1, 0x53, 0x20, 0xee, 0xea, 0x62, 0x10, 0xb7,
+	0x34, 0x05, 0xe2, 0x2a, 0x3f, 0x83, 0x52, 0x18, 0x0c, 0xbf, 0xa8, 0x9f, 0x43, 0x3e, 0xdc, 0xd5,
+	0x60, 0x40, 0x59, 0x4b, 0x09, 0x5c, 0x8b, 0x84, 0x94, 0x06, 0x94, 0x63, 0xa7, 0x97, 0xaa, 0xf8,
+	0x82, 0x82, 0x7f, 0x01, 0x25, 0xa5, 0xb1, 0x8c, 0x74, 0x4c, 0xa4, 0x14, 0x03, 0xaa, 0xea, 0x0d,
+	0x1e, 0x5e, 0xd1, 0xb6, 0xe1, 0xa1, 0x77, 0xe0, 0x23, 0x28, 0x1b, 0xe7, 0x04, 0x7b, 0x7a, 0x54,
+	0xfd, 0x39, 0xc0, 0x53, 0x62, 0xd4, 0x7e, 0x40, 0x54, 0xfe, 0x22, 0x42, 0x25, 0xb2, 0xa1, 0xe1,
+	0xb1, 0xeb, 0x2d, 0x7e, 0x32, 0x16, 0x20, 0x46, 0x73, 0xe6, 0x57, 0xf4, 0x0c, 0x0a, 0xf8, 0x06,
+	0x0f, 0x75, 0x06, 0xd3, 0xa4, 0x20, 0xf8, 0x37, 0x78, 0xc8, 0x50, 0x9a, 0x3c, 0x0e, 0x7e, 0xc5,
+	0xf0, 0xe1, 0xdc, 0x3d, 0xf1, 0x61, 0xf1, 0x2e, 0x84, 0x2e, 0xc2, 0xaa, 0x78, 0xff, 0xb0, 0x32,
+	0xaf, 0x7f, 0xc8, 0x4f, 0xf5, 0x0f, 0x29, 0xd0, 0x73, 0x61, 0x1e, 0xf4, 0x6c, 0x1b, 0x3e, 0x49,
+	0xe2, 0x30, 0x94, 0xc2, 0xc1, 0x95, 0x0f, 0xa0, 0xc4, 0xd8, 0x91, 0x16, 0x7e, 0x5c, 0x57, 0x29,
+	0x31, 0xd2, 0x11, 0x41, 0x69, 0xab, 0x71, 0x28, 0xed, 0x3f, 0x43, 0xeb, 0x13, 0x58, 0x64, 0x79,
+	0x1a, 0x8b, 0x9c, 0x3d, 0x4c, 0x95, 0xb4, 0xc3, 0x14, 0xa0, 0x79, 0x52, 0x84, 0xe6, 0xed, 0x6d,
+	0x43, 0x3e, 0xcc, 0x2d, 0x5a, 0x81, 0xec, 0xc1, 0xab, 0xd7, 0xd2, 0x12, 0xca, 0x83, 0xd8, 0x57,
+	0xdb, 0x6d, 0x49, 0xd8, 0x7b, 0x0e, 0xc5, 0x18, 0x32, 0x46, 0x25, 0x8e, 0x06, 0x0d, 0x69, 0x89,
+	0xfe, 0x68, 0x75, 0x1b, 0x92, 0x40, 0x7f, 0x1c, 0x76, 0x5f, 0x4a, 0x19, 0xce, 0x6a, 0x4a, 0x59,
+	0xfa, 0xa3, 0x59, 0x7f, 0x2d, 0x89, 0x7b, 0x03, 0x58, 0x8d, 0xc7, 0x83, 0x2a, 0x50, 0xd4, 0xd4,
+	0x17, 0x6a, 0x63, 0xa0, 0x77, 0xba, 0x1d, 0x55, 0x5a, 0x42, 0x9b, 0xb0, 0xd1, 0xeb, 0xf6, 0x07,
+	0x7a, 0xb7, 0xd3, 0x7e, 0xad, 0x9f, 0x76, 0x5f, 0xb5, 0x9b, 0x7a, 0x43, 0xeb, 0xf6, 0xfb, 0x92,
+	0x80, 0x64, 0x58, 0xef, 0xab, 0xed, 0x43, 0x7d, 0xa0, 0xd5, 0x9b, 0xaa, 0xde, 0xd3, 0xd4, 0x6f,
+	0xd4, 0xce, 0x40, 0x6d, 0x4a, 0x99, 0xbd, 0xdf, 0x0a, 0xb0, 0x96, 0xd2, 0x8d, 0xa3, 0x55, 0xc8,
+	0xf7, 0x07, 0xbd, 0x50, 0xf5, 0x06, 0x54, 0xe9, 0xaa, 0x51, 0xef, 0x34, 0xd4, 0xb6, 0xde, 0x51,
+	0x4f, 0xd5, 0xfe, 0x40, 0x12, 0xa6, 0xc8, 0xdd, 0x76, 0x93, 0x92, 0x33, 0x68, 0x0d, 0x2a, 0x31,
+	0xf2, 0x41, 0x77, 0x70, 0x2c, 0x65, 0xd1, 0x16, 0xc8, 0x94, 0xd8, 0x54, 0x1b, 0x9a, 0x7a, 0xa2,
+	0x76, 0x06, 0x7a, 0xbd, 0xd3, 0x0c, 0x44, 0x24, 0x71, 0xaf, 0x01, 0x85, 0x08, 0x97, 0x44, 0x05,
+	0xc8, 0xb5, 0x5b, 0x27, 0xad, 0x81, 0xb4, 0x84, 0x00, 0x96, 0x4f, 0xea, 0xda, 0x4b, 0x95, 0x5a,
+	0xab, 0x40, 0xb1, 0x3f, 0xe8, 0xf6, 0xf4, 0x80, 0x90, 0x41, 0x65, 0x00, 0x46, 0xe0, 0xc2, 0xd9,
+	0xbd, 0x33, 0x28, 0xc6, 0xae, 0x01, 0xcd, 0x5c, 0x47, 0x3d, 0x95, 0x96, 0xd0, 0x3a, 0x48, 0xbd,
+	0xba, 0x36, 0x68, 0xd5, 0xdb, 0xed, 0xd7, 0xfa, 0x61, 0xab, 0xdd, 0x56, 0x9b, 0x92, 0x40, 0x55,
+	0x07, 0xbf, 0x33, 0xa8, 0x04, 0x05, 0xee, 0x0a, 0x5d, 0x66, 0x51, 0x11, 0x56, 0xd4, 0x6f, 0x7b,
+	0x2d, 0x4d, 0x6d, 0x4a, 0x22, 0xcd, 0x04, 0xcf, 0xb3, 0xda, 0x94, 0x72, 0x7b, 0x2f, 0x60, 0x35,
+	0xfe, 0xda, 0xa0, 0x6d, 0xd8, 0x1c, 0x68, 0xf5, 0x4e, 0xff, 0x50, 0xd5, 0xf4, 0x97, 0xad, 0x4e,
+	0x53, 0x7f, 0xd5, 0xe9, 0xf7, 0xd4, 0x46, 0xeb, 0xb0, 0xa5, 0x36, 0xa5, 0x25, 0xaa, 0xa9, 0xa9,
+	0xf6, 0xba, 0xfd, 0x16, 0x0d, 0xa0, 0x0c, 0x70, 0xda, 0x1a, 0x1c, 0x37, 0xb5, 0xfa, 0x69, 0xbd,
+	0x2d, 0x65, 0xf6, 0x7e, 0x35, 0x29, 0x86, 0x81, 0xcb, 0x12, 0xac, 0x46, 0xda, 0xea, 0x9d, 0xd7,
+	0xdc, 0xf7, 0x88, 0xd2, 0x53, 0x3b, 0xcd, 0x56, 0xe7, 0x48, 0x12, 0xd0, 0x23, 0x40, 0x11, 0xb5,
+	0xd1, 0xed, 0x1c, 0xb6, 0xb4, 0x13, 0x16, 0xc7, 0x06, 0x54, 0x23, 0x7a, 0xe4, 0x74, 0x76, 0x4a,
+	0xfc, 0xa4, 0xd7, 0x56, 0x29, 0x5d, 0xdc, 0xfb, 0xbd, 0x00, 0xf9, 0xb0, 0xce, 0xa0, 0x2a, 0x94,
+	0xd4, 0x6f, 0xd5, 0x86, 0x5e, 0x6f, 0x34, 0xd4, 0xde, 0x80, 0x79, 0x5f, 0x82, 0x02, 0x23, 0xd1,
+	0x3c, 0x49, 0x02, 0xf5, 0x8e, 0x4b, 0x9c, 0xa8, 0x9d, 0x26, 0xb3, 0x87, 0xa0, 0xcc, 0x28, 0x03,
+	0xad, 0x75, 0x74, 0xa4, 0x6a, 0xcc, 0x58, 0x48, 0x9b, 0x24, 0x54, 0x8c, 0xbe, 0x0c, 0xb3, 0x9a,
+	0x8b, 0xac, 0x45, 0x5e, 0x2e, 0xef, 0xff, 0x22, 0xf6, 0x57, 0xd4, 0x60, 0x9a, 0x43, 0xc7, 0xb0,
+	0x7a, 0x84, 0x49, 0x44, 0x46, 0x4f, 0xa6, 0x4b, 0x5e, 0xec, 0x4f, 0xae, 0xb5, 0xcd, 0x74, 0xe6,
+	0xd8, 0xbe, 0x55, 0x96, 0xf6, 0x7f, 0xb7, 0xcc, 0xb2, 0x6d, 0x4e, 0x46, 0x45, 0x74, 0x0c, 0x30,
+	0x41, 0x42, 0xd1, 0x56, 0x7c, 0x86, 0x98, 0x06, 0xe4, 0x6b, 0xb5, 0x39, 0x5c, 0xa6, 0x1c, 0xbd,
+	0x84, 0x62, 0x0c, 0x8b, 0x44, 0xdb, 0x31, 0xe1, 0x59, 0x20, 0xb4, 0xf6, 0x64, 0x1e, 0x9b, 0x2b,
+	0x3b, 0x06, 0x98, 0xa0, 0x80, 0x09, 0xb7, 0x66, 0x20, 0xcb, 0x5a, 0x6d, 0x0e, 0x97, 0x6b, 0x1a,
+	0x40, 0x39, 0x09, 0xca, 0xa1, 0x9d, 0xf8, 0xec, 0x91, 0x86, 0x03, 0xd6, 0xde, 0x5b, 0x20, 0xc1,
+	0xb5, 0x7e, 0x0d, 0x95, 0x70, 0x4f, 0x02, 0x7c, 0x08, 0xbd, 0x37, 0x9d, 0xf9, 0x24, 0x94, 0x55,
+	0xdb, 0x9a, 0xcb, 0x8f, 0xab, 0x8c, 0x43, 0x4e, 0x09, 0x95, 0x29, 0xe8, 0x58, 0x6d, 0x6b, 0x2e,
+	0x9f, 0xab, 0x54, 0xa1, 0x78, 0x84, 0x49, 0x08, 0x04, 0xa1, 0x78, 0xa2, 0xa6, 0x00, 0xa7, 0x9a,
+	0x9c, 0xca, 0xe3, 0x6a, 0x2c, 0x78, 0x94, 0x0e, 0xe5, 0xa0, 0xdd, 0xd8, 0x57, 0x0b, 0xd1, 0x9e,
+	0xda, 0x07, 0x77, 0x20, 0x1f, 0x13, 0x53, 0xfd, 0xbb, 0x4d, 0xf5, 0xdf, 0x81, 0xa9, 0xfd, 0x21,
+	0x54, 0xa6, 0x70, 0x13, 0xd4, 0x83, 0x7c, 0x48, 0x42, 0xff, 0x13, 0x0f, 0x2d, 0x15, 0x8d, 0xa9,
+	0xbd, 0xbf, 0x48, 0x84, 0x1b, 0xf9, 0xab, 0x10, 0xc7, 0x39, 0x42, 0x3b, 0x7d, 0x58, 0xeb, 0x5f,
+	0x9d, 0xf9, 0x43, 0xcf, 0x3a, 0xc3, 0x13, 0x6e, 0xe2, 0x98, 0xcf, 0x80, 0x23, 0xb5, 0x5a, 0x2a,
+	0x97, 0x4d, 0xf2, 0xca, 0xd2, 0x33, 0x21, 0x38, 0x3f, 0x71, 0x4c, 0x26, 0x71, 0x7e, 0x52, 0x10,
+	0x9f, 0xda, 0xd6, 0x5c, 0x3e, 0xf7, 0xfe, 0x14, 0xca, 0xc1, 0xa0, 0x19, 0x7a, 0xce, 0x4f, 0x54,
+	0x40, 0x4c, 0x9e, 0xa8, 0xa9, 0xd9, 0xbb, 0x26, 0xa7, 0xf2, 0xb8, 0xe2, 0x7f, 0x65, 0xa1, 0x12,
+	0x95, 0xfd, 0x40, 0xf5, 0x11, 0x94, 0x83, 0x4f, 0x83, 0x39, 0x16, 0x6d, 0x26, 0x91, 0x85, 0xd8,
+	0x6c, 0x9b, 0x50, 0x9e, 0x68, 0xc7, 0x95, 0x25, 0x74, 0x02, 0xd5, 0x40, 0x6c, 0x32, 0x95, 0x25,
+	0x72, 0x3b, 0x33, 0xac, 0x2d, 0x54, 0xc7, 0xcb, 0x6f, 0x48, 0xf5, 0x13, 0xe5, 0x77, 0x7a, 0x0a,
+	0xad, 0x6d, 0xa6, 0x33, 0xb9, 0xa6, 0x6f, 0x60, 0xed, 0x08, 0x93, 0xe9, 0x59, 0x12, 0x29, 0xf1,
+	0xb2, 0x9a, 0x3e, 0x68, 0x2e, 0xd6, 0xdb, 0x87, 0x2a, 0xff, 0x00, 0xc7, 0x02, 0x56, 0x12, 0x7b,
+	0x9b, 0x3a, 0xa3, 0x2e, 0x0c, 0x5b, 0x03, 0x89, 0xb7, 0x5a, 0xef, 0x4e, 0xe7, 0xfe, 0x05, 0x48,
+	0xd1, 0x40, 0x91, 0x76, 0x17, 0x22, 0xa6, 0x9f, 0xd8, 0xaf, 0x99, 0x41, 0xa7, 0x56, 0x4b, 0xe3,
+	0xf2, 0x11, 0x85, 0xde, 0x85, 0x83, 0xdc, 0xcf, 0xb3, 0x9f, 0x8f, 0xcf, 0xce, 0x96, 0xd9, 0x7f,
+	0x29, 0x7d, 0xf9, 0xef, 0x01, 0x00, 0x16, 0xe4, 0x4f, 0x1b, 0xbe, 0x24, 0x00, 0x00,
 }
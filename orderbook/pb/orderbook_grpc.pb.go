@@ -25,6 +25,9 @@ const (
 // OrderInfoServiceClient is the client API for OrderInfoService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Every call needs "authorization: Bearer <token>" metadata with a token issued by user_auth.
+// owner_username fields may be left empty, they are refused when they name another user than the token's.
 type OrderInfoServiceClient interface {
 	GetOrderInfo(ctx context.Context, in *OrderInfoRequest, opts ...grpc.CallOption) (*OrderInfoReply, error)
 }
@@ -50,6 +53,9 @@ func (c *orderInfoServiceClient) GetOrderInfo(ctx context.Context, in *OrderInfo
 // OrderInfoServiceServer is the server API for OrderInfoService service.
 // All implementations must embed UnimplementedOrderInfoServiceServer
 // for forward compatibility.
+//
+// Every call needs "authorization: Bearer <token>" metadata with a token issued by user_auth.
+// owner_username fields may be left empty, they are refused when they name another user than the token's.
 type OrderInfoServiceServer interface {
 	GetOrderInfo(context.Context, *OrderInfoRequest) (*OrderInfoReply, error)
 	mustEmbedUnimplementedOrderInfoServiceServer()
@@ -120,6 +126,416 @@ var OrderInfoService_ServiceDesc = grpc.ServiceDesc{
 	Metadata: "proto/orderbook.proto",
 }
 
+const (
+	TradingService_PlaceOrder_FullMethodName             = "/orderbook.TradingService/PlaceOrder"
+	TradingService_CancelOrder_FullMethodName            = "/orderbook.TradingService/CancelOrder"
+	TradingService_AmendOrder_FullMethodName             = "/orderbook.TradingService/AmendOrder"
+	TradingService_ListOpenOrders_FullMethodName         = "/orderbook.TradingService/ListOpenOrders"
+	TradingService_GetOrderHistory_FullMethodName        = "/orderbook.TradingService/GetOrderHistory"
+	TradingService_GetTradeHistory_FullMethodName        = "/orderbook.TradingService/GetTradeHistory"
+	TradingService_GetFeeRates_FullMethodName            = "/orderbook.TradingService/GetFeeRates"
+	TradingService_GetSelfTradePrevention_FullMethodName = "/orderbook.TradingService/GetSelfTradePrevention"
+	TradingService_SetSelfTradePrevention_FullMethodName = "/orderbook.TradingService/SetSelfTradePrevention"
+)
+
+// TradingServiceClient is the client API for TradingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Trading
+type TradingServiceClient interface {
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderReply, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderReply, error)
+	AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderReply, error)
+	ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersReply, error)
+	GetOrderHistory(ctx context.Context, in *OrderHistoryRequest, opts ...grpc.CallOption) (*OrderHistoryReply, error)
+	GetTradeHistory(ctx context.Context, in *TradeHistoryRequest, opts ...grpc.CallOption) (*TradeHistoryReply, error)
+	GetFeeRates(ctx context.Context, in *FeeRatesRequest, opts ...grpc.CallOption) (*FeeRatesReply, error)
+	GetSelfTradePrevention(ctx context.Context, in *GetSelfTradePreventionRequest, opts ...grpc.CallOption) (*SelfTradePreventionReply, error)
+	SetSelfTradePrevention(ctx context.Context, in *SetSelfTradePreventionRequest, opts ...grpc.CallOption) (*SelfTradePreventionReply, error)
+}
+
+type tradingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTradingServiceClient(cc grpc.ClientConnInterface) TradingServiceClient {
+	return &tradingServiceClient{cc}
+}
+
+func (c *tradingServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaceOrderReply)
+	err := c.cc.Invoke(ctx, TradingService_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderReply)
+	err := c.cc.Invoke(ctx, TradingService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AmendOrderReply)
+	err := c.cc.Invoke(ctx, TradingService_AmendOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) ListOpenOrders(ctx context.Context, in *ListOpenOrdersRequest, opts ...grpc.CallOption) (*ListOpenOrdersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOpenOrdersReply)
+	err := c.cc.Invoke(ctx, TradingService_ListOpenOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetOrderHistory(ctx context.Context, in *OrderHistoryRequest, opts ...grpc.CallOption) (*OrderHistoryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderHistoryReply)
+	err := c.cc.Invoke(ctx, TradingService_GetOrderHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetTradeHistory(ctx context.Context, in *TradeHistoryRequest, opts ...grpc.CallOption) (*TradeHistoryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TradeHistoryReply)
+	err := c.cc.Invoke(ctx, TradingService_GetTradeHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetFeeRates(ctx context.Context, in *FeeRatesRequest, opts ...grpc.CallOption) (*FeeRatesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeeRatesReply)
+	err := c.cc.Invoke(ctx, TradingService_GetFeeRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetSelfTradePrevention(ctx context.Context, in *GetSelfTradePreventionRequest, opts ...grpc.CallOption) (*SelfTradePreventionReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelfTradePreventionReply)
+	err := c.cc.Invoke(ctx, TradingService_GetSelfTradePrevention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) SetSelfTradePrevention(ctx context.Context, in *SetSelfTradePreventionRequest, opts ...grpc.CallOption) (*SelfTradePreventionReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelfTradePreventionReply)
+	err := c.cc.Invoke(ctx, TradingService_SetSelfTradePrevention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TradingServiceServer is the server API for TradingService service.
+// All implementations must embed UnimplementedTradingServiceServer
+// for forward compatibility.
+//
+// Trading
+type TradingServiceServer interface {
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderReply, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderReply, error)
+	AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderReply, error)
+	ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersReply, error)
+	GetOrderHistory(context.Context, *OrderHistoryRequest) (*OrderHistoryReply, error)
+	GetTradeHistory(context.Context, *TradeHistoryRequest) (*TradeHistoryReply, error)
+	GetFeeRates(context.Context, *FeeRatesRequest) (*FeeRatesReply, error)
+	GetSelfTradePrevention(context.Context, *GetSelfTradePreventionRequest) (*SelfTradePreventionReply, error)
+	SetSelfTradePrevention(context.Context, *SetSelfTradePreventionRequest) (*SelfTradePreventionReply, error)
+	mustEmbedUnimplementedTradingServiceServer()
+}
+
+// UnimplementedTradingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTradingServiceServer struct{}
+
+func (UnimplementedTradingServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) ListOpenOrders(context.Context, *ListOpenOrdersRequest) (*ListOpenOrdersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOpenOrders not implemented")
+}
+func (UnimplementedTradingServiceServer) GetOrderHistory(context.Context, *OrderHistoryRequest) (*OrderHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderHistory not implemented")
+}
+func (UnimplementedTradingServiceServer) GetTradeHistory(context.Context, *TradeHistoryRequest) (*TradeHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTradeHistory not implemented")
+}
+func (UnimplementedTradingServiceServer) GetFeeRates(context.Context, *FeeRatesRequest) (*FeeRatesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeRates not implemented")
+}
+func (UnimplementedTradingServiceServer) GetSelfTradePrevention(context.Context, *GetSelfTradePreventionRequest) (*SelfTradePreventionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSelfTradePrevention not implemented")
+}
+func (UnimplementedTradingServiceServer) SetSelfTradePrevention(context.Context, *SetSelfTradePreventionRequest) (*SelfTradePreventionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSelfTradePrevention not implemented")
+}
+func (UnimplementedTradingServiceServer) mustEmbedUnimplementedTradingServiceServer() {}
+func (UnimplementedTradingServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeTradingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TradingServiceServer will
+// result in compilation errors.
+type UnsafeTradingServiceServer interface {
+	mustEmbedUnimplementedTradingServiceServer()
+}
+
+func RegisterTradingServiceServer(s grpc.ServiceRegistrar, srv TradingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTradingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TradingService_ServiceDesc, srv)
+}
+
+func _TradingService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).AmendOrder(ctx, req.(*AmendOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_ListOpenOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).ListOpenOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_ListOpenOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).ListOpenOrders(ctx, req.(*ListOpenOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetOrderHistory(ctx, req.(*OrderHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetTradeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TradeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetTradeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetTradeHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetTradeHistory(ctx, req.(*TradeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetFeeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetFeeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetFeeRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetFeeRates(ctx, req.(*FeeRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetSelfTradePrevention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSelfTradePreventionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetSelfTradePrevention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetSelfTradePrevention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetSelfTradePrevention(ctx, req.(*GetSelfTradePreventionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_SetSelfTradePrevention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSelfTradePreventionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).SetSelfTradePrevention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_SetSelfTradePrevention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).SetSelfTradePrevention(ctx, req.(*SetSelfTradePreventionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TradingService_ServiceDesc is the grpc.ServiceDesc for TradingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TradingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderbook.TradingService",
+	HandlerType: (*TradingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _TradingService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _TradingService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _TradingService_AmendOrder_Handler,
+		},
+		{
+			MethodName: "ListOpenOrders",
+			Handler:    _TradingService_ListOpenOrders_Handler,
+		},
+		{
+			MethodName: "GetOrderHistory",
+			Handler:    _TradingService_GetOrderHistory_Handler,
+		},
+		{
+			MethodName: "GetTradeHistory",
+			Handler:    _TradingService_GetTradeHistory_Handler,
+		},
+		{
+			MethodName: "GetFeeRates",
+			Handler:    _TradingService_GetFeeRates_Handler,
+		},
+		{
+			MethodName: "GetSelfTradePrevention",
+			Handler:    _TradingService_GetSelfTradePrevention_Handler,
+		},
+		{
+			MethodName: "SetSelfTradePrevention",
+			Handler:    _TradingService_SetSelfTradePrevention_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/orderbook.proto",
+}
+
 const (
 	GreetingService_Greeting_FullMethodName = "/orderbook.GreetingService/Greeting"
 )
@@ -225,3 +641,666 @@ var GreetingService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/orderbook.proto",
 }
+
+const (
+	MarketDataService_SubscribeMarketData_FullMethodName = "/orderbook.MarketDataService/SubscribeMarketData"
+	MarketDataService_GetRecentTrades_FullMethodName     = "/orderbook.MarketDataService/GetRecentTrades"
+)
+
+// MarketDataServiceClient is the client API for MarketDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Market data
+type MarketDataServiceClient interface {
+	SubscribeMarketData(ctx context.Context, in *MarketDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarketDataEvent], error)
+	GetRecentTrades(ctx context.Context, in *RecentTradesRequest, opts ...grpc.CallOption) (*RecentTradesReply, error)
+}
+
+type marketDataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMarketDataServiceClient(cc grpc.ClientConnInterface) MarketDataServiceClient {
+	return &marketDataServiceClient{cc}
+}
+
+func (c *marketDataServiceClient) SubscribeMarketData(ctx context.Context, in *MarketDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarketDataEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[0], MarketDataService_SubscribeMarketData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MarketDataRequest, MarketDataEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_SubscribeMarketDataClient = grpc.ServerStreamingClient[MarketDataEvent]
+
+func (c *marketDataServiceClient) GetRecentTrades(ctx context.Context, in *RecentTradesRequest, opts ...grpc.CallOption) (*RecentTradesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecentTradesReply)
+	err := c.cc.Invoke(ctx, MarketDataService_GetRecentTrades_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MarketDataServiceServer is the server API for MarketDataService service.
+// All implementations must embed UnimplementedMarketDataServiceServer
+// for forward compatibility.
+//
+// Market data
+type MarketDataServiceServer interface {
+	SubscribeMarketData(*MarketDataRequest, grpc.ServerStreamingServer[MarketDataEvent]) error
+	GetRecentTrades(context.Context, *RecentTradesRequest) (*RecentTradesReply, error)
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+// UnimplementedMarketDataServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMarketDataServiceServer struct{}
+
+func (UnimplementedMarketDataServiceServer) SubscribeMarketData(*MarketDataRequest, grpc.ServerStreamingServer[MarketDataEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeMarketData not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetRecentTrades(context.Context, *RecentTradesRequest) (*RecentTradesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecentTrades not implemented")
+}
+func (UnimplementedMarketDataServiceServer) mustEmbedUnimplementedMarketDataServiceServer() {}
+func (UnimplementedMarketDataServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeMarketDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MarketDataServiceServer will
+// result in compilation errors.
+type UnsafeMarketDataServiceServer interface {
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+func RegisterMarketDataServiceServer(s grpc.ServiceRegistrar, srv MarketDataServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMarketDataServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MarketDataService_ServiceDesc, srv)
+}
+
+func _MarketDataService_SubscribeMarketData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MarketDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).SubscribeMarketData(m, &grpc.GenericServerStream[MarketDataRequest, MarketDataEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_SubscribeMarketDataServer = grpc.ServerStreamingServer[MarketDataEvent]
+
+func _MarketDataService_GetRecentTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecentTradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetRecentTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetRecentTrades_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetRecentTrades(ctx, req.(*RecentTradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MarketDataService_ServiceDesc is the grpc.ServiceDesc for MarketDataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MarketDataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderbook.MarketDataService",
+	HandlerType: (*MarketDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRecentTrades",
+			Handler:    _MarketDataService_GetRecentTrades_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeMarketData",
+			Handler:       _MarketDataService_SubscribeMarketData_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/orderbook.proto",
+}
+
+const (
+	BalanceService_GetBalances_FullMethodName = "/orderbook.BalanceService/GetBalances"
+)
+
+// BalanceServiceClient is the client API for BalanceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Balances
+type BalanceServiceClient interface {
+	GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*BalancesReply, error)
+}
+
+type balanceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBalanceServiceClient(cc grpc.ClientConnInterface) BalanceServiceClient {
+	return &balanceServiceClient{cc}
+}
+
+func (c *balanceServiceClient) GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*BalancesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalancesReply)
+	err := c.cc.Invoke(ctx, BalanceService_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BalanceServiceServer is the server API for BalanceService service.
+// All implementations must embed UnimplementedBalanceServiceServer
+// for forward compatibility.
+//
+// Balances
+type BalanceServiceServer interface {
+	GetBalances(context.Context, *BalancesRequest) (*BalancesReply, error)
+	mustEmbedUnimplementedBalanceServiceServer()
+}
+
+// UnimplementedBalanceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBalanceServiceServer struct{}
+
+func (UnimplementedBalanceServiceServer) GetBalances(context.Context, *BalancesRequest) (*BalancesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedBalanceServiceServer) mustEmbedUnimplementedBalanceServiceServer() {}
+func (UnimplementedBalanceServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeBalanceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BalanceServiceServer will
+// result in compilation errors.
+type UnsafeBalanceServiceServer interface {
+	mustEmbedUnimplementedBalanceServiceServer()
+}
+
+func RegisterBalanceServiceServer(s grpc.ServiceRegistrar, srv BalanceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBalanceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BalanceService_ServiceDesc, srv)
+}
+
+func _BalanceService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalanceService_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServiceServer).GetBalances(ctx, req.(*BalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BalanceService_ServiceDesc is the grpc.ServiceDesc for BalanceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BalanceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderbook.BalanceService",
+	HandlerType: (*BalanceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalances",
+			Handler:    _BalanceService_GetBalances_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/orderbook.proto",
+}
+
+const (
+	TransferService_RequestDeposit_FullMethodName      = "/orderbook.TransferService/RequestDeposit"
+	TransferService_RequestWithdrawal_FullMethodName   = "/orderbook.TransferService/RequestWithdrawal"
+	TransferService_GetTransfers_FullMethodName        = "/orderbook.TransferService/GetTransfers"
+	TransferService_GetPendingApprovals_FullMethodName = "/orderbook.TransferService/GetPendingApprovals"
+	TransferService_ApproveWithdrawal_FullMethodName   = "/orderbook.TransferService/ApproveWithdrawal"
+	TransferService_RejectWithdrawal_FullMethodName    = "/orderbook.TransferService/RejectWithdrawal"
+)
+
+// TransferServiceClient is the client API for TransferService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Transfers
+// A deposit is credited once custody settles it. A withdrawal holds its funds from the request on, one above
+// the asset's approval threshold waits for an admin (ApproveWithdrawal / RejectWithdrawal).
+type TransferServiceClient interface {
+	RequestDeposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransferReply, error)
+	RequestWithdrawal(ctx context.Context, in *WithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error)
+	GetTransfers(ctx context.Context, in *TransfersRequest, opts ...grpc.CallOption) (*TransfersReply, error)
+	GetPendingApprovals(ctx context.Context, in *PendingApprovalsRequest, opts ...grpc.CallOption) (*TransfersReply, error)
+	ApproveWithdrawal(ctx context.Context, in *ReviewWithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error)
+	RejectWithdrawal(ctx context.Context, in *ReviewWithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error)
+}
+
+type transferServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransferServiceClient(cc grpc.ClientConnInterface) TransferServiceClient {
+	return &transferServiceClient{cc}
+}
+
+func (c *transferServiceClient) RequestDeposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransferReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferReply)
+	err := c.cc.Invoke(ctx, TransferService_RequestDeposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) RequestWithdrawal(ctx context.Context, in *WithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferReply)
+	err := c.cc.Invoke(ctx, TransferService_RequestWithdrawal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) GetTransfers(ctx context.Context, in *TransfersRequest, opts ...grpc.CallOption) (*TransfersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransfersReply)
+	err := c.cc.Invoke(ctx, TransferService_GetTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) GetPendingApprovals(ctx context.Context, in *PendingApprovalsRequest, opts ...grpc.CallOption) (*TransfersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransfersReply)
+	err := c.cc.Invoke(ctx, TransferService_GetPendingApprovals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) ApproveWithdrawal(ctx context.Context, in *ReviewWithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferReply)
+	err := c.cc.Invoke(ctx, TransferService_ApproveWithdrawal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transferServiceClient) RejectWithdrawal(ctx context.Context, in *ReviewWithdrawalRequest, opts ...grpc.CallOption) (*TransferReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferReply)
+	err := c.cc.Invoke(ctx, TransferService_RejectWithdrawal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransferServiceServer is the server API for TransferService service.
+// All implementations must embed UnimplementedTransferServiceServer
+// for forward compatibility.
+//
+// Transfers
+// A deposit is credited once custody settles it. A withdrawal holds its funds from the request on, one above
+// the asset's approval threshold waits for an admin (ApproveWithdrawal / RejectWithdrawal).
+type TransferServiceServer interface {
+	RequestDeposit(context.Context, *DepositRequest) (*TransferReply, error)
+	RequestWithdrawal(context.Context, *WithdrawalRequest) (*TransferReply, error)
+	GetTransfers(context.Context, *TransfersRequest) (*TransfersReply, error)
+	GetPendingApprovals(context.Context, *PendingApprovalsRequest) (*TransfersReply, error)
+	ApproveWithdrawal(context.Context, *ReviewWithdrawalRequest) (*TransferReply, error)
+	RejectWithdrawal(context.Context, *ReviewWithdrawalRequest) (*TransferReply, error)
+	mustEmbedUnimplementedTransferServiceServer()
+}
+
+// UnimplementedTransferServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTransferServiceServer struct{}
+
+func (UnimplementedTransferServiceServer) RequestDeposit(context.Context, *DepositRequest) (*TransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestDeposit not implemented")
+}
+func (UnimplementedTransferServiceServer) RequestWithdrawal(context.Context, *WithdrawalRequest) (*TransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestWithdrawal not implemented")
+}
+func (UnimplementedTransferServiceServer) GetTransfers(context.Context, *TransfersRequest) (*TransfersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransfers not implemented")
+}
+func (UnimplementedTransferServiceServer) GetPendingApprovals(context.Context, *PendingApprovalsRequest) (*TransfersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingApprovals not implemented")
+}
+func (UnimplementedTransferServiceServer) ApproveWithdrawal(context.Context, *ReviewWithdrawalRequest) (*TransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveWithdrawal not implemented")
+}
+func (UnimplementedTransferServiceServer) RejectWithdrawal(context.Context, *ReviewWithdrawalRequest) (*TransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectWithdrawal not implemented")
+}
+func (UnimplementedTransferServiceServer) mustEmbedUnimplementedTransferServiceServer() {}
+func (UnimplementedTransferServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTransferServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransferServiceServer will
+// result in compilation errors.
+type UnsafeTransferServiceServer interface {
+	mustEmbedUnimplementedTransferServiceServer()
+}
+
+func RegisterTransferServiceServer(s grpc.ServiceRegistrar, srv TransferServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTransferServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TransferService_ServiceDesc, srv)
+}
+
+func _TransferService_RequestDeposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).RequestDeposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_RequestDeposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).RequestDeposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_RequestWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).RequestWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_RequestWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).RequestWithdrawal(ctx, req.(*WithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_GetTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).GetTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_GetTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).GetTransfers(ctx, req.(*TransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_GetPendingApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingApprovalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).GetPendingApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_GetPendingApprovals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).GetPendingApprovals(ctx, req.(*PendingApprovalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_ApproveWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).ApproveWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_ApproveWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).ApproveWithdrawal(ctx, req.(*ReviewWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransferService_RejectWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServiceServer).RejectWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransferService_RejectWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServiceServer).RejectWithdrawal(ctx, req.(*ReviewWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransferService_ServiceDesc is the grpc.ServiceDesc for TransferService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransferService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderbook.TransferService",
+	HandlerType: (*TransferServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequestDeposit",
+			Handler:    _TransferService_RequestDeposit_Handler,
+		},
+		{
+			MethodName: "RequestWithdrawal",
+			Handler:    _TransferService_RequestWithdrawal_Handler,
+		},
+		{
+			MethodName: "GetTransfers",
+			Handler:    _TransferService_GetTransfers_Handler,
+		},
+		{
+			MethodName: "GetPendingApprovals",
+			Handler:    _TransferService_GetPendingApprovals_Handler,
+		},
+		{
+			MethodName: "ApproveWithdrawal",
+			Handler:    _TransferService_ApproveWithdrawal_Handler,
+		},
+		{
+			MethodName: "RejectWithdrawal",
+			Handler:    _TransferService_RejectWithdrawal_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/orderbook.proto",
+}
+
+const (
+	ExecutionService_SubscribeExecutions_FullMethodName = "/orderbook.ExecutionService/SubscribeExecutions"
+)
+
+// ExecutionServiceClient is the client API for ExecutionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Executions
+type ExecutionServiceClient interface {
+	SubscribeExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecutionReport], error)
+}
+
+type executionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExecutionServiceClient(cc grpc.ClientConnInterface) ExecutionServiceClient {
+	return &executionServiceClient{cc}
+}
+
+func (c *executionServiceClient) SubscribeExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecutionReport], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExecutionService_ServiceDesc.Streams[0], ExecutionService_SubscribeExecutions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecutionsRequest, ExecutionReport]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_SubscribeExecutionsClient = grpc.ServerStreamingClient[ExecutionReport]
+
+// ExecutionServiceServer is the server API for ExecutionService service.
+// All implementations must embed UnimplementedExecutionServiceServer
+// for forward compatibility.
+//
+// Executions
+type ExecutionServiceServer interface {
+	SubscribeExecutions(*ExecutionsRequest, grpc.ServerStreamingServer[ExecutionReport]) error
+	mustEmbedUnimplementedExecutionServiceServer()
+}
+
+// UnimplementedExecutionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExecutionServiceServer struct{}
+
+func (UnimplementedExecutionServiceServer) SubscribeExecutions(*ExecutionsRequest, grpc.ServerStreamingServer[ExecutionReport]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeExecutions not implemented")
+}
+func (UnimplementedExecutionServiceServer) mustEmbedUnimplementedExecutionServiceServer() {}
+func (UnimplementedExecutionServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeExecutionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExecutionServiceServer will
+// result in compilation errors.
+type UnsafeExecutionServiceServer interface {
+	mustEmbedUnimplementedExecutionServiceServer()
+}
+
+func RegisterExecutionServiceServer(s grpc.ServiceRegistrar, srv ExecutionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExecutionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExecutionService_ServiceDesc, srv)
+}
+
+func _ExecutionService_SubscribeExecutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecutionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutionServiceServer).SubscribeExecutions(m, &grpc.GenericServerStream[ExecutionsRequest, ExecutionReport]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExecutionService_SubscribeExecutionsServer = grpc.ServerStreamingServer[ExecutionReport]
+
+// ExecutionService_ServiceDesc is the grpc.ServiceDesc for ExecutionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExecutionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orderbook.ExecutionService",
+	HandlerType: (*ExecutionServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeExecutions",
+			Handler:       _ExecutionService_SubscribeExecutions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/orderbook.proto",
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NOTE: jwtSecret verifies the HS256 tokens user_auth issues at login, see SetAuth. Until it's set no call gets in.
+var jwtSecret []byte
+
+// NOTE: admins are the usernames allowed to review withdrawals, see SetAuth.
+var admins = map[string]bool{}
+
+/*
+SetAuth sets the key bearer tokens are verified with, the one user_auth signs them with (Config.JWTSecret), and the
+usernames allowed to review withdrawals (Config.Admins). There's no default key, an empty one is refused.
+*/
+func SetAuth(secret []byte, admin_names []string) error {
+	if len(secret) == 0 {
+		return models.ErrNoJWTSecret
+	}
+	jwtSecret = secret
+	admins = make(map[string]bool)
+	for _, admin := range admin_names {
+		admins[admin] = true
+	}
+	return nil
+}
+
+type usernameKey struct{}
+
+// NOTE: authenticate reads "authorization: Bearer <token>" from the call metadata and returns the token's username claim.
+func authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	if len(jwtSecret) == 0 {
+		return "", status.Error(codes.Unavailable, "authentication isn't configured")
+	}
+	raw := md.Get("authorization")[0]
+	if len(raw) < 7 || !strings.EqualFold(raw[:7], "bearer ") {
+		return "", status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	token, err := jwt.Parse(strings.TrimSpace(raw[7:]), func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "invalid token claims")
+	}
+	username, _ := claims["username"].(string)
+	if username == "" {
+		return "", status.Error(codes.Unauthenticated, "token has no username")
+	}
+	return username, nil
+}
+
+func unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	username, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, usernameKey{}, username), req)
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context { return s.ctx }
+
+func streamAuthInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	username, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	ctx := context.WithValue(stream.Context(), usernameKey{}, username)
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+/*
+callerOwner returns the authenticated username of the call. requested is the owner_username sent in the request,
+it may be left empty and is refused when it names someone else.
+*/
+func callerOwner(ctx context.Context, requested string) (string, error) {
+	username, ok := ctx.Value(usernameKey{}).(string)
+	if !ok || username == "" {
+		return "", status.Error(codes.Unauthenticated, "unauthenticated call")
+	}
+	if requested != "" && requested != username {
+		return "", status.Errorf(codes.PermissionDenied, "%s can't act for %s", username, requested)
+	}
+	return username, nil
+}
+
+// NOTE: callerAdmin returns the authenticated username of the call when it's an admin's.
+func callerAdmin(ctx context.Context) (string, error) {
+	username, err := callerOwner(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	if !admins[username] {
+		return "", status.Errorf(codes.PermissionDenied, "%s is not an admin", username)
+	}
+	return username, nil
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+)
+
+type balanceServer struct {
+	pb.UnimplementedBalanceServiceServer
+}
+
+func (s *balanceServer) GetBalances(ctx context.Context, req *pb.BalancesRequest) (*pb.BalancesReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.BalancesReply{}
+	for _, balance := range markets.Balances().Of(owner) {
+		if req.GetAsset() != "" && balance.Asset != strings.ToUpper(req.GetAsset()) {
+			continue
+		}
+		reply.Balances = append(reply.Balances, balanceToPb(balance))
+	}
+	return reply, nil
+}
+
+func balanceToPb(balance models.Balance) *pb.Balance {
+	return &pb.Balance{
+		Asset:     balance.Asset,
+		Available: balance.Available.String(),
+		Locked:    balance.Locked.String(),
+		Total:     balance.Total().String(),
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	executionsRetained = 1000
+	executionsBuffer   = 1024
+)
+
+type executionServer struct {
+	pb.UnimplementedExecutionServiceServer
+}
+
+func (s *executionServer) SubscribeExecutions(req *pb.ExecutionsRequest, stream pb.ExecutionService_SubscribeExecutionsServer) error {
+	owner, err := callerOwner(stream.Context(), req.GetOwnerUsername())
+	if err != nil {
+		return err
+	}
+	sub, err := executions.Subscribe(owner, req.GetAfterSequence())
+	if errors.Is(err, models.ErrExecutionsGone) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case report, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "fell too far behind, resume after the last sequence received")
+			}
+			if err := stream.Send(executionReportToPb(report)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func executionReportToPb(report models.ExecutionReport) *pb.ExecutionReport {
+	return &pb.ExecutionReport{
+		Sequence:       report.Sequence,
+		OrderId:        uint64(report.OrderID),
+		Symbol:         report.Symbol,
+		ExecType:       pb.ExecType(report.Kind),
+		Status:         pb.OrderStatus(report.Status),
+		Side:           pb.EnumSide(report.Side),
+		Price:          report.Price.String(),
+		Quantity:       report.Quantity.String(),
+		FilledQuantity: report.FilledQuantity.String(),
+		LastPrice:      report.LastPrice.String(),
+		LastQuantity:   report.LastQuantity.String(),
+		Maker:          report.Maker,
+		Fee:            report.Fee.String(),
+		RejectReason:   pb.RejectReason(report.RejectReason),
+		Timestamp:      report.Timestamp,
+		OwnerUsername:  report.OwnerUsername,
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NOTE: storeEntries stores entries and their lines, keyed by entry ID (and position): one stored already is left as it is.
+func storeEntries(tx *gorm.DB, entries []models.LedgerEntry) error {
+	var lines []models.LedgerLine
+	for _, entry := range entries {
+		lines = append(lines, entry.Lines...)
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(&entries).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lines).Error
+}
+
+// NOTE: storedBalances sums the stored ledger lines by owner, account and asset.
+func storedBalances() ([]models.LedgerBalance, error) {
+	var balances []models.LedgerBalance
+	err := db.Model(&models.LedgerLine{}).
+		Select("owner, account, asset, SUM(amount) AS amount").
+		Group("owner, account, asset").
+		Scan(&balances).Error
+	return balances, err
+}
+
+/*
+reconcileLedger refuses to start on books that don't balance or on a stored ledger that doesn't match them, once
+the entries the recovery replayed are stored. It returns what custody holds of each asset.
+*/
+func reconcileLedger(ledger *models.Ledger) (map[string]models.Decimal, error) {
+	in_custody, err := ledger.Reconcile()
+	if err != nil {
+		return nil, fmt.Errorf("ledger doesn't reconcile: %w", err)
+	}
+	if db != nil {
+		if err := writes.flush(); err != nil {
+			return nil, err
+		}
+		stored, err := storedBalances()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the stored ledger: %w", err)
+		}
+		if _, err := ledger.ReconcileStored(stored); err != nil {
+			return nil, fmt.Errorf("ledger doesn't reconcile: %w", err)
+		}
+	}
+	for asset, amount := range in_custody {
+		log.Printf("ledger reconciled: %s %s in custody", amount, asset)
+	}
+	return in_custody, nil
+}
@@ -2,49 +2,144 @@ package server
 
 import (
 	"context"
-	_ "errors"
+	"errors"
 	"fmt"
 	_ "fmt"
 	"log"
 	"net"
 	_ "net/http"
-	"sync"
+	"os"
+	"path/filepath"
 	_ "sync"
-	_ "time"
+	"time"
 
+	"github.com/ParsaAminpour/GoCoin/orderbook/custody"
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
 	"github.com/ParsaAminpour/GoCoin/orderbook/models"
 	_ "github.com/ParsaAminpour/GoCoin/orderbook/models"
 	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
 	_ "github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"github.com/ParsaAminpour/GoCoin/orderbook/snapshot"
 	_ "github.com/fatih/color"
 	_ "github.com/golang-jwt/jwt"
 	_ "github.com/labstack/echo/v4"
 	"google.golang.org/grpc"
 	_ "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	_ "gorm.io/gorm"
 )
 
 var (
-	mu   = sync.Mutex{}
-	once sync.Once
-	db   *gorm.DB
+	db         *gorm.DB
+	markets    = models.NewMarketRegistry()
+	marketData = models.NewMarketDataHub(marketDataBuffer)
+	executions = models.NewExecutionHub(executionsRetained, executionsBuffer)
+	wal        *journal.Writer
+	books      *engine.Engine
 )
 
-func getDB() *gorm.DB {
-	conf := models.Config{}
-	once.Do(func() {
-		conf, err := conf.ExtractDbConfig()
-		if err != nil {
-			log.Fatalf("Failed to extract DB config: %v", err)
+const commandQueueSize = 1024
+
+func loadMarkets(conf *models.Config) error {
+	listed, err := models.LoadMarkets(conf.MarketsFile)
+	if err != nil {
+		return err
+	}
+	return markets.Load(listed)
+}
+
+/*
+recoverBooks loads the newest valid snapshot into the freshly loaded markets and replays the journal written
+after it. The replayed commands are persisted like live ones, what a crash kept from the database is stored now
+and what made it is left as it is (every store is keyed). The execution reports are rebuilt, from the snapshot and
+the replay, so owners resume where they left off, but nobody is subscribed yet to be sent them again. Then it
+opens the journal for the commands to come.
+*/
+func recoverBooks(conf *models.Config) error {
+	if err := os.MkdirAll(filepath.Dir(conf.JournalFile), 0o755); err != nil {
+		return err
+	}
+	snap, ok, skipped, err := snapshot.Latest(conf.SnapshotDir)
+	if err != nil {
+		return err
+	}
+	for _, reason := range skipped {
+		log.Printf("skipping snapshot: %v", reason)
+	}
+	var after uint64
+	if ok {
+		if err := markets.Restore(snap.State); err != nil {
+			return fmt.Errorf("failed to restore %s: %v", snap.Path, err)
 		}
-		_, err = init_db_connection(&conf)
-		if err != nil {
-			log.Fatalf("Failed to initialize DB: %v", err)
+		after = snap.LSN
+		log.Printf("restored snapshot at lsn %d", after)
+	}
+	lsn, err := journal.ReplayAfterFunc(conf.JournalFile, markets, after, persistReplayed)
+	if err != nil {
+		return err
+	}
+	log.Printf("replayed the journal up to lsn %d", lsn)
+	if wal, err = journal.Open(conf.JournalFile); err != nil {
+		return err
+	}
+	if wal.LSN() < after {
+		return fmt.Errorf("journal ends at lsn %d, before the snapshot at lsn %d", wal.LSN(), after)
+	}
+	return nil
+}
+
+// NOTE: persistReplayed stores what a replayed command did besides its orders, which the order listener stores.
+func persistReplayed(rec journal.Record, result journal.Result) {
+	recordTrades(result.Trades)
+	if result.Transfer != nil {
+		persistTransfer(*result.Transfer)
+	}
+}
+
+const snapshotsKept = 3
+
+/*
+runSnapshots writes a snapshot every interval, when commands came in since the last one. The journal is only
+replayed after the newest snapshot, so what the commands before it left for the database must be stored first:
+a snapshot is skipped (and tried again on the next tick) while the write queue can't flush.
+*/
+func runSnapshots(ctx context.Context, dir string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lsn, state, err := books.Snapshot(ctx)
+			if err != nil {
+				log.Printf("failed to take a snapshot: %v", err)
+				continue
+			}
+			if lsn == last {
+				continue
+			}
+			if err := writes.flush(); err != nil {
+				log.Printf("not writing a snapshot at lsn %d: %v", lsn, err)
+				continue
+			}
+			path, err := snapshot.Write(dir, lsn, state)
+			if err != nil {
+				log.Printf("failed to write snapshot: %v", err)
+				continue
+			}
+			last = lsn
+			log.Printf("wrote %s", path)
+			if err := snapshot.Prune(dir, snapshotsKept); err != nil {
+				log.Printf("failed to prune snapshots: %v", err)
+			}
 		}
-	})
-	return db
+	}
 }
 
 func init_db_connection(conf *models.Config) (*gorm.DB, error) {
@@ -64,7 +159,7 @@ func init_db_connection(conf *models.Config) (*gorm.DB, error) {
 	fmt.Println("Connected to DB")
 
 	// AutoMigrate the Order model
-	if err := db.AutoMigrate(&models.Order{}, &models.Orderbook{}); err != nil {
+	if err := db.AutoMigrate(&models.Order{}, &models.Orderbook{}, &models.Trade{}, &models.LedgerEntry{}, &models.LedgerLine{}, &models.Transfer{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
 	fmt.Println("I'm here too...")
@@ -76,25 +171,179 @@ type server struct {
 	pb.OrderInfoServiceServer
 }
 
+// NOTE: GetOrderInfo looks in the live books first, an order that left them (filled, cancelled) is read from the DB.
 func (s *server) GetOrderInfo(ctx context.Context, req *pb.OrderInfoRequest) (*pb.OrderInfoReply, error) {
-	// var order models.Order
-	return &pb.OrderInfoReply{
-		Order: &pb.Order{},
-	}, nil
+	owner, err := callerOwner(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	id := uint(req.GetId())
+
+	order, ok, err := books.FindOrder(ctx, id)
+	if err != nil {
+		return nil, engineError(err)
+	}
+	if !ok {
+		if db == nil {
+			return nil, status.Errorf(codes.NotFound, "order %d not found", id)
+		}
+		database := models.Database{DB: db.WithContext(ctx)}
+		if err := database.GetOrderById(&order, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "order %d not found", id)
+			}
+			return nil, status.Errorf(codes.Internal, "failed to load order %d: %v", id, err)
+		}
+	}
+	if order.OwnerUsername != owner {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d belongs to another user", id)
+	}
+	return &pb.OrderInfoReply{Order: orderToPb(&order)}, nil
 }
 
-func main() {
-	my_db := getDB()
-	fmt.Println("DB initialized:", my_db)
+// NOTE: orderToPb maps an order to its wire form, orders that were never persisted take their
+// created_at/updated_at from the time the book accepted them.
+func orderToPb(order *models.Order) *pb.Order {
+	created_at, updated_at := order.CreatedAt, order.UpdatedAt
+	if created_at.IsZero() {
+		created_at = time.Unix(int64(order.Timestamp), 0)
+	}
+	if updated_at.IsZero() {
+		updated_at = created_at
+	}
+	return &pb.Order{
+		Id:              uint64(order.ID),
+		Price:           order.Price.String(),
+		Quantity:        order.Quantity.String(),
+		Side:            pb.EnumSide(order.Side),
+		Timestamp:       order.Timestamp,
+		OwnerUsername:   order.OwnerUsername,
+		CreatedAt:       created_at.UTC().Format(time.RFC3339),
+		UpdatedAt:       updated_at.UTC().Format(time.RFC3339),
+		TimeInForce:     pb.TimeInForce(order.TimeInForce),
+		ExpiresAt:       order.ExpiresAt,
+		PostOnly:        order.PostOnly,
+		PostOnlyReprice: order.PostOnlyReprice,
+		RejectReason:    pb.RejectReason(order.RejectReason),
+		Symbol:          order.Symbol,
+		Type:            pb.OrderType(order.Type),
+		Status:          pb.OrderStatus(order.Status),
+		FilledQuantity:  order.FilledQuantity.String(),
+		StopPrice:       order.StopPrice.String(),
+		ProtectionPrice: order.ProtectionPrice.String(),
+		MaxSlippageBps:  uint32(order.MaxSlippageBps),
+		DisplayQuantity: order.DisplayQuantity.String(),
+		Triggered:       order.Triggered,
+		Held:            order.Held.String(),
+		MakerFeeBps:     order.MakerFeeBps.String(),
+		TakerFeeBps:     order.TakerFeeBps.String(),
 
-	listener, err := net.Listen("tcp", ":8080")
-	if err != nil {
-		panic(err)
+		SelfTradePrevention: pb.SelfTradePrevention(order.SelfTradePrevention),
 	}
+}
+
+// NOTE: onOrderEvent is the books' order listener, it persists the order (the registry reports it to its owner).
+func onOrderEvent(event models.OrderEvent) {
+	persistOrder(event.Order)
+}
 
-	s := grpc.NewServer()
+// NOTE: persistOrder keeps the orders table in step with the books for history, it's written in the background.
+func persistOrder(order *models.Order) {
+	if db == nil {
+		return
+	}
+	writes.addOrder(order)
+}
+
+// NOTE: NewGRPCServer registers every service behind the auth interceptors, it serves what Attach handed over.
+func NewGRPCServer() *grpc.Server {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryAuthInterceptor),
+		grpc.StreamInterceptor(streamAuthInterceptor),
+	)
 	pb.RegisterOrderInfoServiceServer(s, &server{})
+	pb.RegisterTradingServiceServer(s, &tradingServer{})
+	pb.RegisterMarketDataServiceServer(s, &marketDataServer{})
+	pb.RegisterExecutionServiceServer(s, &executionServer{})
+	pb.RegisterBalanceServiceServer(s, &balanceServer{})
+	pb.RegisterTransferServiceServer(s, &transferServer{})
+	return s
+}
+
+/*
+Attach hands the services the markets, the engine that runs them and the database orders are read back from (nil
+to run without one). Run sets these up as it recovers the books, tests attach books of their own.
+*/
+func Attach(registry *models.MarketRegistry, matching *engine.Engine, database *gorm.DB) {
+	markets, books, db = registry, matching, database
+}
+
+/*
+Run starts the orderbook service and serves gRPC on Config.ListenAddr until ctx is done, then stops gracefully and
+returns nil. It runs without a database when DB_HOST_ORDERBOOK is empty. Anything it can't set up on the way
+(configuration, the database, the books) is returned.
+*/
+func Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conf, err := (&models.Config{}).ExtractDbConfig()
+	if err != nil {
+		return fmt.Errorf("failed to extract config: %w", err)
+	}
+	db = nil
+	if conf.Host != "" {
+		if _, err := init_db_connection(&conf); err != nil {
+			return fmt.Errorf("failed to initialize DB: %w", err)
+		}
+	}
+	markets = models.NewMarketRegistry()
+	if err := loadMarkets(&conf); err != nil {
+		return fmt.Errorf("failed to load markets: %w", err)
+	}
+	markets.SetBalances(models.NewBalances())
+	executions = models.NewExecutionHub(executionsRetained, executionsBuffer)
+	markets.SetExecutions(executions)
+	writes = newWriteQueue()
+	go writes.run(ctx)
+	markets.Balances().Ledger().SetEntryListener(writes.addEntry)
+	markets.SetOrderListener(onOrderEvent)
+	if err := recoverBooks(&conf); err != nil {
+		return fmt.Errorf("failed to recover the books: %w", err)
+	}
+	defer wal.Close()
+	in_custody, err := reconcileLedger(markets.Balances().Ledger())
+	if err != nil {
+		return err
+	}
+	custodian = custody.NewFake(in_custody) // NOTE: funded from the ledger, it has no wallets of its own to reconcile
+	if err := SetAuth([]byte(conf.JWTSecret), conf.Admins); err != nil {
+		return fmt.Errorf("refusing to start: %w", err)
+	}
+	markets.SetApprovers(conf.Admins)
+	for _, m := range markets.Markets() {
+		m.Book.TrackLevelChanges()
+	}
+	books = engine.New(markets, wal, commandQueueSize, afterCommand)
+	defer books.Close()
+	go runSnapshots(ctx, conf.SnapshotDir, conf.SnapshotInterval)
+	go books.RunExpiry(ctx, conf.ExpiryInterval)
+	resumeTransfers()
+
+	listener, err := net.Listen("tcp", conf.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", conf.ListenAddr, err)
+	}
+	s := NewGRPCServer()
+	go func() {
+		<-ctx.Done()
+		s.GracefulStop()
+	}()
 	if err := s.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+		return fmt.Errorf("failed to serve: %w", err)
 	}
+	return nil
 }
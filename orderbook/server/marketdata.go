@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const marketDataBuffer = 4096
+
+type marketDataServer struct {
+	pb.UnimplementedMarketDataServiceServer
+}
+
+func (s *marketDataServer) SubscribeMarketData(req *pb.MarketDataRequest, stream pb.MarketDataService_SubscribeMarketDataServer) error {
+	var sub *models.MarketDataSubscription
+	err := books.Exec(stream.Context(), req.GetSymbol(), func(m *models.Market) {
+		sub = marketData.Subscribe(m.Book, int(req.GetDepth()))
+	})
+	if err != nil {
+		return engineError(err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "fell too far behind the feed, subscribe again")
+			}
+			if err := stream.Send(marketDataEventToPb(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *marketDataServer) GetRecentTrades(ctx context.Context, req *pb.RecentTradesRequest) (*pb.RecentTradesReply, error) {
+	var trades []models.Trade
+	err := books.Exec(ctx, req.GetSymbol(), func(m *models.Market) {
+		trades = m.Book.RecentTrades(int(req.GetLimit()))
+	})
+	if err != nil {
+		return nil, engineError(err)
+	}
+	reply := &pb.RecentTradesReply{}
+	for _, trade := range trades {
+		reply.Trades = append(reply.Trades, tradeToPb(trade))
+	}
+	return reply, nil
+}
+
+func marketDataEventToPb(event models.MarketDataEvent) *pb.MarketDataEvent {
+	reply := &pb.MarketDataEvent{Sequence: event.Sequence, Symbol: event.Symbol}
+	switch event.Kind {
+	case models.MarketDataSnapshot:
+		snapshot := &pb.DepthSnapshot{}
+		for _, level := range event.Bids {
+			snapshot.Bids = append(snapshot.Bids, priceLevelToPb(level))
+		}
+		for _, level := range event.Asks {
+			snapshot.Asks = append(snapshot.Asks, priceLevelToPb(level))
+		}
+		reply.Event = &pb.MarketDataEvent_Snapshot{Snapshot: snapshot}
+	case models.MarketDataLevel:
+		reply.Event = &pb.MarketDataEvent_Level{Level: &pb.LevelUpdate{
+			Side:  pb.EnumSide(event.Update.Side),
+			Level: priceLevelToPb(event.Update.Level),
+		}}
+	case models.MarketDataTrade:
+		reply.Event = &pb.MarketDataEvent_Trade{Trade: tradeToPb(event.Trade)}
+	}
+	return reply
+}
+
+func priceLevelToPb(level models.DepthLevel) *pb.PriceLevel {
+	return &pb.PriceLevel{Price: level.Price.String(), Quantity: level.Quantity.String(), OrderCount: uint32(level.OrderCount)}
+}
@@ -0,0 +1,406 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
+type tradingServer struct {
+	pb.UnimplementedTradingServiceServer
+}
+
+func (s *tradingServer) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	order, err := orderFromPb(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	order.OwnerUsername = owner
+
+	result, err := submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: order.Symbol, Order: order})
+	if err != nil {
+		return nil, err
+	}
+	order = result.Order
+	return &pb.PlaceOrderReply{Order: orderToPb(order), Fills: ownFills(order.ID, result.Trades)}, nil
+}
+
+func (s *tradingServer) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	order, err := ownedOrder(ctx, req.GetSymbol(), req.GetId(), owner)
+	if err != nil {
+		return nil, err
+	}
+	result, err := submit(ctx, journal.Record{Kind: journal.CancelOrder, Symbol: order.Symbol, OrderID: order.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CancelOrderReply{Order: orderToPb(result.Order)}, nil
+}
+
+func (s *tradingServer) AmendOrder(ctx context.Context, req *pb.AmendOrderRequest) (*pb.AmendOrderReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	price, err := parseAmount("price", req.GetPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	quantity, err := parseAmount("quantity", req.GetQuantity())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	order, err := ownedOrder(ctx, req.GetSymbol(), req.GetId(), owner)
+	if err != nil {
+		return nil, err
+	}
+	result, err := submit(ctx, journal.Record{Kind: journal.AmendOrder, Symbol: order.Symbol, OrderID: order.ID, Price: price, Quantity: quantity})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AmendOrderReply{Order: orderToPb(result.Order), Fills: ownFills(order.ID, result.Trades)}, nil
+}
+
+func (s *tradingServer) ListOpenOrders(ctx context.Context, req *pb.ListOpenOrdersRequest) (*pb.ListOpenOrdersReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	orders, err := books.OpenOrders(ctx, owner, req.GetSymbol())
+	if err != nil {
+		return nil, engineError(err)
+	}
+	reply := &pb.ListOpenOrdersReply{}
+	for i := range orders {
+		reply.Orders = append(reply.Orders, orderToPb(&orders[i]))
+	}
+	return reply, nil
+}
+
+// NOTE: GetOrderHistory pages through the persisted orders of a user by descending ID, the page token being the last ID seen.
+func (s *tradingServer) GetOrderHistory(ctx context.Context, req *pb.OrderHistoryRequest) (*pb.OrderHistoryReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, status.Error(codes.Unavailable, "order history needs the database")
+	}
+	page_size := pageSize(req.GetPageSize())
+
+	query := db.WithContext(ctx).Where("owner_username = ?", owner)
+	if req.GetSymbol() != "" {
+		query = query.Where("symbol = ?", strings.ToUpper(req.GetSymbol()))
+	}
+	if req.GetPageToken() != "" {
+		after, err := strconv.ParseUint(req.GetPageToken(), 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		query = query.Where("id < ?", after)
+	}
+	var orders []models.Order
+	if err := query.Order("id desc").Limit(page_size + 1).Find(&orders).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load order history: %v", err)
+	}
+
+	reply := &pb.OrderHistoryReply{}
+	if len(orders) > page_size {
+		orders = orders[:page_size]
+		reply.NextPageToken = strconv.FormatUint(uint64(orders[page_size-1].ID), 10)
+	}
+	for i := range orders {
+		reply.Orders = append(reply.Orders, orderToPb(&orders[i]))
+	}
+	return reply, nil
+}
+
+/*
+submit is how every command reaches the books: the engine journals it (synced) with the current time on the
+goroutine of its market, then applies it with the book clock pinned to that time, so a replay of the journal
+rebuilds the same books. What the command did is persisted and published by afterCommand.
+*/
+func submit(ctx context.Context, rec journal.Record) (journal.Result, error) {
+	result, err := books.Submit(ctx, rec)
+	if err != nil {
+		return result, engineError(err)
+	}
+	return result, nil
+}
+
+// NOTE: afterCommand runs on the market's goroutine after each command, so the feed sees the book's changes in order.
+func afterCommand(m *models.Market, rec journal.Record, result journal.Result, err error) {
+	if err != nil {
+		return
+	}
+	recordTrades(result.Trades)
+	marketData.Publish(m.Book, result.Trades)
+}
+
+// NOTE: ownedOrder finds an open order by ID in symbol's market (any market when it's empty) and checks it belongs to owner.
+func ownedOrder(ctx context.Context, symbol string, id uint64, owner string) (models.Order, error) {
+	if id == 0 {
+		return models.Order{}, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	var order models.Order
+	var ok bool
+	var err error
+	if symbol != "" {
+		order, ok, err = books.GetOrder(ctx, symbol, uint(id))
+	} else {
+		order, ok, err = books.FindOrder(ctx, uint(id))
+	}
+	if err != nil {
+		return models.Order{}, engineError(err)
+	}
+	if !ok {
+		return models.Order{}, status.Errorf(codes.NotFound, "order %d is not open", id)
+	}
+	if order.OwnerUsername != owner {
+		return models.Order{}, status.Errorf(codes.PermissionDenied, "order %d belongs to another user", id)
+	}
+	return order, nil
+}
+
+// NOTE: engineError maps an error of the engine to its gRPC status, the book's own errors going through bookError.
+func engineError(err error) error {
+	switch {
+	case errors.Is(err, engine.ErrJournal):
+		return status.Error(codes.Internal, err.Error())
+	case errors.Is(err, engine.ErrStopped):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return bookError(err)
+	}
+}
+
+// NOTE: bookError maps an error of the market registry or a book to its gRPC status.
+func bookError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrMarketNotFound), errors.Is(err, models.ErrOrderNotFound), errors.Is(err, models.ErrTransferNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrMarketNotTrading), errors.Is(err, models.ErrInsufficientFunds), errors.Is(err, models.ErrOrderExpired),
+		errors.Is(err, models.ErrTransferStatus), errors.Is(err, models.ErrWithdrawalLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrNotApprover):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// NOTE: ownFills keeps the trades the order took part in, with their fees, dropping those of stops its fills triggered.
+func ownFills(id uint, trades []models.Trade) []*pb.Trade {
+	var fills []*pb.Trade
+	for _, trade := range trades {
+		if trade.TakerOrderID == id || trade.MakerOrderID == id {
+			fill := tradeToPb(trade)
+			fill.MakerFee, fill.TakerFee = trade.MakerFee.String(), trade.TakerFee.String()
+			fills = append(fills, fill)
+		}
+	}
+	return fills
+}
+
+// NOTE: parseAmount reads an optional decimal field, the empty string being zero.
+func parseAmount(name, value string) (models.Decimal, error) {
+	if value == "" {
+		return models.Zero, nil
+	}
+	amount, err := models.ParseDecimal(value)
+	if err != nil {
+		return models.Zero, fmt.Errorf("%s: %v", name, err)
+	}
+	return amount, nil
+}
+
+func orderFromPb(req *pb.PlaceOrderRequest) (*models.Order, error) {
+	order := &models.Order{
+		Symbol:          req.GetSymbol(),
+		Side:            models.OrderSide(req.GetSide()),
+		Type:            models.OrderType(req.GetType()),
+		TimeInForce:     models.TimeInForce(req.GetTimeInForce()),
+		ExpiresAt:       req.GetExpiresAt(),
+		PostOnly:        req.GetPostOnly(),
+		PostOnlyReprice: req.GetPostOnlyReprice(),
+		MaxSlippageBps:  uint(req.GetMaxSlippageBps()),
+		OwnerUsername:   req.GetOwnerUsername(),
+
+		SelfTradePrevention: models.SelfTradePrevention(req.GetSelfTradePrevention()),
+	}
+	if order.ExpiresAt != 0 && order.TimeInForce != models.GTD {
+		return nil, fmt.Errorf("%w, not %s", models.ErrExpiresAtNotGTD, order.TimeInForce)
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *models.Decimal
+	}{
+		{"price", req.GetPrice(), &order.Price},
+		{"quantity", req.GetQuantity(), &order.Quantity},
+		{"stop_price", req.GetStopPrice(), &order.StopPrice},
+		{"protection_price", req.GetProtectionPrice(), &order.ProtectionPrice},
+		{"display_quantity", req.GetDisplayQuantity(), &order.DisplayQuantity},
+	} {
+		amount, err := parseAmount(field.name, field.value)
+		if err != nil {
+			return nil, err
+		}
+		*field.dest = amount
+	}
+	return order, nil
+}
+
+func tradeToPb(trade models.Trade) *pb.Trade {
+	return &pb.Trade{
+		Symbol:        trade.Symbol,
+		MakerOrderId:  uint64(trade.MakerOrderID),
+		TakerOrderId:  uint64(trade.TakerOrderID),
+		Price:         trade.Price.String(),
+		Quantity:      trade.Quantity.String(),
+		AggressorSide: pb.EnumSide(trade.AggressorSide),
+		Timestamp:     trade.Timestamp,
+		Sequence:      trade.Sequence,
+	}
+}
+
+// NOTE: ownTradeToPb is trade as seen by owner, the maker side when owner traded with themselves.
+func ownTradeToPb(trade *models.Trade, owner string) *pb.OwnTrade {
+	own := &pb.OwnTrade{Trade: tradeToPb(*trade)}
+	if trade.MakerOwner == owner {
+		own.OrderId, own.Maker, own.Fee = uint64(trade.MakerOrderID), true, trade.MakerFee.String()
+		own.Side = pb.EnumSide(models.Buy)
+		if trade.AggressorSide == models.Buy {
+			own.Side = pb.EnumSide(models.Sell)
+		}
+	} else {
+		own.OrderId, own.Fee = uint64(trade.TakerOrderID), trade.TakerFee.String()
+		own.Side = pb.EnumSide(trade.AggressorSide)
+	}
+	return own
+}
+
+// NOTE: recordTrades persists the trades of one book operation in the background, see writeQueue.
+func recordTrades(trades []models.Trade) {
+	if db == nil || len(trades) == 0 {
+		return
+	}
+	writes.addTrades(trades)
+}
+
+// NOTE: pageSize applies the default and the maximum of the history RPCs.
+func pageSize(requested uint32) int {
+	if requested == 0 {
+		return defaultHistoryPageSize
+	}
+	if requested > maxHistoryPageSize {
+		return maxHistoryPageSize
+	}
+	return int(requested)
+}
+
+func (s *tradingServer) GetTradeHistory(ctx context.Context, req *pb.TradeHistoryRequest) (*pb.TradeHistoryReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, status.Error(codes.Unavailable, "trade history needs the database")
+	}
+	page_size := pageSize(req.GetPageSize())
+
+	query := db.WithContext(ctx).Where("maker_owner = ? OR taker_owner = ?", owner, owner)
+	if req.GetSymbol() != "" {
+		query = query.Where("symbol = ?", strings.ToUpper(req.GetSymbol()))
+	}
+	if req.GetPageToken() != "" {
+		after, err := strconv.ParseUint(req.GetPageToken(), 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		query = query.Where("id < ?", after)
+	}
+	var trades []models.Trade
+	if err := query.Order("id desc").Limit(page_size + 1).Find(&trades).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load trade history: %v", err)
+	}
+
+	reply := &pb.TradeHistoryReply{}
+	if len(trades) > page_size {
+		trades = trades[:page_size]
+		reply.NextPageToken = strconv.FormatUint(uint64(trades[page_size-1].ID), 10)
+	}
+	for i := range trades {
+		reply.Trades = append(reply.Trades, ownTradeToPb(&trades[i], owner))
+	}
+	return reply, nil
+}
+
+// NOTE: GetFeeRates is the tier the caller's next order in the market gets, read on the market's goroutine.
+func (s *tradingServer) GetFeeRates(ctx context.Context, req *pb.FeeRatesRequest) (*pb.FeeRatesReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	var reply *pb.FeeRatesReply
+	err = books.Exec(ctx, req.GetSymbol(), func(m *models.Market) {
+		tier, volume := m.FeeTier(owner)
+		reply = &pb.FeeRatesReply{
+			Symbol:     m.Symbol,
+			Volume_30D: volume.String(),
+			MakerBps:   tier.MakerBps.String(),
+			TakerBps:   tier.TakerBps.String(),
+		}
+	})
+	if err != nil {
+		return nil, engineError(err)
+	}
+	return reply, nil
+}
+
+func (s *tradingServer) GetSelfTradePrevention(ctx context.Context, req *pb.GetSelfTradePreventionRequest) (*pb.SelfTradePreventionReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	mode := markets.SelfTradePrevention(owner)
+	return &pb.SelfTradePreventionReply{OwnerUsername: owner, Mode: pb.SelfTradePrevention(mode)}, nil
+}
+
+// NOTE: SetSelfTradePrevention is journaled like a transfer, so a replay gives the same orders the same mode.
+func (s *tradingServer) SetSelfTradePrevention(ctx context.Context, req *pb.SetSelfTradePreventionRequest) (*pb.SelfTradePreventionReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	mode := models.SelfTradePrevention(req.GetMode())
+	if _, err := submit(ctx, journal.Record{Kind: journal.SetSelfTradePrevention, Owner: owner, SelfTradePrevention: mode}); err != nil {
+		return nil, err
+	}
+	return &pb.SelfTradePreventionReply{OwnerUsername: owner, Mode: pb.SelfTradePrevention(mode)}, nil
+}
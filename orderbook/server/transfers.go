@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/custody"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NOTE: custodian holds the exchange's funds on chain, Run sets it.
+var custodian custody.Backend
+
+type transferServer struct {
+	pb.UnimplementedTransferServiceServer
+}
+
+func (s *transferServer) RequestDeposit(ctx context.Context, req *pb.DepositRequest) (*pb.TransferReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount("amount", req.GetAmount())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	address, err := custodian.DepositAddress(ctx, owner, req.GetAsset())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "custody has no deposit address: %v", err)
+	}
+	deposit, err := submitTransfer(ctx, journal.Record{Kind: journal.Deposit, Owner: owner, Asset: req.GetAsset(), Amount: amount, Address: address})
+	if err != nil {
+		return nil, err
+	}
+	go settleDeposit(deposit)
+	return &pb.TransferReply{Transfer: transferToPb(deposit)}, nil
+}
+
+func (s *transferServer) RequestWithdrawal(ctx context.Context, req *pb.WithdrawalRequest) (*pb.TransferReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount("amount", req.GetAmount())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	withdrawal, err := submitTransfer(ctx, journal.Record{Kind: journal.Withdraw, Owner: owner, Asset: req.GetAsset(), Amount: amount, Address: req.GetAddress()})
+	if err != nil {
+		return nil, err
+	}
+	if withdrawal.Status == models.TransferConfirmed {
+		go payOut(withdrawal)
+	}
+	return &pb.TransferReply{Transfer: transferToPb(withdrawal)}, nil
+}
+
+func (s *transferServer) GetTransfers(ctx context.Context, req *pb.TransfersRequest) (*pb.TransfersReply, error) {
+	owner, err := callerOwner(ctx, req.GetOwnerUsername())
+	if err != nil {
+		return nil, err
+	}
+	return transfersToPb(markets.Balances().Transfers().List(owner, models.TransferStatus(req.GetStatus()))), nil
+}
+
+func (s *transferServer) GetPendingApprovals(ctx context.Context, req *pb.PendingApprovalsRequest) (*pb.TransfersReply, error) {
+	if _, err := callerAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var pending []models.Transfer
+	for _, transfer := range markets.Balances().Transfers().List("", models.TransferPending) {
+		if transfer.NeedsApproval {
+			pending = append(pending, transfer)
+		}
+	}
+	return transfersToPb(pending), nil
+}
+
+func (s *transferServer) ApproveWithdrawal(ctx context.Context, req *pb.ReviewWithdrawalRequest) (*pb.TransferReply, error) {
+	admin, err := callerAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pendingWithdrawal(req.GetTransferId()); err != nil {
+		return nil, err
+	}
+	withdrawal, err := submitTransfer(ctx, journal.Record{Kind: journal.ConfirmTransfer, TransferID: req.GetTransferId(), Owner: admin})
+	if err != nil {
+		return nil, err
+	}
+	go payOut(withdrawal)
+	return &pb.TransferReply{Transfer: transferToPb(withdrawal)}, nil
+}
+
+func (s *transferServer) RejectWithdrawal(ctx context.Context, req *pb.ReviewWithdrawalRequest) (*pb.TransferReply, error) {
+	admin, err := callerAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pendingWithdrawal(req.GetTransferId()); err != nil {
+		return nil, err
+	}
+	reason := req.GetReason()
+	if reason == "" {
+		reason = "rejected by " + admin
+	}
+	withdrawal, err := submitTransfer(ctx, journal.Record{Kind: journal.RejectTransfer, TransferID: req.GetTransferId(), Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TransferReply{Transfer: transferToPb(withdrawal)}, nil
+}
+
+// NOTE: pendingWithdrawal finds a withdrawal that waits for an admin, the only kind admins review.
+func pendingWithdrawal(id uint64) (models.Transfer, error) {
+	transfer, ok := markets.Balances().Transfers().Get(id)
+	if !ok || transfer.Kind != models.TransferWithdrawal {
+		return models.Transfer{}, status.Errorf(codes.NotFound, "withdrawal %d not found", id)
+	}
+	if transfer.Status != models.TransferPending {
+		return models.Transfer{}, status.Errorf(codes.FailedPrecondition, "withdrawal %d is %s", id, transfer.Status)
+	}
+	return transfer, nil
+}
+
+// NOTE: submitTransfer runs an account command and persists the transfer it moved.
+func submitTransfer(ctx context.Context, rec journal.Record) (models.Transfer, error) {
+	result, err := submit(ctx, rec)
+	if err != nil {
+		return models.Transfer{}, err
+	}
+	persistTransfer(*result.Transfer)
+	return *result.Transfer, nil
+}
+
+func persistTransfer(transfer models.Transfer) {
+	if db == nil {
+		return
+	}
+	if err := db.Save(&transfer).Error; err != nil {
+		log.Printf("failed to persist transfer %d: %v", transfer.ID, err)
+	}
+}
+
+// NOTE: custodyTimeout bounds one call to the custody backend.
+const custodyTimeout = time.Minute
+
+// NOTE: settleDeposit waits for custody to settle deposit and credits it, or rejects it when custody can't.
+func settleDeposit(deposit models.Transfer) {
+	ctx, cancel := context.WithTimeout(context.Background(), custodyTimeout)
+	defer cancel()
+	tx, err := custodian.Receive(ctx, deposit)
+	if err != nil {
+		moveTransfer(journal.Record{Kind: journal.RejectTransfer, TransferID: deposit.ID, Reason: err.Error()})
+		return
+	}
+	if deposit.Status == models.TransferPending {
+		if !moveTransfer(journal.Record{Kind: journal.ConfirmTransfer, TransferID: deposit.ID}) {
+			return
+		}
+	}
+	moveTransfer(journal.Record{Kind: journal.CompleteTransfer, TransferID: deposit.ID, TxID: tx})
+}
+
+// NOTE: payOut has custody send a confirmed withdrawal, a failed one is rejected and its funds go back.
+func payOut(withdrawal models.Transfer) {
+	ctx, cancel := context.WithTimeout(context.Background(), custodyTimeout)
+	defer cancel()
+	tx, err := custodian.Send(ctx, withdrawal)
+	if err != nil {
+		moveTransfer(journal.Record{Kind: journal.RejectTransfer, TransferID: withdrawal.ID, Reason: err.Error()})
+		return
+	}
+	moveTransfer(journal.Record{Kind: journal.CompleteTransfer, TransferID: withdrawal.ID, TxID: tx})
+}
+
+func moveTransfer(rec journal.Record) bool {
+	if _, err := submitTransfer(context.Background(), rec); err != nil {
+		log.Printf("failed to %s transfer %d: %v", rec.Kind, rec.TransferID, err)
+		return false
+	}
+	return true
+}
+
+// NOTE: resumeTransfers picks up what custody was doing when the server stopped, the backend ignores repeats.
+func resumeTransfers() {
+	for _, transfer := range markets.Balances().Transfers().List("", 0) {
+		switch {
+		case transfer.Kind == models.TransferDeposit && (transfer.Status == models.TransferPending || transfer.Status == models.TransferConfirmed):
+			go settleDeposit(transfer)
+		case transfer.Kind == models.TransferWithdrawal && transfer.Status == models.TransferConfirmed:
+			go payOut(transfer)
+		}
+	}
+}
+
+func transferToPb(transfer models.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            transfer.ID,
+		Kind:          pb.TransferKind(transfer.Kind),
+		OwnerUsername: transfer.Owner,
+		Asset:         transfer.Asset,
+		Amount:        transfer.Amount.String(),
+		Address:       transfer.Address,
+		Status:        pb.TransferStatus(transfer.Status),
+		NeedsApproval: transfer.NeedsApproval,
+		ApprovedBy:    transfer.ApprovedBy,
+		TxId:          transfer.TxID,
+		Reason:        transfer.Reason,
+		CreatedAt:     transfer.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     transfer.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func transfersToPb(transfers []models.Transfer) *pb.TransfersReply {
+	reply := &pb.TransfersReply{}
+	for _, transfer := range transfers {
+		reply.Transfers = append(reply.Transfers, transferToPb(transfer))
+	}
+	return reply
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NOTE: writes queues what the books hand over for the database, Run sets it.
+var writes *writeQueue
+
+// NOTE: writeBatch is what's queued for the database: ledger entries, orders as they change and trades.
+type writeBatch struct {
+	entries []models.LedgerEntry
+	orders  []models.Order
+	trades  []models.Trade
+}
+
+func (b writeBatch) empty() bool {
+	return len(b.entries) == 0 && len(b.orders) == 0 && len(b.trades) == 0
+}
+
+/*
+writeQueue persists what the books do. The ledger and the books hand it over on the market goroutines (the ledger
+under its lock), so it's queued here and written by its own goroutine in batches, one transaction each, rather
+than holding up matching on the database. Every store is keyed (entries by ID, orders upserted by ID, trades by
+symbol and sequence), what a replay after a crash hands over again is stored once. The history RPCs read the
+database, so they may trail the books by the batch being written.
+*/
+type writeQueue struct {
+	mu      sync.Mutex
+	pending writeBatch
+	wake    chan struct{}
+	writing sync.Mutex
+}
+
+func newWriteQueue() *writeQueue {
+	return &writeQueue{wake: make(chan struct{}, 1)}
+}
+
+func (q *writeQueue) add(fn func(*writeBatch)) {
+	q.mu.Lock()
+	fn(&q.pending)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// NOTE: addEntry is the ledger's entry listener.
+func (q *writeQueue) addEntry(entry models.LedgerEntry) {
+	q.add(func(b *writeBatch) { b.entries = append(b.entries, entry) })
+}
+
+/*
+addOrder queues a copy of order, the book keeps changing its own. The copy is stamped with the time the book
+accepted the order (as orderToPb reports it), so the upserts that follow don't move created_at.
+*/
+func (q *writeQueue) addOrder(order *models.Order) {
+	copied := *order
+	if copied.CreatedAt.IsZero() {
+		copied.CreatedAt = time.Unix(int64(copied.Timestamp), 0)
+	}
+	q.add(func(b *writeBatch) { b.orders = append(b.orders, copied) })
+}
+
+func (q *writeQueue) addTrades(trades []models.Trade) {
+	q.add(func(b *writeBatch) { b.trades = append(b.trades, trades...) })
+}
+
+// NOTE: writeRetryInterval is how long the queue waits before writing again after the database refused a batch.
+const writeRetryInterval = time.Second
+
+// NOTE: run writes what's added until ctx is done, a batch that fails stays queued and is retried.
+func (q *writeQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+		for {
+			err := q.flush()
+			if err == nil {
+				break
+			}
+			log.Printf("%v, retrying in %v", err, writeRetryInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(writeRetryInterval):
+			}
+		}
+	}
+}
+
+/*
+flush writes what's pending, once it returns nil everything handed over before the call is stored. A batch it
+failed to store is put back at the front of the queue, ahead of what was added since, for the next flush.
+*/
+func (q *writeQueue) flush() error {
+	q.writing.Lock()
+	defer q.writing.Unlock()
+	q.mu.Lock()
+	batch := q.pending
+	q.pending = writeBatch{}
+	q.mu.Unlock()
+	if db == nil || batch.empty() {
+		return nil
+	}
+	if err := storeBatch(batch); err != nil {
+		q.mu.Lock()
+		q.pending = writeBatch{
+			entries: append(batch.entries, q.pending.entries...),
+			orders:  append(batch.orders, q.pending.orders...),
+			trades:  append(batch.trades, q.pending.trades...),
+		}
+		q.mu.Unlock()
+		return fmt.Errorf("failed to persist %d ledger entries, %d order changes and %d trades: %w",
+			len(batch.entries), len(batch.orders), len(batch.trades), err)
+	}
+	return nil
+}
+
+func storeBatch(batch writeBatch) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(batch.entries) > 0 {
+			if err := storeEntries(tx, batch.entries); err != nil {
+				return err
+			}
+		}
+		if orders := latestOrders(batch.orders); len(orders) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&orders).Error; err != nil {
+				return err
+			}
+		}
+		if len(batch.trades) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch.trades).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NOTE: latestOrders keeps the last change of each order, an upsert can't touch the same row twice in one statement.
+func latestOrders(orders []models.Order) []models.Order {
+	last := make(map[uint]int, len(orders))
+	for i, order := range orders {
+		last[order.ID] = i
+	}
+	latest := make([]models.Order, 0, len(last))
+	for i, order := range orders {
+		if last[order.ID] == i {
+			latest = append(latest, order)
+		}
+	}
+	return latest
+}
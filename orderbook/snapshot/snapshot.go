@@ -0,0 +1,184 @@
+/*
+Package snapshot stores point-in-time copies of every market's book so recovery only has to replay the journal
+written after them.
+
+A snapshot is one JSON file per journal position, named snapshot-<lsn>.json, holding a format version, the LSN of
+the last command it includes and the registry state with its CRC-32. Files are written to a temporary name,
+synced and renamed, and a file that fails any check is skipped in favour of the next older one.
+*/
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+)
+
+// NOTE: Version is bumped whenever the state layout changes, snapshots of another version are skipped.
+const Version = 4
+
+var ErrInvalid = errors.New("invalid snapshot")
+
+type envelope struct {
+	Version   int             `json:"version"`
+	LSN       uint64          `json:"lsn"`
+	CreatedAt string          `json:"created_at"` // ISO8601
+	Checksum  uint32          `json:"checksum"`
+	State     json.RawMessage `json:"state"`
+}
+
+type Snapshot struct {
+	Path  string
+	LSN   uint64
+	State models.RegistryState
+}
+
+func fileName(lsn uint64) string {
+	return fmt.Sprintf("snapshot-%020d.json", lsn)
+}
+
+// NOTE: Write stores state, taken right after command lsn was applied, in dir and returns the file's path.
+func Write(dir string, lsn uint64, state models.RegistryState) (string, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("error in encoding snapshot: %v", err)
+	}
+	data, err := json.Marshal(envelope{
+		Version:   Version,
+		LSN:       lsn,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Checksum:  crc32.ChecksumIEEE(raw),
+		State:     raw,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error in encoding snapshot: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fileName(lsn))
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error in writing snapshot: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error in syncing snapshot: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("error in renaming snapshot: %v", err)
+	}
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return path, nil
+}
+
+// NOTE: Read loads and checks one snapshot file.
+func Read(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return Snapshot{}, fmt.Errorf("%s: %w: %v", path, ErrInvalid, err)
+	}
+	if env.Version != Version {
+		return Snapshot{}, fmt.Errorf("%s: %w: version %d, want %d", path, ErrInvalid, env.Version, Version)
+	}
+	if crc32.ChecksumIEEE(env.State) != env.Checksum {
+		return Snapshot{}, fmt.Errorf("%s: %w: checksum mismatch", path, ErrInvalid)
+	}
+	snap := Snapshot{Path: path, LSN: env.LSN}
+	if err := json.Unmarshal(env.State, &snap.State); err != nil {
+		return Snapshot{}, fmt.Errorf("%s: %w: %v", path, ErrInvalid, err)
+	}
+	return snap, nil
+}
+
+// NOTE: list returns the snapshot files of dir, newest (highest LSN) first.
+func list(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	type file struct {
+		path string
+		lsn  uint64
+	}
+	var files []file
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "snapshot-") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		lsn, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, "snapshot-"), ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, file{path: filepath.Join(dir, name), lsn: lsn})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].lsn > files[j].lsn })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+/*
+Latest returns the newest valid snapshot of dir, ok is false when there is none. skipped lists why each newer
+snapshot was passed over, for the caller to log.
+*/
+func Latest(dir string) (snap Snapshot, ok bool, skipped []error, err error) {
+	paths, err := list(dir)
+	if err != nil {
+		return Snapshot{}, false, nil, err
+	}
+	for _, path := range paths {
+		snap, err := Read(path)
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		return snap, true, skipped, nil
+	}
+	return Snapshot{}, false, skipped, nil
+}
+
+// NOTE: Prune deletes all but the newest keep snapshots of dir.
+func Prune(dir string, keep int) error {
+	paths, err := list(dir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(paths); i++ {
+		if err := os.Remove(paths[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
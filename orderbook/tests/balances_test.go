@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFundedRegistry(t *testing.T) (*models.MarketRegistry, *models.Balances) {
+	registry := newTestRegistry(t)
+	balances := models.NewBalances()
+	registry.SetBalances(balances)
+	assert.NoError(t, balances.Deposit("alice", "BTC", dec(10), "test"))
+	assert.NoError(t, balances.Deposit("bob", "USDT", dec(10000), "test"))
+	return registry, balances
+}
+
+func balanceOf(balances *models.Balances, owner, asset string) [2]models.Decimal {
+	balance := balances.Get(owner, asset)
+	return [2]models.Decimal{balance.Available, balance.Locked}
+}
+
+func TestHoldsFollowTheOrderLifecycle(t *testing.T) {
+	registry, balances := newFundedRegistry(t)
+
+	sell := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"}
+	_, err := registry.AddOrder(sell)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(6), dec(4)}, balanceOf(balances, "alice", "BTC"))
+	assert.Equal(t, dec(4), sell.Held)
+
+	// NOTE: bob holds 6 @ 105 and buys 4 @ 100, the 20 saved stays held until the order is done
+	buy := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(105), Quantity: dec(6), OwnerUsername: "bob"}
+	trades, err := registry.AddOrder(buy)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, [2]models.Decimal{dec(9370), dec(230)}, balanceOf(balances, "bob", "USDT"))
+	assert.Equal(t, [2]models.Decimal{dec(4), models.Zero}, balanceOf(balances, "bob", "BTC"))
+	assert.Equal(t, [2]models.Decimal{dec(6), models.Zero}, balanceOf(balances, "alice", "BTC"))
+	assert.Equal(t, [2]models.Decimal{dec(400), models.Zero}, balanceOf(balances, "alice", "USDT"))
+	assert.True(t, sell.Held.IsZero())
+
+	// NOTE: amending to 4 @ 110 needs 220 for the 2 left
+	_, err = registry.AmendOrder("BTC-USDT", buy.ID, dec(110), dec(6))
+	assert.NoError(t, err)
+	assert.Equal(t, dec(220), buy.Held)
+	assert.Equal(t, [2]models.Decimal{dec(9380), dec(220)}, balanceOf(balances, "bob", "USDT"))
+	_, err = registry.AmendOrder("BTC-USDT", buy.ID, dec(111), dec(6))
+	assert.Error(t, err, "off tick")
+	assert.Equal(t, dec(220), buy.Held)
+
+	_, err = registry.CancelOrder("BTC-USDT", buy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(9600), models.Zero}, balanceOf(balances, "bob", "USDT"))
+	assert.True(t, buy.Held.IsZero())
+
+	// NOTE: funds only changed hands
+	for asset, total := range map[string]models.Decimal{"BTC": dec(10), "USDT": dec(10000)} {
+		sum := models.Zero
+		for _, balance := range balances.All() {
+			if balance.Asset == asset {
+				sum = sum.Add(balance.Total())
+			}
+		}
+		assert.Equal(t, total, sum, asset)
+	}
+}
+
+func TestMarketAndStopBuysHoldFunds(t *testing.T) {
+	registry, balances := newFundedRegistry(t)
+	for _, price := range []int64{100, 110} {
+		_, err := registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(price), Quantity: dec(2), OwnerUsername: "alice"})
+		assert.NoError(t, err)
+	}
+
+	// NOTE: a market buy of 6 holds what the 4 on offer cost, gets them and gives the rest back
+	market := &models.Order{Symbol: "BTC-USDT", Type: models.MarketOrder, Side: models.Buy, Quantity: dec(6), OwnerUsername: "bob"}
+	assert.NoError(t, registry.ReserveOrder(withID(registry, market)))
+	registry.Unreserve(market.ID)
+	trades, err := registry.AddOrder(market)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, models.StatusCancelled, market.Status)
+	assert.Equal(t, [2]models.Decimal{dec(9580), models.Zero}, balanceOf(balances, "bob", "USDT"))
+	assert.Equal(t, [2]models.Decimal{dec(4), models.Zero}, balanceOf(balances, "bob", "BTC"))
+
+	stop := &models.Order{Symbol: "BTC-USDT", Type: models.StopMarketOrder, Side: models.Buy, StopPrice: dec(120), Quantity: dec(2), OwnerUsername: "bob"}
+	_, err = registry.AddOrder(stop)
+	assert.Error(t, err, "a stop market buy needs a protection price")
+	assert.Equal(t, [2]models.Decimal{dec(9580), models.Zero}, balanceOf(balances, "bob", "USDT"))
+	stop.ProtectionPrice = dec(125)
+	_, err = registry.AddOrder(stop)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(9330), dec(250)}, balanceOf(balances, "bob", "USDT"))
+}
+
+func withID(registry *models.MarketRegistry, order *models.Order) *models.Order {
+	order.ID = registry.NewOrderID()
+	return order
+}
+
+func TestReservationsRefuseUnfundedOrders(t *testing.T) {
+	registry, _ := newFundedRegistry(t)
+
+	first := withID(registry, &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(6), OwnerUsername: "alice"})
+	assert.NoError(t, registry.ReserveOrder(first))
+	// NOTE: the first order isn't in its book yet, its reservation still counts
+	second := withID(registry, &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(6), OwnerUsername: "alice"})
+	assert.ErrorIs(t, registry.ReserveOrder(second), models.ErrInsufficientFunds)
+	registry.Unreserve(first.ID)
+	assert.NoError(t, registry.ReserveOrder(second))
+	registry.Unreserve(second.ID)
+
+	buy := withID(registry, &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(102), OwnerUsername: "bob"})
+	assert.ErrorIs(t, registry.ReserveOrder(buy), models.ErrInsufficientFunds)
+	carol := withID(registry, &models.Order{Symbol: "ETH-USDT", Side: models.Sell, Price: dec(10), Quantity: dec(1), OwnerUsername: "carol"})
+	assert.ErrorIs(t, registry.ReserveOrder(carol), models.ErrInsufficientFunds, "no balance at all")
+}
+
+func TestEngineBalancesReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live, balances := newTestRegistry(t), models.NewBalances()
+	live.SetBalances(balances)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+
+	deposit := func(owner, asset string, amount int64) error {
+		requested, err := books.Submit(ctx, journal.Record{Kind: journal.Deposit, Owner: owner, Asset: asset, Amount: dec(amount)})
+		if err != nil {
+			return err
+		}
+		for _, kind := range []journal.Kind{journal.ConfirmTransfer, journal.CompleteTransfer} {
+			if _, err := books.Submit(ctx, journal.Record{Kind: kind, TransferID: requested.Transfer.ID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	place := func(symbol string, side models.OrderSide, price, quantity int64, owner string) (journal.Result, error) {
+		order := &models.Order{Symbol: symbol, Side: side, Price: dec(price), Quantity: dec(quantity), OwnerUsername: owner}
+		return books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: symbol, Order: order})
+	}
+	assert.NoError(t, deposit("alice", "BTC", 4))
+	assert.NoError(t, deposit("bob", "usdt", 1000))
+	assert.Error(t, deposit("bob", "DOGE", 1), "unknown asset")
+
+	_, err = place("BTC-USDT", models.Buy, 100, 12, "bob")
+	assert.ErrorIs(t, err, models.ErrInsufficientFunds)
+	sell, err := place("BTC-USDT", models.Sell, 100, 4, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, dec(4), sell.Order.Held)
+	buy, err := place("BTC-USDT", models.Buy, 100, 6, "bob")
+	assert.NoError(t, err)
+	assert.Len(t, buy.Trades, 1)
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.AmendOrder, Symbol: "BTC-USDT", OrderID: buy.Order.ID, Price: dec(400), Quantity: dec(6)})
+	assert.ErrorIs(t, err, models.ErrInsufficientFunds, "2 more at 400 is more than bob has left")
+	_, err = place("ETH-USDT", models.Buy, 100, 4, "bob")
+	assert.NoError(t, err)
+
+	books.Close()
+	assert.Equal(t, []models.Balance{
+		{Owner: "alice", Asset: "USDT", Available: dec(400), Locked: models.Zero},
+		{Owner: "bob", Asset: "BTC", Available: dec(4), Locked: models.Zero},
+		{Owner: "bob", Asset: "USDT", Available: models.Zero, Locked: dec(600)},
+	}, balances.All())
+
+	replayed := newTestRegistry(t)
+	replayed.SetBalances(models.NewBalances())
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+}
+
+func TestAmendRefusedBeforeFundsMove(t *testing.T) {
+	registry, balances := newFundedRegistry(t)
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	m, err := registry.Market("BTC-USDT")
+	assert.NoError(t, err)
+	m.Book.SetClock(func() time.Time { return now })
+
+	iceberg := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(6), DisplayQuantity: dec(2), OwnerUsername: "alice"}
+	_, err = registry.AddOrder(iceberg)
+	assert.NoError(t, err)
+	_, err = registry.AmendOrder("BTC-USDT", iceberg.ID, dec(100), dec(2))
+	assert.Error(t, err, "no longer more than the display quantity")
+
+	gtd := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice",
+		TimeInForce: models.GTD, ExpiresAt: uint32(now.Add(time.Minute).Unix())}
+	_, err = registry.AddOrder(gtd)
+	assert.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, err = registry.AmendOrder("BTC-USDT", gtd.ID, dec(100), dec(4))
+	assert.ErrorIs(t, err, models.ErrOrderExpired)
+
+	assert.Equal(t, dec(6), iceberg.Held)
+	assert.Equal(t, dec(2), gtd.Held)
+	assert.Equal(t, [2]models.Decimal{dec(2), dec(8)}, balanceOf(balances, "alice", "BTC"))
+}
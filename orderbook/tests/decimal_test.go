@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	d, err := models.ParseDecimal("0.0015")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0015", d.String())
+	assert.Equal(t, uint8(4), d.Scale())
+
+	assert.Equal(t, models.MustParseDecimal("1.50"), models.MustParseDecimal("1.5"), "decimals are canonical so == works")
+	assert.Equal(t, models.NewDecimalFromInt(12), models.MustParseDecimal("12.000"))
+	assert.Equal(t, "-12.5", models.MustParseDecimal("-12.5").String())
+	assert.Equal(t, "64250.5", models.MustParseDecimal("64250.500000000000000000").String())
+
+	for _, bad := range []string{"", ".", "1.2.3", "1e5", "abc", "99999999999999999999", "0.0000000000000000001"} {
+		_, err := models.ParseDecimal(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	price := models.MustParseDecimal("64250.5")
+	qty := models.MustParseDecimal("0.0015")
+
+	assert.Equal(t, "0.0025", qty.Add(models.MustParseDecimal("0.001")).String())
+	assert.Equal(t, "0.0005", qty.Sub(models.MustParseDecimal("0.001")).String())
+	assert.Equal(t, 1, price.Cmp(qty))
+	assert.True(t, qty.LessThan(price))
+	assert.True(t, models.MustParseDecimal("0.1").Add(models.MustParseDecimal("0.2")) == models.MustParseDecimal("0.3"))
+
+	notional, err := price.Mul(qty)
+	assert.NoError(t, err)
+	assert.Equal(t, "96.37575", notional.String())
+
+	_, err = models.MustParseDecimal("0.000000001").Mul(models.MustParseDecimal("0.0000000001"))
+	assert.ErrorIs(t, err, models.ErrDecimalInexact)
+	_, err = models.NewDecimalFromInt(1 << 40).Mul(models.NewDecimalFromInt(1 << 40))
+	assert.ErrorIs(t, err, models.ErrDecimalOverflow)
+
+	assert.True(t, models.MustParseDecimal("100.05").IsMultipleOf(models.MustParseDecimal("0.01")))
+	assert.False(t, models.MustParseDecimal("100.005").IsMultipleOf(models.MustParseDecimal("0.01")))
+	assert.True(t, models.Zero.IsMultipleOf(models.MustParseDecimal("0.01")))
+}
+
+func TestDecimalWideAmounts(t *testing.T) {
+	wei := models.MustParseDecimal("0.000000000000000001")
+	sum := models.NewDecimalFromInt(10).Add(wei)
+	assert.Equal(t, "10.000000000000000001", sum.String())
+	assert.Equal(t, models.MustParseDecimal("10.000000000000000001"), sum, "a wide amount is canonical too")
+	assert.Equal(t, models.NewDecimalFromInt(10), sum.Sub(wei))
+	assert.Equal(t, 1, sum.Cmp(models.NewDecimalFromInt(10)))
+	assert.True(t, sum.IsMultipleOf(wei))
+
+	big := models.MustParseDecimal("999999999999999999.999999999999999999")
+	assert.Equal(t, "-999999999999999999.999999999999999999", big.Neg().String())
+	_, err := big.CheckedAdd(wei)
+	assert.ErrorIs(t, err, models.ErrDecimalOverflow, "the sum has 19 whole digits")
+	notional, err := models.MustParseDecimal("3000.5").Mul(models.MustParseDecimal("12.000000000000001"))
+	assert.NoError(t, err)
+	assert.Equal(t, "36006.0000000000030005", notional.String())
+}
+
+func TestDecimalRounding(t *testing.T) {
+	price := models.MustParseDecimal("64250.5")
+	qty := models.MustParseDecimal("0.00007")
+
+	down, err := price.MulRound(qty, 2, models.RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "4.49", down.String())
+	up, err := price.MulRound(qty, 2, models.RoundUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "4.5", up.String())
+	half, err := models.MustParseDecimal("2.345").MulRound(models.NewDecimalFromInt(1), 2, models.RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.35", half.String())
+
+	third, err := models.NewDecimalFromInt(1).QuoRound(models.NewDecimalFromInt(3), 6, models.RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.333333", third.String())
+	_, err = models.NewDecimalFromInt(1).QuoRound(models.Zero, 6, models.RoundDown)
+	assert.Error(t, err)
+
+	assert.Equal(t, "1.50000000", models.MustParseDecimal("1.5").StringFixed(8))
+	assert.Equal(t, "7.00", models.NewDecimalFromInt(7).StringFixed(2))
+}
+
+func TestDecimalEncoding(t *testing.T) {
+	order := models.Order{Price: models.MustParseDecimal("64250.5"), Quantity: models.MustParseDecimal("0.0015")}
+	data, err := json.Marshal(order)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"price":"64250.5"`)
+	assert.Contains(t, string(data), `"quantity":"0.0015"`)
+
+	var decoded models.Order
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"100.25","quantity":2}`), &decoded))
+	assert.Equal(t, models.MustParseDecimal("100.25"), decoded.Price)
+	assert.Equal(t, models.NewDecimalFromInt(2), decoded.Quantity)
+
+	var scanned models.Decimal
+	assert.NoError(t, scanned.Scan([]byte("0.001500000000000000")))
+	assert.Equal(t, models.MustParseDecimal("0.0015"), scanned)
+	assert.NoError(t, scanned.Scan(int64(42)))
+	assert.Equal(t, models.NewDecimalFromInt(42), scanned)
+	assert.Error(t, scanned.Scan(true))
+
+	value, err := models.MustParseDecimal("0.0015").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0015", value)
+}
@@ -0,0 +1,284 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineJournalReplaysToTheSameBooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live := loadConfigMarkets(t)
+	var applied atomic.Int64
+	books := engine.New(live, wal, 8, func(m *models.Market, rec journal.Record, result journal.Result, err error) {
+		applied.Add(1)
+	})
+
+	// NOTE: two markets fed from several goroutines at once, their commands interleave in the journal
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			symbol := []string{"BTC-USDT", "ETH-USDT"}[i%2]
+			for j := 0; j < 25; j++ {
+				side := models.Buy
+				if j%2 == 1 {
+					side = models.Sell
+				}
+				order := &models.Order{Symbol: symbol, Side: side, Price: models.MustParseDecimal(fmt.Sprintf("%d", 100+j%5)), Quantity: models.MustParseDecimal("0.1"), OwnerUsername: fmt.Sprintf("user%d", i)}
+				result, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: symbol, Order: order})
+				assert.NoError(t, err)
+				assert.NotZero(t, result.Order.ID)
+				assert.Zero(t, order.ID, "the caller's order is left alone")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	open, err := books.OpenOrders(ctx, "user0", "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, open)
+	found, ok, err := books.FindOrder(ctx, open[0].ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, open[0].ID, found.ID)
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.CancelOrder, Symbol: found.Symbol, OrderID: found.ID})
+	assert.NoError(t, err)
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: "XRP-USDT", Order: &models.Order{}})
+	assert.ErrorIs(t, err, models.ErrMarketNotFound)
+
+	lsn, state, err := books.Snapshot(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(101), lsn)
+	assert.Equal(t, int64(101), applied.Load())
+	books.Close()
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.CancelOrder, Symbol: "BTC-USDT", OrderID: 1})
+	assert.ErrorIs(t, err, engine.ErrStopped)
+
+	replayed := loadConfigMarkets(t)
+	report, err := journal.Verify(path, replayed)
+	assert.NoError(t, err)
+	assert.Equal(t, 101, report.Commands)
+	assert.Empty(t, report.Mismatches)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+	assert.Equal(t, state.NextOrderID, replayed.State().NextOrderID)
+	assert.Equal(t, replayed.NewOrderID(), state.NextOrderID+1, "replayed order IDs aren't handed out again")
+}
+
+func TestEngineBusyMarketDoesNotStallOthers(t *testing.T) {
+	books := engine.New(newTestRegistry(t), nil, 1, nil)
+	defer books.Close()
+	ctx := context.Background()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	go books.Exec(ctx, "BTC-USDT", func(*models.Market) {
+		close(started)
+		<-release
+	})
+	<-started
+	queued := make(chan error)
+	go func() {
+		_, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"}})
+		queued <- err
+	}()
+
+	// NOTE: ETH-USDT keeps matching while BTC-USDT is stuck
+	eth := &models.Order{Symbol: "ETH-USDT", Side: models.Sell, Price: dec(10), Quantity: dec(1), OwnerUsername: "bob"}
+	result, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: "ETH-USDT", Order: eth})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusNew, result.Order.Status)
+
+	// NOTE: once the order above takes BTC-USDT's queue of one, senders wait for room until their context gives up
+	time.Sleep(20 * time.Millisecond)
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, books.Exec(timeout, "BTC-USDT", func(*models.Market) {}), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, <-queued, "a queued command still runs")
+	var bids []models.DepthLevel
+	assert.NoError(t, books.Exec(ctx, "BTC-USDT", func(m *models.Market) { bids = m.Book.Depth(models.Buy, 0) }))
+	assert.Len(t, bids, 1)
+}
+
+// NOTE: benchRegistry lists n markets with one tick and one lot of 1.
+func TestEngineExpiresOrders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live, balances := newTestRegistry(t), models.NewBalances()
+	live.SetBalances(balances)
+	assert.NoError(t, balances.Deposit("alice", "ETH", dec(5), "test"))
+	expired := make(chan []*models.Order, 1)
+	books := engine.New(live, wal, 8, func(m *models.Market, rec journal.Record, result journal.Result, err error) {
+		if rec.Kind == journal.ExpireOrders && len(result.Expired) > 0 {
+			expired <- result.Expired
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gtd := &models.Order{Symbol: "ETH-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice",
+		TimeInForce: models.GTD, ExpiresAt: uint32(time.Now().Add(time.Second).Unix())}
+	placed, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: gtd.Symbol, Order: gtd})
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(3), dec(2)}, balanceOf(balances, "alice", "ETH"))
+	go books.RunExpiry(ctx, 50*time.Millisecond)
+
+	select {
+	case orders := <-expired:
+		assert.Len(t, orders, 1)
+		assert.Equal(t, placed.Order.ID, orders[0].ID)
+		assert.Equal(t, models.StatusExpired, orders[0].Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the engine didn't expire the GTD order")
+	}
+	cancel()
+	books.Close()
+	assert.Equal(t, [2]models.Decimal{dec(5), models.Zero}, balanceOf(balances, "alice", "ETH"), "the expired order gives its funds back")
+
+	var kinds []journal.Kind
+	_, err = journal.Read(path, func(rec journal.Record) error {
+		if rec.Kind != journal.Outcome {
+			kinds = append(kinds, rec.Kind)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []journal.Kind{journal.NewOrder, journal.ExpireOrders}, kinds, "nothing is journaled while no expiry is due")
+
+	replayed, replayed_balances := newTestRegistry(t), models.NewBalances()
+	replayed.SetBalances(replayed_balances)
+	assert.NoError(t, replayed_balances.Deposit("alice", "ETH", dec(5), "test"))
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+}
+
+func benchRegistry(b *testing.B, n int) (*models.MarketRegistry, []string) {
+	registry := models.NewMarketRegistry()
+	if err := registry.RegisterAsset(models.Asset{Code: "USDT", Scale: 6}); err != nil {
+		b.Fatal(err)
+	}
+	var symbols []string
+	for i := 0; i < n; i++ {
+		code := fmt.Sprintf("C%c", 'A'+i)
+		if err := registry.RegisterAsset(models.Asset{Code: code, Scale: 8}); err != nil {
+			b.Fatal(err)
+		}
+		m, err := registry.Register(models.Market{Symbol: code + "-USDT", BaseAsset: code, QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1)})
+		if err != nil {
+			b.Fatal(err)
+		}
+		symbols = append(symbols, m.Symbol)
+	}
+	return registry, symbols
+}
+
+// NOTE: benchOrder alternates resting sells and buys that take them, so every other command trades.
+func benchOrder(symbol string, i int64) *models.Order {
+	side := models.Sell
+	if i%2 == 1 {
+		side = models.Buy
+	}
+	return &models.Order{Symbol: symbol, Side: side, Price: dec(100 + i%4), Quantity: dec(1), OwnerUsername: "bench"}
+}
+
+// NOTE: benchCommandIO stands in for what the server does after each command (persisting orders and trades).
+const benchCommandIO = 20 * time.Microsecond
+
+/*
+benchEngine submits b.N orders from parallel senders, sender i trading on market i modulo markets. With one
+market every command waits for the ones before it, with more the markets work side by side.
+*/
+func benchEngine(b *testing.B, markets int) {
+	registry, symbols := benchRegistry(b, markets)
+	books := engine.New(registry, nil, 1024, func(*models.Market, journal.Record, journal.Result, error) {
+		time.Sleep(benchCommandIO)
+	})
+	defer books.Close()
+	ctx := context.Background()
+	var senders atomic.Int64
+	b.SetParallelism(4)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		symbol := symbols[int(senders.Add(1))%len(symbols)]
+		var i int64
+		for pb.Next() {
+			i++
+			if _, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: symbol, Order: benchOrder(symbol, i)}); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEngineOneMarket(b *testing.B)   { benchEngine(b, 1) }
+func BenchmarkEngineFourMarkets(b *testing.B) { benchEngine(b, 4) }
+
+// NOTE: BenchmarkGlobalLockFourMarkets is the load of BenchmarkEngineFourMarkets behind one mutex, the way the server used to run.
+func BenchmarkGlobalLockFourMarkets(b *testing.B) {
+	registry, symbols := benchRegistry(b, 4)
+	var mu sync.Mutex
+	var senders atomic.Int64
+	b.SetParallelism(4)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		symbol := symbols[int(senders.Add(1))%len(symbols)]
+		var i int64
+		for pb.Next() {
+			i++
+			mu.Lock()
+			_, err := registry.AddOrder(benchOrder(symbol, i))
+			time.Sleep(benchCommandIO)
+			mu.Unlock()
+			if err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func TestFindOrderOnlyWaitsOnItsMarket(t *testing.T) {
+	books := engine.New(loadConfigMarkets(t), nil, 1, nil)
+	defer books.Close()
+	ctx := context.Background()
+	result, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: models.MustParseDecimal("0.1"), OwnerUsername: "alice"}})
+	assert.NoError(t, err)
+
+	// NOTE: ETH-USDT is busy until release, a lookup that waited on its queue would time out
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	go books.Exec(ctx, "ETH-USDT", func(*models.Market) { close(started); <-release })
+	<-started
+	timeout, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	found, ok, err := books.FindOrder(timeout, result.Order.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "BTC-USDT", found.Symbol)
+	_, ok, err = books.FindOrder(timeout, result.Order.ID+1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	found, ok, err = books.GetOrder(timeout, "BTC-USDT", result.Order.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, result.Order.ID, found.ID)
+	_, _, err = books.GetOrder(timeout, "XRP-USDT", result.Order.ID)
+	assert.ErrorIs(t, err, models.ErrMarketNotFound)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionReportsPerUser(t *testing.T) {
+	ob := models.NewOrderbook()
+	hub := models.NewExecutionHub(100, 16)
+	ob.SetOrderListener(hub.Publish)
+	alice, err := hub.Subscribe("alice", 0)
+	assert.NoError(t, err)
+	defer alice.Close()
+
+	maker := newOrder(models.Sell, 100, 5, "alice")
+	_, _ = ob.AddOrder(maker)
+	_, _ = ob.AddOrder(newOrder(models.Buy, 101, 2, "bob"))
+
+	accepted := <-alice.C
+	assert.Equal(t, uint64(1), accepted.Sequence)
+	assert.Equal(t, models.OrderAccepted, accepted.Kind)
+	fill := <-alice.C
+	assert.Equal(t, uint64(2), fill.Sequence)
+	assert.Equal(t, models.OrderFilled, fill.Kind)
+	assert.Equal(t, models.StatusPartiallyFilled, fill.Status)
+	assert.Equal(t, dec(100), fill.LastPrice)
+	assert.Equal(t, dec(2), fill.LastQuantity)
+	assert.True(t, fill.Maker)
+	assert.Len(t, alice.C, 0, "bob's reports go to bob only")
+}
+
+func TestExecutionReportsResume(t *testing.T) {
+	ob := models.NewOrderbook()
+	hub := models.NewExecutionHub(3, 16)
+	ob.SetOrderListener(hub.Publish)
+	for i := 0; i < 4; i++ {
+		_, _ = ob.AddOrder(newOrder(models.Sell, 100, 1, "alice"))
+	}
+
+	resumed, err := hub.Subscribe("alice", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), (<-resumed.C).Sequence)
+	assert.Equal(t, uint64(4), (<-resumed.C).Sequence)
+	resumed.Close()
+	resumed.Close()
+
+	_, err = hub.Subscribe("alice", 0)
+	assert.NoError(t, err)
+	_, err = hub.Subscribe("alice", 1)
+	assert.NoError(t, err, "reports 2 to 4 are retained")
+	_, err = hub.Subscribe("alice", 5)
+	assert.Error(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, _ = ob.AddOrder(newOrder(models.Sell, 100, 1, "alice"))
+	}
+	_, err = hub.Subscribe("alice", 1)
+	assert.ErrorIs(t, err, models.ErrExecutionsGone)
+}
+
+// NOTE: the server restarts from a snapshot taken after alice's first fill and the journal after it, then she fills again.
+func TestExecutionReportsResumeAcrossARestart(t *testing.T) {
+	dir := t.TempDir()
+	journal_path := filepath.Join(dir, "orderbook.journal")
+	snapshot_dir := filepath.Join(dir, "snapshots")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	order := func(side models.OrderSide, quantity, owner string) *models.Order {
+		return &models.Order{Symbol: "BTC-USDT", Side: side, Price: models.MustParseDecimal("64250"), Quantity: models.MustParseDecimal(quantity), OwnerUsername: owner}
+	}
+
+	live := loadConfigMarkets(t)
+	live.SetExecutions(models.NewExecutionHub(100, 16))
+	wal, err := journal.Open(journal_path)
+	assert.NoError(t, err)
+	commitTo(t, wal, live, at, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Sell, "0.003", "alice")})
+	commitTo(t, wal, live, at.Add(time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Buy, "0.001", "bob")})
+	_, err = snapshot.Write(snapshot_dir, wal.LSN(), live.State())
+	assert.NoError(t, err)
+	commitTo(t, wal, live, at.Add(2*time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Buy, "0.001", "bob")})
+	assert.NoError(t, wal.Close())
+
+	recovered := loadConfigMarkets(t)
+	hub := models.NewExecutionHub(100, 16)
+	recovered.SetExecutions(hub)
+	snap, ok, _, err := snapshot.Latest(snapshot_dir)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, recovered.Restore(snap.State))
+	_, err = journal.ReplayAfter(journal_path, recovered, snap.LSN)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, recovered), "same sequences and retained reports, timestamps included")
+
+	wal, err = journal.Open(journal_path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	commitTo(t, wal, recovered, at.Add(3*time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Buy, "0.001", "bob")})
+
+	// NOTE: alice saw her acceptance and first fill before the restart
+	alice, err := hub.Subscribe("alice", 2)
+	assert.NoError(t, err)
+	defer alice.Close()
+	for i, status := range []models.OrderStatus{models.StatusPartiallyFilled, models.StatusFilled} {
+		report := <-alice.C
+		assert.Equal(t, uint64(3+i), report.Sequence)
+		assert.Equal(t, models.OrderFilled, report.Kind)
+		assert.Equal(t, status, report.Status)
+		assert.Equal(t, models.MustParseDecimal("0.001"), report.LastQuantity)
+		assert.Equal(t, uint32(at.Add(time.Duration(2+i)*time.Second).Unix()), report.Timestamp)
+	}
+	assert.Len(t, alice.C, 0)
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFees = []models.FeeTier{
+	{MinVolume: dec(0), MakerBps: dec(10), TakerBps: dec(20)},
+	{MinVolume: dec(1000), MakerBps: dec(5), TakerBps: dec(15)},
+	{MinVolume: dec(5000), MakerBps: dec(-2), TakerBps: dec(10)},
+}
+
+// NOTE: newFeeRegistry is a BTC-USDT market charging fees, on a clock the test moves.
+func newFeeRegistry(t *testing.T, fees []models.FeeTier) (*models.MarketRegistry, *models.Balances, *time.Time) {
+	registry := models.NewMarketRegistry()
+	assert.NoError(t, registry.RegisterAsset(models.Asset{Code: "BTC", Scale: 8}))
+	assert.NoError(t, registry.RegisterAsset(models.Asset{Code: "USDT", Scale: 6}))
+	market, err := registry.Register(models.Market{Symbol: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1), Fees: fees})
+	assert.NoError(t, err)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	market.Book.SetClock(func() time.Time { return now })
+	balances := models.NewBalances()
+	registry.SetBalances(balances)
+	assert.NoError(t, balances.Deposit("alice", "BTC", dec(100), "test"))
+	assert.NoError(t, balances.Deposit("bob", "USDT", dec(100000), "test"))
+	return registry, balances, &now
+}
+
+func trade(t *testing.T, registry *models.MarketRegistry, maker, taker *models.Order) []models.Trade {
+	_, err := registry.AddOrder(maker)
+	assert.NoError(t, err)
+	trades, err := registry.AddOrder(taker)
+	assert.NoError(t, err)
+	return trades
+}
+
+func TestFeesAreBookedToTheHouse(t *testing.T) {
+	registry, balances, now := newFeeRegistry(t, testFees)
+	market, _ := registry.Market("BTC-USDT")
+	house := models.Account{Kind: models.AccountHouse}
+
+	buy := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(4), OwnerUsername: "bob"}
+	trades := trade(t, registry, &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"}, buy)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, models.MustParseDecimal("0.4"), trades[0].MakerFee, "10 bps of 400")
+	assert.Equal(t, models.MustParseDecimal("0.8"), trades[0].TakerFee, "20 bps of 400")
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("99599.2"), models.Zero}, balanceOf(balances, "bob", "USDT"))
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("399.6"), models.Zero}, balanceOf(balances, "alice", "USDT"))
+	assert.Equal(t, models.MustParseDecimal("1.2"), balances.Ledger().Total(house, "USDT"), "the house is credited both fees")
+
+	tier, volume := market.FeeTier("alice")
+	assert.Equal(t, testFees[0], tier)
+	assert.Equal(t, dec(400), volume)
+	trade(t, registry,
+		&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(7), OwnerUsername: "alice"},
+		&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(7), OwnerUsername: "bob"})
+	tier, volume = market.FeeTier("bob")
+	assert.Equal(t, testFees[1], tier, "1100 traded moves bob up a tier")
+	assert.Equal(t, dec(1100), volume)
+
+	next := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(90), Quantity: dec(1), OwnerUsername: "bob"}
+	_, err := registry.AddOrder(next)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(5), dec(15)}, [2]models.Decimal{next.MakerFeeBps, next.TakerFeeBps})
+	assert.Equal(t, models.MustParseDecimal("90.135"), next.Held, "a buy holds its cost and the fee at its higher rate")
+
+	*now = now.AddDate(0, 0, 30)
+	tier, volume = market.FeeTier("bob")
+	assert.Equal(t, testFees[0], tier, "volume older than 30 days no longer counts")
+	assert.True(t, volume.IsZero())
+	assert.Equal(t, [2]models.Decimal{dec(5), dec(15)}, [2]models.Decimal{next.MakerFeeBps, next.TakerFeeBps}, "an open order keeps the rates it was placed at")
+
+	held, err := balances.Ledger().Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"BTC": dec(100), "USDT": dec(100000)}, held)
+}
+
+func TestMakerRebates(t *testing.T) {
+	registry, balances, _ := newFeeRegistry(t, []models.FeeTier{{MinVolume: dec(0), MakerBps: dec(-2), TakerBps: dec(10)}})
+	house := models.Account{Kind: models.AccountHouse}
+
+	trades := trade(t, registry,
+		&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(4), OwnerUsername: "bob"},
+		&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"})
+	assert.Len(t, trades, 1)
+	assert.Equal(t, models.MustParseDecimal("-0.08"), trades[0].MakerFee)
+	assert.Equal(t, models.MustParseDecimal("0.4"), trades[0].TakerFee)
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("99600.08"), models.Zero}, balanceOf(balances, "bob", "USDT"), "the rebate is paid and the unused fee hold released")
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("399.6"), models.Zero}, balanceOf(balances, "alice", "USDT"))
+	assert.Equal(t, models.MustParseDecimal("0.32"), balances.Ledger().Total(house, "USDT"))
+}
+
+func TestFeeTierValidation(t *testing.T) {
+	registry := newTestRegistry(t)
+	for name, fees := range map[string][]models.FeeTier{
+		"first tier above zero":     {{MinVolume: dec(10), TakerBps: dec(10)}},
+		"tiers out of order":        {{MinVolume: dec(0), TakerBps: dec(10)}, {MinVolume: dec(0), TakerBps: dec(5)}},
+		"negative taker fee":        {{MinVolume: dec(0), TakerBps: dec(-1)}},
+		"rebate over the taker fee": {{MinVolume: dec(0), MakerBps: dec(5), TakerBps: dec(20)}, {MinVolume: dec(100), MakerBps: dec(-11), TakerBps: dec(10)}},
+		"rate over 10%":             {{MinVolume: dec(0), TakerBps: dec(1001)}},
+	} {
+		_, err := registry.Register(models.Market{Symbol: "ETH-BTC", BaseAsset: "ETH", QuoteAsset: "BTC", TickSize: dec(1), LotSize: dec(1), Fees: fees})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestEngineFeesReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live, balances := loadConfigMarkets(t), models.NewBalances()
+	live.SetBalances(balances)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+
+	for _, deposit := range []journal.Record{
+		{Kind: journal.Deposit, Owner: "alice", Asset: "BTC", Amount: dec(50)},
+		{Kind: journal.Deposit, Owner: "bob", Asset: "USDT", Amount: dec(2000000)},
+	} {
+		requested, err := books.Submit(ctx, deposit)
+		assert.NoError(t, err)
+		for _, kind := range []journal.Kind{journal.ConfirmTransfer, journal.CompleteTransfer} {
+			_, err := books.Submit(ctx, journal.Record{Kind: kind, TransferID: requested.Transfer.ID})
+			assert.NoError(t, err)
+		}
+	}
+	for _, quantity := range []int64{30, 2} { // NOTE: 1.2M traded puts both in the second tier for the next trade
+		for _, order := range []*models.Order{
+			{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(40000), Quantity: dec(quantity), OwnerUsername: "alice"},
+			{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(40000), Quantity: dec(quantity), OwnerUsername: "bob"},
+		} {
+			_, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: order.Symbol, Order: order})
+			assert.NoError(t, err)
+		}
+	}
+	books.Close()
+	house := models.Account{Kind: models.AccountHouse}
+	assert.Equal(t, dec(3760), balances.Ledger().Total(house, "USDT"), "30 bps of 1.2M, then 20 bps of 80k")
+
+	replayed := loadConfigMarkets(t)
+	replayed.SetBalances(models.NewBalances())
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+}
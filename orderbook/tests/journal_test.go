@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadConfigMarkets(t *testing.T) *models.MarketRegistry {
+	conf, err := models.LoadMarkets("../config/markets.json")
+	assert.NoError(t, err)
+	registry := models.NewMarketRegistry()
+	assert.NoError(t, registry.Load(conf))
+	return registry
+}
+
+// NOTE: commitTo journals and applies one command like the server does, at a given time.
+func commitTo(t *testing.T, wal *journal.Writer, registry *models.MarketRegistry, at time.Time, rec journal.Record) journal.Result {
+	rec.Time = at.UnixNano()
+	assert.NoError(t, wal.Append(&rec))
+	result, _ := journal.Apply(registry, rec)
+	assert.NoError(t, wal.AppendOutcome(rec.LSN, result.Trades))
+	return result
+}
+
+func writeTestJournal(t *testing.T, path string) *models.MarketRegistry {
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live := loadConfigMarkets(t)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	order := func(side models.OrderSide, price, quantity, owner string) *models.Order {
+		return &models.Order{Symbol: "BTC-USDT", Side: side, Price: models.MustParseDecimal(price), Quantity: models.MustParseDecimal(quantity), OwnerUsername: owner}
+	}
+	maker := commitTo(t, wal, live, start, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Sell, "64250.5", "0.002", "alice")})
+	commitTo(t, wal, live, start.Add(time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Sell, "64260", "0.003", "alice")})
+	commitTo(t, wal, live, start.Add(2*time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Buy, "64255", "0.0015", "bob")})
+	commitTo(t, wal, live, start.Add(3*time.Second), journal.Record{Kind: journal.AmendOrder, Symbol: "BTC-USDT", OrderID: maker.Order.ID, Price: models.MustParseDecimal("64245"), Quantity: models.MustParseDecimal("0.002")})
+	commitTo(t, wal, live, start.Add(4*time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "XRP-USDT", Order: order(models.Buy, "1", "1", "bob")})
+	commitTo(t, wal, live, start.Add(5*time.Second), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: order(models.Buy, "64260", "0.003", "carol")})
+	commitTo(t, wal, live, start.Add(6*time.Second), journal.Record{Kind: journal.CancelOrder, Symbol: "BTC-USDT", OrderID: maker.Order.ID})
+	assert.Equal(t, uint64(7), wal.LSN())
+	return live
+}
+
+func TestJournalReplayRebuildsTheBooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	live := writeTestJournal(t, path)
+
+	replayed := loadConfigMarkets(t)
+	lsn, err := journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), lsn)
+	for _, side := range []models.OrderSide{models.Buy, models.Sell} {
+		want, _ := live.Depth("BTC-USDT", side, 0)
+		got, _ := replayed.Depth("BTC-USDT", side, 0)
+		assert.Equal(t, want, got)
+	}
+	want, _ := live.RecentTrades("BTC-USDT", 0)
+	got, _ := replayed.RecentTrades("BTC-USDT", 0)
+	assert.Equal(t, want, got, "same trades, timestamps and sequences included")
+
+	report, err := journal.Verify(path, loadConfigMarkets(t))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, report.Commands)
+	assert.Equal(t, 7, report.Checked)
+	assert.Empty(t, report.Mismatches)
+}
+
+func TestReplayAfterFuncReportsTheTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	live := writeTestJournal(t, path)
+
+	// NOTE: the server stores what the replayed commands did, the rejected ones (lsn 5 names no market, lsn 7 cancels a filled order) did nothing
+	var replayed []uint64
+	var trades []models.Trade
+	lsn, err := journal.ReplayAfterFunc(path, loadConfigMarkets(t), 0, func(rec journal.Record, result journal.Result) {
+		replayed = append(replayed, rec.LSN)
+		trades = append(trades, result.Trades...)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), lsn)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 6}, replayed)
+	want, _ := live.RecentTrades("BTC-USDT", 0)
+	assert.NotEmpty(t, trades)
+	assert.ElementsMatch(t, want, trades, "every trade of the tail, keyed by symbol and sequence")
+}
+
+func TestJournalTornTailAndCorruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	writeTestJournal(t, path)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	// a crash in the middle of a write leaves half a record behind, it's dropped on open
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, _ = file.Write([]byte{0, 0, 1, 0, 9, 9})
+	file.Close()
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), wal.LSN())
+	wal.Close()
+	truncated, _ := os.Stat(path)
+	assert.Equal(t, info.Size(), truncated.Size())
+
+	// a flipped byte in an earlier record is corruption
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	data[20] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+	_, err = journal.Replay(path, loadConfigMarkets(t))
+	assert.ErrorIs(t, err, journal.ErrCorrupt)
+	_, err = journal.Open(path)
+	assert.ErrorIs(t, err, journal.ErrCorrupt)
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerRefusesUnbalancedEntries(t *testing.T) {
+	ledger := models.NewLedger()
+	alice := models.Account{Owner: "alice", Kind: models.AccountAvailable}
+	custody := models.Account{Kind: models.AccountCustody}
+
+	_, err := ledger.Post(models.EntryDeposit, "deposit 1",
+		models.LedgerLine{Owner: alice.Owner, Account: alice.Kind, Asset: "BTC", Amount: dec(2)},
+		models.LedgerLine{Account: custody.Kind, Asset: "BTC", Amount: dec(-1)})
+	assert.ErrorIs(t, err, models.ErrUnbalanced)
+	assert.True(t, ledger.Total(alice, "BTC").IsZero(), "nothing of a refused entry is booked")
+
+	entry, err := ledger.Post(models.EntryDeposit, "deposit 1",
+		models.LedgerLine{Owner: alice.Owner, Account: alice.Kind, Asset: "btc", Amount: dec(2)},
+		models.LedgerLine{Account: custody.Kind, Asset: "BTC", Amount: dec(-2)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), entry.ID)
+	assert.Equal(t, dec(2), ledger.Total(alice, "BTC"))
+	assert.Equal(t, dec(-2), ledger.Total(custody, "BTC"))
+
+	held, err := ledger.Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"BTC": dec(2)}, held)
+	assert.ErrorIs(t, ledger.Restore(models.LedgerState{Balances: []models.LedgerBalance{{Owner: "alice", Account: models.AccountAvailable, Asset: "BTC", Amount: dec(1)}}}), models.ErrUnbalanced)
+	assert.Equal(t, dec(2), ledger.Total(alice, "BTC"), "a state that doesn't balance isn't restored")
+}
+
+func TestEveryBalanceMovementIsABalancedEntry(t *testing.T) {
+	registry, balances := newTestRegistry(t), models.NewBalances()
+	registry.SetBalances(balances)
+	var entries []models.LedgerEntry
+	balances.Ledger().SetEntryListener(func(entry models.LedgerEntry) { entries = append(entries, entry) })
+	assert.NoError(t, balances.Deposit("alice", "BTC", dec(10), "test"))
+	assert.NoError(t, balances.Deposit("bob", "USDT", dec(10000), "test"))
+
+	_, err := registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"})
+	assert.NoError(t, err)
+	buy := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(105), Quantity: dec(6), OwnerUsername: "bob"}
+	_, err = registry.AddOrder(buy)
+	assert.NoError(t, err)
+	_, err = registry.CancelOrder("BTC-USDT", buy.ID)
+	assert.NoError(t, err)
+
+	var kinds []models.EntryKind
+	for _, entry := range entries {
+		kinds = append(kinds, entry.Kind)
+		sums := map[string]models.Decimal{}
+		for _, line := range entry.Lines {
+			sums[line.Asset] = sums[line.Asset].Add(line.Amount)
+		}
+		for asset, sum := range sums {
+			assert.True(t, sum.IsZero(), "entry %d (%s) is off by %s %s", entry.ID, entry.Kind, sum, asset)
+		}
+	}
+	assert.Equal(t, []models.EntryKind{
+		models.EntryDeposit, models.EntryDeposit,
+		models.EntryHold, models.EntryHold, // NOTE: alice's 4 BTC, bob's 630 USDT
+		models.EntryTrade,
+		models.EntryRelease, // NOTE: bob's cancel gives back the 230 left
+	}, kinds)
+
+	held, err := balances.Ledger().Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"BTC": dec(10), "USDT": dec(10000)}, held)
+	assert.Equal(t, models.Balance{Owner: "alice", Asset: "USDT", Available: dec(400), Locked: models.Zero}, balances.Get("alice", "USDT"))
+	assert.Equal(t, models.Balance{Owner: "bob", Asset: "BTC", Available: dec(4), Locked: models.Zero}, balances.Get("bob", "BTC"))
+
+	restored := models.NewLedger()
+	assert.NoError(t, restored.Restore(balances.Ledger().State()))
+	assert.Equal(t, balances.Ledger().Balances(), restored.Balances())
+	entry, err := restored.Post(models.EntryDeposit, "test",
+		models.LedgerLine{Owner: "carol", Account: models.AccountAvailable, Asset: "BTC", Amount: dec(1)},
+		models.LedgerLine{Account: models.AccountCustody, Asset: "BTC", Amount: dec(-1)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(entries)+1), entry.ID, "entry IDs carry on after a restore")
+}
+
+func TestLedgerEntryIDsSurviveReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	collect := func(registry *models.MarketRegistry) map[uint64]string {
+		var mu sync.Mutex
+		entries := make(map[uint64]string)
+		balances := models.NewBalances()
+		registry.SetBalances(balances)
+		balances.Ledger().SetEntryListener(func(entry models.LedgerEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			_, dup := entries[entry.ID]
+			assert.False(t, dup, "entry %d posted twice", entry.ID)
+			entries[entry.ID] = fmt.Sprintf("%s %s %v", entry.Kind, entry.Reference, entry.Lines)
+		})
+		return entries
+	}
+	live := newTestRegistry(t)
+	live_entries := collect(live)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+	for _, deposit := range []journal.Record{
+		{Kind: journal.Deposit, Owner: "alice", Asset: "BTC", Amount: dec(100)},
+		{Kind: journal.Deposit, Owner: "alice", Asset: "ETH", Amount: dec(100)},
+		{Kind: journal.Deposit, Owner: "bob", Asset: "USDT", Amount: dec(100000)},
+	} {
+		requested, err := books.Submit(ctx, deposit)
+		assert.NoError(t, err)
+		for _, kind := range []journal.Kind{journal.ConfirmTransfer, journal.CompleteTransfer} {
+			_, err := books.Submit(ctx, journal.Record{Kind: kind, TransferID: requested.Transfer.ID})
+			assert.NoError(t, err)
+		}
+	}
+
+	// NOTE: both markets trade at once, so their entries are posted interleaved
+	var wg sync.WaitGroup
+	for _, symbol := range []string{"BTC-USDT", "ETH-USDT"} {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				for _, order := range []*models.Order{
+					{Symbol: symbol, Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"},
+					{Symbol: symbol, Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"},
+				} {
+					_, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: symbol, Order: order})
+					assert.NoError(t, err)
+				}
+			}
+		}(symbol)
+	}
+	wg.Wait()
+	books.Close()
+
+	replayed := newTestRegistry(t)
+	replayed_entries := collect(replayed)
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, live_entries)
+	assert.Equal(t, live_entries, replayed_entries)
+}
+
+// NOTE: storedLines sums entries' lines by owner, account and asset, as the server reads them back from the database.
+func storedLines(entries []models.LedgerEntry) []models.LedgerBalance {
+	totals := map[[3]string]models.LedgerBalance{}
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			key := [3]string{line.Owner, line.Account.String(), line.Asset}
+			total := totals[key]
+			total.Owner, total.Account, total.Asset, total.Amount = line.Owner, line.Account, line.Asset, total.Amount.Add(line.Amount)
+			totals[key] = total
+		}
+	}
+	var balances []models.LedgerBalance
+	for _, total := range totals {
+		balances = append(balances, total)
+	}
+	return balances
+}
+
+func TestReconcileAgainstStoredLines(t *testing.T) {
+	registry, balances := newTestRegistry(t), models.NewBalances()
+	registry.SetBalances(balances)
+	var entries []models.LedgerEntry
+	balances.Ledger().SetEntryListener(func(entry models.LedgerEntry) { entries = append(entries, entry) })
+	assert.NoError(t, balances.Deposit("alice", "BTC", dec(10), "test"))
+	assert.NoError(t, balances.Deposit("bob", "USDT", dec(10000), "test"))
+	_, err := registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"})
+	assert.NoError(t, err)
+	_, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"})
+	assert.NoError(t, err)
+	ledger := balances.Ledger()
+
+	in_custody, err := ledger.ReconcileStored(storedLines(entries))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"BTC": dec(10), "USDT": dec(10000)}, in_custody)
+
+	// NOTE: a hold lost on the way to the database still balances, but not against the books
+	_, err = ledger.ReconcileStored(storedLines(append(entries[:2:2], entries[3:]...)))
+	assert.ErrorIs(t, err, models.ErrLedgerMismatch)
+	_, err = ledger.ReconcileStored(storedLines(entries[:1]))
+	assert.ErrorIs(t, err, models.ErrLedgerMismatch)
+	unbalanced := append(storedLines(entries), models.LedgerBalance{Owner: "carol", Account: models.AccountAvailable, Asset: "BTC", Amount: dec(1)})
+	_, err = ledger.ReconcileStored(unbalanced)
+	assert.ErrorIs(t, err, models.ErrUnbalanced)
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry(t *testing.T) *models.MarketRegistry {
+	registry := models.NewMarketRegistry()
+	assert.NoError(t, registry.RegisterAsset(models.Asset{Code: "BTC", Scale: 8}))
+	assert.NoError(t, registry.RegisterAsset(models.Asset{Code: "ETH", Scale: 18}))
+	assert.NoError(t, registry.RegisterAsset(models.Asset{Code: "USDT", Scale: 6}))
+	_, err := registry.Register(models.Market{Symbol: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: dec(5), LotSize: dec(2), MinNotional: dec(100)})
+	assert.NoError(t, err)
+	_, err = registry.Register(models.Market{Symbol: "ETH-USDT", BaseAsset: "ETH", QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1)})
+	assert.NoError(t, err)
+	return registry
+}
+
+func TestRegisterMarkets(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	_, err := registry.Register(models.Market{Symbol: "BTC-USDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1)})
+	assert.Error(t, err, "symbols are unique")
+	_, err = registry.Register(models.Market{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1)})
+	assert.Error(t, err)
+	_, err = registry.Register(models.Market{Symbol: "SOL-USDT", BaseAsset: "SOL", QuoteAsset: "USDT"})
+	assert.Error(t, err, "tick and lot size are required")
+	_, err = registry.Register(models.Market{Symbol: "BTC-ETH", BaseAsset: "BTC", QuoteAsset: "ETH", TickSize: dec(1), LotSize: models.MustParseDecimal("0.000000001")})
+	assert.Error(t, err, "lot size finer than the base asset's scale")
+	_, err = registry.Register(models.Market{Symbol: "SOL-USDT", BaseAsset: "SOL", QuoteAsset: "USDT", TickSize: dec(1), LotSize: dec(1)})
+	assert.Error(t, err, "assets must be registered first")
+
+	listed := registry.Markets()
+	assert.Len(t, listed, 2)
+	assert.Equal(t, "BTC-USDT", listed[0].Symbol)
+
+	m, err := registry.Market("eth-usdt")
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH", m.BaseAsset)
+	_, err = registry.Market("DOGE-USDT")
+	assert.Error(t, err)
+}
+
+func TestMarketsHaveSeparateBooks(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	btc := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"}
+	eth := &models.Order{Symbol: "ETH-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"}
+	_, err := registry.AddOrder(btc)
+	assert.NoError(t, err)
+	trades, err := registry.AddOrder(eth)
+	assert.NoError(t, err)
+	assert.Empty(t, trades, "orders in different markets never match")
+	assert.NotEqual(t, btc.ID, eth.ID, "order IDs are unique across markets")
+
+	trades, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "carol"})
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, "BTC-USDT", trades[0].Symbol)
+
+	_, err = registry.GetOrder("BTC-USDT", eth.ID)
+	assert.Error(t, err)
+	order, err := registry.GetOrder("ETH-USDT", eth.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, eth, order)
+
+	depth, err := registry.Depth("ETH-USDT", models.Buy, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DepthLevel{{Price: dec(100), Quantity: dec(2), OrderCount: 1}}, depth)
+}
+
+func TestMarketTradingRules(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	_, err := registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(101), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.Error(t, err, "price off the tick")
+	_, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(3), OwnerUsername: "alice"})
+	assert.Error(t, err, "quantity off the lot")
+	_, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(40), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.Error(t, err, "below min notional")
+	_, err = registry.AddOrder(&models.Order{Symbol: "XRP-USDT", Side: models.Buy, Price: dec(40), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.Error(t, err, "unknown market")
+
+	resting := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"}
+	_, err = registry.AddOrder(resting)
+	assert.NoError(t, err)
+	_, err = registry.AmendOrder("BTC-USDT", resting.ID, dec(102), dec(2))
+	assert.Error(t, err)
+
+	assert.NoError(t, registry.SetStatus("BTC-USDT", models.MarketHalted))
+	_, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.Error(t, err, "halted markets take no new orders")
+	_, err = registry.CancelOrder("BTC-USDT", resting.ID)
+	assert.NoError(t, err, "but cancels still go through")
+}
+
+func TestLoadMarkets(t *testing.T) {
+	conf, err := models.LoadMarkets("../config/markets.json")
+	assert.NoError(t, err)
+	registry := models.NewMarketRegistry()
+	assert.NoError(t, registry.Load(conf))
+	assert.NotEmpty(t, registry.Markets())
+
+	m, err := registry.Market("BTC-USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(8), m.Base.Scale)
+	assert.Equal(t, models.MustParseDecimal("0.01"), m.TickSize)
+}
+
+func TestFractionalMarketRules(t *testing.T) {
+	conf, err := models.LoadMarkets("../config/markets.json")
+	assert.NoError(t, err)
+	registry := models.NewMarketRegistry()
+	assert.NoError(t, registry.Load(conf))
+
+	order := func(side models.OrderSide, price, quantity string) *models.Order {
+		return &models.Order{Symbol: "BTC-USDT", Side: side, Price: models.MustParseDecimal(price), Quantity: models.MustParseDecimal(quantity), OwnerUsername: "alice"}
+	}
+	_, err = registry.AddOrder(order(models.Buy, "64250.005", "0.0015"))
+	assert.Error(t, err, "price off the 0.01 tick")
+	_, err = registry.AddOrder(order(models.Buy, "64250.5", "0.000015"))
+	assert.Error(t, err, "quantity off the 0.00001 lot")
+	_, err = registry.AddOrder(order(models.Buy, "64250.5", "0.00007"))
+	assert.Error(t, err, "4.497535 USDT is below the 5 USDT min notional")
+
+	maker := order(models.Sell, "64250.5", "0.0015")
+	_, err = registry.AddOrder(maker)
+	assert.NoError(t, err)
+	trades, err := registry.AddOrder(order(models.Buy, "64251", "0.001"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, "64250.5", trades[0].Price.String())
+	assert.Equal(t, "0.001", trades[0].Quantity.String())
+	assert.Equal(t, "0.0005", maker.Remaining().String())
+}
+
+func TestFindOrderAcrossMarkets(t *testing.T) {
+	registry := newTestRegistry(t)
+	eth := &models.Order{Symbol: "ETH-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"}
+	_, err := registry.AddOrder(eth)
+	assert.NoError(t, err)
+
+	order, ok := registry.FindOrder(eth.ID)
+	assert.True(t, ok)
+	assert.Equal(t, eth, order)
+	_, ok = registry.FindOrder(eth.ID + 1)
+	assert.False(t, ok)
+	symbol, ok := registry.OrderSymbol(eth.ID)
+	assert.True(t, ok)
+	assert.Equal(t, "ETH-USDT", symbol)
+
+	// NOTE: a filled or cancelled order leaves the index, a restored one is back in it
+	_, err = registry.AddOrder(&models.Order{Symbol: "ETH-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.NoError(t, err)
+	_, ok = registry.OrderSymbol(eth.ID)
+	assert.False(t, ok, "filled")
+	btc := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"}
+	_, err = registry.AddOrder(btc)
+	assert.NoError(t, err)
+	state := registry.State()
+	_, err = registry.CancelOrder("BTC-USDT", btc.ID)
+	assert.NoError(t, err)
+	_, ok = registry.FindOrder(btc.ID)
+	assert.False(t, ok, "cancelled")
+
+	restored := newTestRegistry(t)
+	assert.NoError(t, restored.Restore(state))
+	order, ok = restored.FindOrder(btc.ID)
+	assert.True(t, ok)
+	assert.Equal(t, "BTC-USDT", order.Symbol)
+}
+
+func TestOpenOrdersAndErrors(t *testing.T) {
+	registry := newTestRegistry(t)
+	for _, o := range []*models.Order{
+		{Symbol: "ETH-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"},
+		{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "bob"},
+		{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"},
+	} {
+		_, err := registry.AddOrder(o)
+		assert.NoError(t, err)
+	}
+
+	all, err := registry.OpenOrders("bob", "")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	btc, err := registry.OpenOrders("bob", "BTC-USDT")
+	assert.NoError(t, err)
+	assert.Len(t, btc, 1)
+
+	_, err = registry.OpenOrders("bob", "XRP-USDT")
+	assert.ErrorIs(t, err, models.ErrMarketNotFound)
+	_, err = registry.CancelOrder("BTC-USDT", 999)
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	assert.NoError(t, registry.SetStatus("BTC-USDT", models.MarketClosed))
+	_, err = registry.CancelOrder("BTC-USDT", btc[0].ID)
+	assert.ErrorIs(t, err, models.ErrMarketNotTrading)
+}
+
+func TestSessionCloseFromConfig(t *testing.T) {
+	registry := newTestRegistry(t)
+	_, err := registry.Register(models.Market{Symbol: "ETH-BTC", BaseAsset: "ETH", QuoteAsset: "BTC", TickSize: dec(1), LotSize: dec(1), SessionClose: "25:00"})
+	assert.Error(t, err)
+	_, err = registry.Register(models.Market{Symbol: "ETH-BTC", BaseAsset: "ETH", QuoteAsset: "BTC", TickSize: dec(1), LotSize: dec(1), SessionClose: "close"})
+	assert.Error(t, err)
+
+	m, err := registry.Register(models.Market{Symbol: "ETH-BTC", BaseAsset: "ETH", QuoteAsset: "BTC", TickSize: dec(1), LotSize: dec(1), SessionClose: "16:30"})
+	assert.NoError(t, err)
+	m.Book.SetClock(fixedClock(time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)))
+	day := &models.Order{Symbol: "ETH-BTC", Side: models.Buy, Price: dec(1), Quantity: dec(1), OwnerUsername: "alice", TimeInForce: models.DAY}
+	_, err = registry.AddOrder(day)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(time.Date(2024, 12, 1, 16, 30, 0, 0, time.UTC).Unix()), day.ExpiresAt)
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelChanges(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 101, 5, "alice"))
+	assert.Empty(t, ob.LevelChanges(), "nothing is tracked until asked for")
+
+	ob.TrackLevelChanges()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 101, 3, "bob"))
+	assert.Equal(t, []models.LevelUpdate{
+		{Side: models.Sell, Level: models.DepthLevel{Price: dec(100)}},
+		{Side: models.Sell, Level: models.DepthLevel{Price: dec(101), Quantity: dec(4), OrderCount: 1}},
+	}, ob.LevelChanges())
+	assert.Empty(t, ob.LevelChanges())
+}
+
+func TestMarketDataFeed(t *testing.T) {
+	ob := models.NewOrderbook()
+	ob.Symbol = "BTC-USDT"
+	ob.TrackLevelChanges()
+	hub := models.NewMarketDataHub(16)
+
+	trades, _ := ob.AddOrder(newOrder(models.Sell, 100, 5, "alice"))
+	hub.Publish(ob, trades)
+
+	sub := hub.Subscribe(ob, 10)
+	defer sub.Close()
+	snapshot := <-sub.C
+	assert.Equal(t, models.MarketDataSnapshot, snapshot.Kind)
+	assert.Equal(t, uint64(1), snapshot.Sequence)
+	assert.Equal(t, []models.DepthLevel{{Price: dec(100), Quantity: dec(5), OrderCount: 1}}, snapshot.Asks)
+
+	trades, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "bob"))
+	hub.Publish(ob, trades)
+	trade := <-sub.C
+	assert.Equal(t, models.MarketDataTrade, trade.Kind)
+	assert.Equal(t, uint64(2), trade.Sequence)
+	assert.Equal(t, dec(2), trade.Trade.Quantity)
+	level := <-sub.C
+	assert.Equal(t, models.MarketDataLevel, level.Kind)
+	assert.Equal(t, uint64(3), level.Sequence, "sequence numbers have no gaps")
+	assert.Equal(t, dec(3), level.Update.Level.Quantity)
+}
+
+func TestSlowMarketDataSubscriberIsDropped(t *testing.T) {
+	ob := models.NewOrderbook()
+	ob.TrackLevelChanges()
+	hub := models.NewMarketDataHub(1)
+	sub := hub.Subscribe(ob, 0)
+
+	for i := int64(0); i < 3; i++ {
+		trades, _ := ob.AddOrder(newOrder(models.Sell, 100+i, 1, "alice"))
+		hub.Publish(ob, trades)
+	}
+	var received int
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, 2, received, "the snapshot and one update fit, then the channel is closed")
+	sub.Close()
+}
@@ -0,0 +1,752 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func dec(n int64) models.Decimal { return models.NewDecimalFromInt(n) }
+
+func newOrder(side models.OrderSide, price, quantity int64, owner string) *models.Order {
+	return &models.Order{
+		Price:         dec(price),
+		Quantity:      dec(quantity),
+		Side:          side,
+		OwnerUsername: owner,
+	}
+}
+
+func TestAddOrderRestsWhenNothingCrosses(t *testing.T) {
+	ob := models.NewOrderbook()
+
+	trades, err := ob.AddOrder(newOrder(models.Buy, 100, 5, "alice"))
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+
+	trades, err = ob.AddOrder(newOrder(models.Sell, 101, 5, "bob"))
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+
+	assert.Equal(t, 1, ob.BidCount())
+	assert.Equal(t, 1, ob.AskCount())
+}
+
+func TestAddOrderFullFill(t *testing.T) {
+	ob := models.NewOrderbook()
+	maker := newOrder(models.Sell, 100, 5, "alice")
+	_, err := ob.AddOrder(maker)
+	assert.NoError(t, err)
+
+	taker := newOrder(models.Buy, 105, 5, "bob")
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, dec(100), trades[0].Price, "trades execute at the maker's price")
+	assert.Equal(t, dec(5), trades[0].Quantity)
+	assert.Equal(t, maker.ID, trades[0].MakerOrderID)
+	assert.Equal(t, taker.ID, trades[0].TakerOrderID)
+	assert.Equal(t, models.Buy, trades[0].AggressorSide)
+
+	assert.Equal(t, models.StatusFilled, maker.Status)
+	assert.Equal(t, models.StatusFilled, taker.Status)
+	assert.Equal(t, 0, ob.BidCount())
+	assert.Equal(t, 0, ob.AskCount())
+}
+
+func TestAddOrderPartialFillRestsRemainder(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 3, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 102, 3, "alice"))
+
+	taker := newOrder(models.Buy, 101, 10, "bob")
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, dec(3), taker.FilledQuantity)
+	assert.Equal(t, models.StatusPartiallyFilled, taker.Status)
+
+	best_bid, ok := ob.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, taker.ID, best_bid.ID)
+	assert.Equal(t, dec(7), best_bid.Remaining())
+
+	best_ask, ok := ob.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, dec(102), best_ask.Price)
+}
+
+func TestAddOrderSweepsSeveralMakers(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Buy, 99, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 101, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "alice"))
+
+	trades, err := ob.AddOrder(newOrder(models.Sell, 100, 5, "bob"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, dec(101), trades[0].Price)
+	assert.Equal(t, dec(100), trades[1].Price)
+
+	best_ask, ok := ob.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, dec(1), best_ask.Remaining())
+	best_bid, _ := ob.BestBid()
+	assert.Equal(t, dec(99), best_bid.Price)
+}
+
+func TestAddOrderRejectsInvalidOrders(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, err := ob.AddOrder(newOrder(models.Buy, 100, 0, "alice"))
+	assert.Error(t, err)
+	_, err = ob.AddOrder(newOrder(models.Buy, 0, 1, "alice"))
+	assert.Error(t, err)
+}
+
+func TestSamePriceFillsInTimeOrder(t *testing.T) {
+	ob := models.NewOrderbook()
+	first := newOrder(models.Sell, 100, 1, "alice")
+	first.Timestamp = 10
+	second := newOrder(models.Sell, 100, 1, "bob")
+	second.Timestamp = 10
+	third := newOrder(models.Sell, 100, 1, "carol")
+	third.Timestamp = 9
+	for _, o := range []*models.Order{first, second, third} {
+		_, err := ob.AddOrder(o)
+		assert.NoError(t, err)
+	}
+
+	trades, err := ob.AddOrder(newOrder(models.Buy, 100, 3, "dave"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 3)
+	// the earlier timestamp wins, equal timestamps fall back to arrival sequence
+	assert.Equal(t, third.ID, trades[0].MakerOrderID)
+	assert.Equal(t, first.ID, trades[1].MakerOrderID)
+	assert.Equal(t, second.ID, trades[2].MakerOrderID)
+}
+
+func TestSequenceIsMonotonic(t *testing.T) {
+	ob := models.NewOrderbook()
+	var last uint64
+	for i := 0; i < 20; i++ {
+		o := newOrder(models.Buy, int64(90+i%3), 1, "alice")
+		_, err := ob.AddOrder(o)
+		assert.NoError(t, err)
+		assert.Greater(t, o.Sequence, last)
+		last = o.Sequence
+	}
+
+	// equal price and timestamp: the bids must come out strictly by sequence
+	trades, _ := ob.AddOrder(newOrder(models.Sell, 92, 20, "bob"))
+	for i := 1; i < len(trades); i++ {
+		assert.Less(t, trades[i-1].MakerOrderID, trades[i].MakerOrderID)
+	}
+}
+
+func TestDepthAggregatesPriceLevels(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 3, "bob"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 98, 1, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 99, 4, "carol"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 103, 1, "dave"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 101, 6, "dave"))
+
+	assert.Equal(t, []models.DepthLevel{
+		{Price: dec(100), Quantity: dec(5), OrderCount: 2},
+		{Price: dec(99), Quantity: dec(4), OrderCount: 1},
+	}, ob.Depth(models.Buy, 2))
+	assert.Equal(t, []models.DepthLevel{
+		{Price: dec(101), Quantity: dec(6), OrderCount: 1},
+		{Price: dec(103), Quantity: dec(1), OrderCount: 1},
+	}, ob.Depth(models.Sell, 0))
+
+	// a partial fill reduces the aggregated quantity, a full fill removes the level
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 3, "erin"))
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(2), OrderCount: 1}, ob.DepthAt(models.Buy, dec(100)))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "erin"))
+	assert.Equal(t, models.DepthLevel{Price: dec(100)}, ob.DepthAt(models.Buy, dec(100)))
+
+	best_bid, ok := ob.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, dec(99), best_bid.Price)
+	assert.Len(t, ob.Depth(models.Buy, 0), 2)
+}
+
+func TestCancelOrder(t *testing.T) {
+	ob := models.NewOrderbook()
+	first := newOrder(models.Sell, 100, 2, "alice")
+	second := newOrder(models.Sell, 100, 3, "bob")
+	_, _ = ob.AddOrder(first)
+	_, _ = ob.AddOrder(second)
+
+	cancelled, err := ob.CancelOrder(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, cancelled.Status)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(3), OrderCount: 1}, ob.DepthAt(models.Sell, dec(100)))
+
+	_, err = ob.CancelOrder(first.ID)
+	assert.Error(t, err, "an order can only be cancelled once")
+
+	trades, _ := ob.AddOrder(newOrder(models.Buy, 100, 3, "carol"))
+	assert.Len(t, trades, 1)
+	assert.Equal(t, second.ID, trades[0].MakerOrderID)
+	_, ok := ob.GetOrder(second.ID)
+	assert.False(t, ok, "filled orders leave the index")
+}
+
+func TestAmendQuantityDecreaseKeepsPriority(t *testing.T) {
+	ob := models.NewOrderbook()
+	first := newOrder(models.Buy, 100, 5, "alice")
+	second := newOrder(models.Buy, 100, 5, "bob")
+	_, _ = ob.AddOrder(first)
+	_, _ = ob.AddOrder(second)
+
+	trades, err := ob.AmendOrder(first.ID, dec(100), dec(2))
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(7), OrderCount: 2}, ob.DepthAt(models.Buy, dec(100)))
+
+	best_bid, _ := ob.BestBid()
+	assert.Equal(t, first.ID, best_bid.ID)
+}
+
+func TestAmendQuantityIncreaseOrPriceChangeLosesPriority(t *testing.T) {
+	ob := models.NewOrderbook()
+	first := newOrder(models.Buy, 100, 5, "alice")
+	second := newOrder(models.Buy, 100, 5, "bob")
+	_, _ = ob.AddOrder(first)
+	_, _ = ob.AddOrder(second)
+
+	_, err := ob.AmendOrder(first.ID, dec(100), dec(6))
+	assert.NoError(t, err)
+	best_bid, _ := ob.BestBid()
+	assert.Equal(t, second.ID, best_bid.ID)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(11), OrderCount: 2}, ob.DepthAt(models.Buy, dec(100)))
+
+	// moving the price through the spread trades straight away
+	_, _ = ob.AddOrder(newOrder(models.Sell, 102, 4, "carol"))
+	trades, err := ob.AmendOrder(second.ID, dec(102), dec(5))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, dec(4), trades[0].Quantity)
+	assert.Equal(t, models.DepthLevel{Price: dec(102), Quantity: dec(1), OrderCount: 1}, ob.DepthAt(models.Buy, dec(102)))
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(6), OrderCount: 1}, ob.DepthAt(models.Buy, dec(100)))
+}
+
+func TestAmendRejectsInvalidQuantity(t *testing.T) {
+	ob := models.NewOrderbook()
+	maker := newOrder(models.Sell, 100, 5, "alice")
+	_, _ = ob.AddOrder(maker)
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 3, "bob"))
+
+	_, err := ob.AmendOrder(maker.ID, dec(100), dec(3))
+	assert.Error(t, err)
+	_, err = ob.AmendOrder(12345, dec(100), dec(3))
+	assert.Error(t, err)
+}
+
+func TestAmendRechecksIcebergAndExpiry(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(func() time.Time { return now })
+
+	iceberg := newOrder(models.Sell, 100, 10, "alice")
+	iceberg.DisplayQuantity = dec(3)
+	_, err := ob.AddOrder(iceberg)
+	assert.NoError(t, err)
+	_, err = ob.AmendOrder(iceberg.ID, dec(100), dec(3))
+	assert.Error(t, err, "no longer more than the display quantity")
+	assert.Equal(t, dec(10), iceberg.Quantity)
+
+	gtd := newOrder(models.Sell, 101, 1, "alice")
+	gtd.TimeInForce = models.GTD
+	gtd.ExpiresAt = uint32(now.Add(time.Minute).Unix())
+	_, err = ob.AddOrder(gtd)
+	assert.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, err = ob.AmendOrder(gtd.ID, dec(102), dec(1))
+	assert.ErrorIs(t, err, models.ErrOrderExpired)
+	assert.Equal(t, dec(101), gtd.Price)
+	assert.Len(t, ob.ExpireOrders(now), 1, "left to the sweep")
+}
+
+func newMarketOrder(side models.OrderSide, quantity int64, owner string) *models.Order {
+	return &models.Order{Type: models.MarketOrder, Quantity: dec(quantity), Side: side, OwnerUsername: owner}
+}
+
+func TestMarketOrderSweepsUntilFilled(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 105, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 150, 2, "alice"))
+
+	taker := newMarketOrder(models.Buy, 5, "bob")
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 3)
+	assert.Equal(t, dec(150), trades[2].Price)
+	assert.Equal(t, models.StatusFilled, taker.Status)
+	assert.Equal(t, 1, ob.AskCount())
+}
+
+func TestMarketOrderRemainderIsCancelledWhenBookRunsDry(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "alice"))
+
+	taker := newMarketOrder(models.Sell, 5, "bob")
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, dec(2), taker.FilledQuantity)
+	assert.Equal(t, models.StatusCancelled, taker.Status)
+	assert.Equal(t, 0, ob.AskCount(), "market orders never rest")
+}
+
+func TestMarketOrderProtectionStopsTheSweep(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 101, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 120, 2, "alice"))
+
+	protected := newMarketOrder(models.Buy, 6, "bob")
+	protected.ProtectionPrice = dec(101)
+	trades, err := ob.AddOrder(protected)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, models.StatusCancelled, protected.Status)
+	assert.Equal(t, dec(4), protected.FilledQuantity)
+
+	_, _ = ob.AddOrder(newOrder(models.Buy, 110, 2, "carol"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 105, 2, "carol"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "carol"))
+
+	// 5% from a best bid of 110 allows selling down to 104.5, rounded up to 105
+	slippage := newMarketOrder(models.Sell, 6, "dave")
+	slippage.MaxSlippageBps = 500
+	trades, err = ob.AddOrder(slippage)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, dec(105), trades[1].Price)
+	assert.Equal(t, models.StatusCancelled, slippage.Status)
+	best_bid, _ := ob.BestBid()
+	assert.Equal(t, dec(100), best_bid.Price)
+}
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestImmediateOrCancel(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+
+	ioc := newOrder(models.Buy, 100, 5, "bob")
+	ioc.TimeInForce = models.IOC
+	trades, err := ob.AddOrder(ioc)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, models.StatusCancelled, ioc.Status)
+	assert.Equal(t, 0, ob.BidCount())
+}
+
+func TestFillOrKill(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 101, 2, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 103, 5, "alice"))
+
+	killed := newOrder(models.Buy, 101, 5, "bob")
+	killed.TimeInForce = models.FOK
+	trades, err := ob.AddOrder(killed)
+	assert.NoError(t, err)
+	assert.Empty(t, trades, "FOK must not partially fill")
+	assert.Equal(t, models.StatusCancelled, killed.Status)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(2), OrderCount: 1}, ob.DepthAt(models.Sell, dec(100)))
+
+	filled := newOrder(models.Buy, 103, 5, "bob")
+	filled.TimeInForce = models.FOK
+	trades, err = ob.AddOrder(filled)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 3)
+	assert.Equal(t, models.StatusFilled, filled.Status)
+}
+
+func TestGoodTillDateExpires(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(fixedClock(now))
+
+	past := newOrder(models.Buy, 100, 1, "alice")
+	past.TimeInForce = models.GTD
+	past.ExpiresAt = uint32(now.Add(-time.Minute).Unix())
+	_, err := ob.AddOrder(past)
+	assert.Error(t, err)
+
+	gtd := newOrder(models.Buy, 100, 1, "alice")
+	gtd.TimeInForce = models.GTD
+	gtd.ExpiresAt = uint32(now.Add(time.Hour).Unix())
+	_, err = ob.AddOrder(gtd)
+	assert.NoError(t, err)
+	gtc := newOrder(models.Buy, 100, 1, "alice")
+	_, _ = ob.AddOrder(gtc)
+
+	assert.Empty(t, ob.ExpireOrders(now.Add(59*time.Minute)))
+	expired := ob.ExpireOrders(now.Add(time.Hour))
+	assert.Len(t, expired, 1)
+	assert.Equal(t, gtd.ID, expired[0].ID)
+	assert.Equal(t, models.StatusExpired, gtd.Status)
+	assert.Equal(t, 1, ob.BidCount())
+}
+
+func TestExpiresAtIsForGoodTillDateOnly(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(fixedClock(now))
+
+	for _, tif := range []models.TimeInForce{models.GTC, models.IOC, models.FOK, models.DAY} {
+		order := newOrder(models.Buy, 100, 1, "alice")
+		order.TimeInForce = tif
+		order.ExpiresAt = uint32(now.Add(time.Hour).Unix())
+		_, err := ob.AddOrder(order)
+		assert.ErrorIs(t, err, models.ErrExpiresAtNotGTD, tif.String())
+	}
+	assert.Equal(t, 0, ob.BidCount())
+}
+
+func TestDayOrdersExpireAtSessionClose(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(fixedClock(now))
+	assert.NoError(t, ob.SetSessionClose(16, 30))
+
+	day := newOrder(models.Sell, 100, 1, "alice")
+	day.TimeInForce = models.DAY
+	_, err := ob.AddOrder(day)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(time.Date(2024, 12, 1, 16, 30, 0, 0, time.UTC).Unix()), day.ExpiresAt)
+
+	// a cancelled DAY order is simply skipped by the sweep
+	cancelled := newOrder(models.Sell, 101, 1, "alice")
+	cancelled.TimeInForce = models.DAY
+	_, _ = ob.AddOrder(cancelled)
+	_, _ = ob.CancelOrder(cancelled.ID)
+
+	expired := ob.ExpireOrders(now.Add(7 * time.Hour))
+	assert.Len(t, expired, 1)
+	assert.Equal(t, 0, ob.AskCount())
+}
+
+func TestExpiredMakersNeverTrade(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(fixedClock(now))
+	gtd := newOrder(models.Sell, 100, 1, "alice")
+	gtd.TimeInForce = models.GTD
+	gtd.ExpiresAt = uint32(now.Add(time.Minute).Unix())
+	_, err := ob.AddOrder(gtd)
+	assert.NoError(t, err)
+	gtc := newOrder(models.Sell, 101, 1, "bob")
+	_, err = ob.AddOrder(gtc)
+	assert.NoError(t, err)
+
+	// NOTE: no sweep has run since the GTD ask expired
+	ob.SetClock(fixedClock(now.Add(time.Minute)))
+	fok := newOrder(models.Buy, 101, 2, "carol")
+	fok.TimeInForce = models.FOK
+	trades, err := ob.AddOrder(fok)
+	assert.NoError(t, err)
+	assert.Empty(t, trades, "the expired ask isn't counted as liquidity")
+	assert.Equal(t, models.StatusCancelled, fok.Status)
+
+	buy := newOrder(models.Buy, 101, 1, "carol")
+	trades, err = ob.AddOrder(buy)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, gtc.ID, trades[0].MakerOrderID, "the taker goes past the expired ask")
+	assert.Equal(t, models.StatusExpired, gtd.Status)
+	assert.Equal(t, 0, ob.AskCount())
+	assert.Empty(t, ob.ExpireOrders(now.Add(time.Hour)))
+}
+
+func TestGoodTillDateStopsExpire(t *testing.T) {
+	now := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	ob := models.NewOrderbook()
+	ob.SetClock(fixedClock(now))
+	stop := newStopOrder(models.StopLimitOrder, models.Buy, 110, 111, 1, "alice")
+	stop.TimeInForce = models.GTD
+	stop.ExpiresAt = uint32(now.Add(time.Hour).Unix())
+	_, err := ob.AddOrder(stop)
+	assert.NoError(t, err)
+
+	assert.True(t, ob.ExpiryDue(now.Add(time.Hour)))
+	expired := ob.ExpireOrders(now.Add(time.Hour))
+	assert.Len(t, expired, 1)
+	assert.Equal(t, models.StatusExpired, stop.Status)
+	assert.Equal(t, 0, ob.StopCount(models.Buy))
+	_, ok := ob.GetOrder(stop.ID)
+	assert.False(t, ok)
+}
+
+func TestPostOnlyRejectedWhenCrossing(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+
+	maker := newOrder(models.Buy, 100, 1, "bob")
+	maker.PostOnly = true
+	trades, err := ob.AddOrder(maker)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, models.StatusRejected, maker.Status)
+	assert.Equal(t, models.RejectPostOnlyWouldCross, maker.RejectReason)
+	assert.Equal(t, 0, ob.BidCount())
+
+	passive := newOrder(models.Buy, 99, 1, "bob")
+	passive.PostOnly = true
+	_, err = ob.AddOrder(passive)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusNew, passive.Status)
+
+	// amending it through the spread is refused and leaves the order where it was
+	_, err = ob.AmendOrder(passive.ID, dec(101), dec(1))
+	assert.Error(t, err)
+	assert.Equal(t, models.DepthLevel{Price: dec(99), Quantity: dec(1), OrderCount: 1}, ob.DepthAt(models.Buy, dec(99)))
+}
+
+func TestPostOnlyRepricedOneTickAway(t *testing.T) {
+	ob := models.NewOrderbook()
+	assert.NoError(t, ob.SetTickSize(dec(5)))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "alice"))
+
+	maker := newOrder(models.Sell, 90, 1, "bob")
+	maker.PostOnly = true
+	maker.PostOnlyReprice = true
+	trades, err := ob.AddOrder(maker)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, dec(105), maker.Price)
+	best_ask, _ := ob.BestAsk()
+	assert.Equal(t, maker.ID, best_ask.ID)
+}
+
+func TestPostOnlyRejectsContradictoryFlags(t *testing.T) {
+	ob := models.NewOrderbook()
+	ioc := newOrder(models.Buy, 100, 1, "alice")
+	ioc.PostOnly = true
+	ioc.TimeInForce = models.IOC
+	_, err := ob.AddOrder(ioc)
+	assert.Error(t, err)
+
+	market := newMarketOrder(models.Buy, 1, "alice")
+	market.PostOnly = true
+	_, err = ob.AddOrder(market)
+	assert.Error(t, err)
+}
+
+func newStopOrder(order_type models.OrderType, side models.OrderSide, stop, price, quantity int64, owner string) *models.Order {
+	return &models.Order{Type: order_type, StopPrice: dec(stop), Price: dec(price), Quantity: dec(quantity), Side: side, OwnerUsername: owner}
+}
+
+func TestStopMarketFiresWhenLastPriceCrosses(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 1, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Sell, 105, 5, "alice"))
+
+	stop := newStopOrder(models.StopMarketOrder, models.Buy, 100, 0, 2, "bob")
+	trades, err := ob.AddOrder(stop)
+	assert.NoError(t, err)
+	assert.Empty(t, trades, "nothing has traded yet")
+	assert.Equal(t, 1, ob.StopCount(models.Buy))
+	assert.Empty(t, ob.Depth(models.Buy, 0), "held stops aren't part of the book")
+
+	trades, err = ob.AddOrder(newOrder(models.Buy, 100, 1, "carol"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, stop.ID, trades[1].TakerOrderID)
+	assert.Equal(t, dec(105), trades[1].Price)
+	assert.True(t, stop.Triggered)
+	assert.Equal(t, models.StatusFilled, stop.Status)
+	assert.Equal(t, 0, ob.StopCount(models.Buy))
+	assert.Equal(t, dec(105), ob.LastTradePrice())
+}
+
+func TestStopLimitRestsAfterTrigger(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 1, "alice"))
+
+	stop := newStopOrder(models.StopLimitOrder, models.Sell, 100, 98, 3, "bob")
+	_, _ = ob.AddOrder(stop)
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 1, "carol"))
+
+	assert.True(t, stop.Triggered)
+	resting, ok := ob.GetOrder(stop.ID)
+	assert.True(t, ok)
+	assert.Equal(t, dec(98), resting.Price)
+	assert.Equal(t, models.DepthLevel{Price: dec(98), Quantity: dec(3), OrderCount: 1}, ob.DepthAt(models.Sell, dec(98)))
+}
+
+func TestStopsCascadeInDeterministicOrder(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 1, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 99, 1, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 98, 1, "alice"))
+	_, _ = ob.AddOrder(newOrder(models.Buy, 97, 1, "alice"))
+
+	// held out of arrival order: the higher stop must fire first
+	low := newStopOrder(models.StopMarketOrder, models.Sell, 99, 0, 1, "bob")
+	high := newStopOrder(models.StopMarketOrder, models.Sell, 100, 0, 1, "bob")
+	chained := newStopOrder(models.StopMarketOrder, models.Sell, 98, 0, 1, "bob")
+	for _, o := range []*models.Order{low, high, chained} {
+		_, err := ob.AddOrder(o)
+		assert.NoError(t, err)
+	}
+
+	trades, err := ob.AddOrder(newOrder(models.Sell, 100, 1, "carol"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 4)
+	assert.Equal(t, []models.Decimal{dec(100), dec(99), dec(98), dec(97)}, []models.Decimal{trades[0].Price, trades[1].Price, trades[2].Price, trades[3].Price})
+	assert.Equal(t, high.ID, trades[1].TakerOrderID)
+	assert.Equal(t, low.ID, trades[2].TakerOrderID)
+	assert.Equal(t, chained.ID, trades[3].TakerOrderID, "a stop triggered by another stop's fill fires in the same call")
+	assert.Equal(t, 0, ob.BidCount())
+}
+
+func TestCancelHeldStop(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, _ = ob.AddOrder(newOrder(models.Sell, 100, 5, "alice"))
+	stop := newStopOrder(models.StopMarketOrder, models.Buy, 100, 0, 2, "bob")
+	_, _ = ob.AddOrder(stop)
+
+	_, err := ob.CancelOrder(stop.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, stop.Status)
+
+	trades, _ := ob.AddOrder(newOrder(models.Buy, 100, 1, "carol"))
+	assert.Len(t, trades, 1, "a cancelled stop must not fire")
+	assert.False(t, stop.Triggered)
+}
+
+func TestIcebergShowsOnlyDisplaySlice(t *testing.T) {
+	ob := models.NewOrderbook()
+	iceberg := newOrder(models.Sell, 100, 10, "alice")
+	iceberg.DisplayQuantity = dec(3)
+	_, err := ob.AddOrder(iceberg)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DepthLevel{{Price: dec(100), Quantity: dec(3), OrderCount: 1}}, ob.Depth(models.Sell, 0))
+
+	trades, _ := ob.AddOrder(newOrder(models.Buy, 100, 2, "bob"))
+	assert.Len(t, trades, 1)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(1), OrderCount: 1}, ob.DepthAt(models.Sell, dec(100)))
+
+	// taking the rest of the slice and more fills across replenished slices
+	trades, _ = ob.AddOrder(newOrder(models.Buy, 100, 5, "bob"))
+	assert.Len(t, trades, 3)
+	assert.Equal(t, []models.Decimal{dec(1), dec(3), dec(1)}, []models.Decimal{trades[0].Quantity, trades[1].Quantity, trades[2].Quantity})
+	assert.Equal(t, dec(7), iceberg.FilledQuantity)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(2), OrderCount: 1}, ob.DepthAt(models.Sell, dec(100)))
+}
+
+func TestIcebergReplenishesAtBackOfQueue(t *testing.T) {
+	ob := models.NewOrderbook()
+	iceberg := newOrder(models.Buy, 100, 6, "alice")
+	iceberg.DisplayQuantity = dec(2)
+	_, _ = ob.AddOrder(iceberg)
+	plain := newOrder(models.Buy, 100, 4, "bob")
+	_, _ = ob.AddOrder(plain)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(6), OrderCount: 2}, ob.DepthAt(models.Buy, dec(100)))
+
+	trades, _ := ob.AddOrder(newOrder(models.Sell, 100, 3, "carol"))
+	assert.Len(t, trades, 2)
+	assert.Equal(t, iceberg.ID, trades[0].MakerOrderID)
+	assert.Equal(t, plain.ID, trades[1].MakerOrderID, "the refilled slice lost its place to the plain order")
+
+	trades, _ = ob.AddOrder(newOrder(models.Sell, 100, 4, "carol"))
+	assert.Len(t, trades, 2)
+	assert.Equal(t, plain.ID, trades[0].MakerOrderID)
+	assert.Equal(t, dec(3), trades[0].Quantity)
+	assert.Equal(t, iceberg.ID, trades[1].MakerOrderID)
+	assert.Equal(t, models.DepthLevel{Price: dec(100), Quantity: dec(1), OrderCount: 1}, ob.DepthAt(models.Buy, dec(100)))
+}
+
+func TestFillOrKillSeesHiddenIcebergQuantity(t *testing.T) {
+	ob := models.NewOrderbook()
+	iceberg := newOrder(models.Sell, 100, 10, "alice")
+	iceberg.DisplayQuantity = dec(1)
+	_, _ = ob.AddOrder(iceberg)
+
+	fok := newOrder(models.Buy, 100, 8, "bob")
+	fok.TimeInForce = models.FOK
+	trades, err := ob.AddOrder(fok)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 8)
+	assert.Equal(t, models.StatusFilled, fok.Status)
+}
+
+func TestFractionalPricesAndQuantities(t *testing.T) {
+	ob := models.NewOrderbook()
+	assert.NoError(t, ob.SetTickSize(models.MustParseDecimal("0.01")))
+	maker := &models.Order{Side: models.Sell, Price: models.MustParseDecimal("64250.5"), Quantity: models.MustParseDecimal("0.0015"), OwnerUsername: "alice"}
+	_, _ = ob.AddOrder(maker)
+	_, _ = ob.AddOrder(&models.Order{Side: models.Sell, Price: models.MustParseDecimal("64250.5"), Quantity: models.MustParseDecimal("0.0001"), OwnerUsername: "alice"})
+
+	taker := newMarketOrder(models.Buy, 0, "bob")
+	taker.Quantity = models.MustParseDecimal("0.0012")
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, models.MustParseDecimal("0.0003"), maker.Remaining())
+	assert.Equal(t, models.DepthLevel{Price: models.MustParseDecimal("64250.5"), Quantity: models.MustParseDecimal("0.0004"), OrderCount: 2}, ob.DepthAt(models.Sell, models.MustParseDecimal("64250.50")))
+
+	post_only := &models.Order{Side: models.Buy, Price: models.MustParseDecimal("64251"), Quantity: models.MustParseDecimal("0.001"), PostOnly: true, PostOnlyReprice: true, OwnerUsername: "carol"}
+	trades, err = ob.AddOrder(post_only)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, "64250.49", post_only.Price.String(), "repriced one 0.01 tick below the best ask")
+}
+
+func TestOrderListenerSeesEveryStateChange(t *testing.T) {
+	ob := models.NewOrderbook()
+	var seen []string
+	ob.SetOrderListener(func(event models.OrderEvent) {
+		seen = append(seen, event.Order.OwnerUsername+":"+event.Kind.String()+":"+event.Order.Status.String())
+	})
+
+	maker := newOrder(models.Sell, 100, 5, "alice")
+	_, _ = ob.AddOrder(maker)
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 2, "bob"))
+	_, _ = ob.AmendOrder(maker.ID, dec(100), dec(4))
+	_, _ = ob.CancelOrder(maker.ID)
+	ioc := newOrder(models.Buy, 99, 1, "bob")
+	ioc.TimeInForce = models.IOC
+	_, _ = ob.AddOrder(ioc)
+
+	assert.Equal(t, []string{
+		"alice:Accepted:New",
+		"bob:Accepted:New",                                  // accepted before it trades
+		"alice:Filled:PartiallyFilled", "bob:Filled:Filled", // maker first, then the incoming order
+		"alice:Amended:PartiallyFilled",
+		"alice:Cancelled:Cancelled",
+		"bob:Accepted:New", "bob:Cancelled:Cancelled",
+	}, seen)
+}
+
+func TestTradeSequenceAndRecentTrades(t *testing.T) {
+	ob := models.NewOrderbook()
+	for i := 0; i < 120; i++ {
+		_, _ = ob.AddOrder(newOrder(models.Sell, 100, 1, "alice"))
+	}
+	trades, err := ob.AddOrder(newOrder(models.Buy, 100, 3, "bob"))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, []uint64{trades[0].Sequence, trades[1].Sequence, trades[2].Sequence})
+	assert.Equal(t, "alice", trades[0].MakerOwner)
+	assert.Equal(t, "bob", trades[0].TakerOwner)
+
+	_, _ = ob.AddOrder(newOrder(models.Buy, 100, 110, "bob"))
+	recent := ob.RecentTrades(2)
+	assert.Len(t, recent, 2)
+	assert.Equal(t, uint64(113), recent[0].Sequence, "newest first")
+	assert.Equal(t, uint64(112), recent[1].Sequence)
+	assert.Len(t, ob.RecentTrades(0), 100, "only the last 100 are kept")
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// NOTE: levels come and go in no particular order, depth stays sorted best first with one entry per price.
+func TestDepthStaysSortedAsLevelsComeAndGo(t *testing.T) {
+	ob := models.NewOrderbook()
+	rng := rand.New(rand.NewPCG(7, 7))
+	resting := map[int64][]*models.Order{}
+	for i := 0; i < 5000; i++ {
+		price := int64(1 + rng.IntN(500))
+		if orders := resting[price]; len(orders) > 0 && rng.IntN(2) == 0 {
+			_, err := ob.CancelOrder(orders[0].ID)
+			assert.NoError(t, err)
+			resting[price] = orders[1:]
+			continue
+		}
+		side := models.Buy
+		if price > 250 {
+			side = models.Sell
+		}
+		order := newOrder(side, price, 1, "alice")
+		_, err := ob.AddOrder(order)
+		assert.NoError(t, err)
+		resting[price] = append(resting[price], order)
+	}
+
+	var bids, asks []models.DepthLevel
+	for price, orders := range resting {
+		if len(orders) == 0 {
+			continue
+		}
+		level := models.DepthLevel{Price: dec(price), Quantity: dec(int64(len(orders))), OrderCount: len(orders)}
+		if price > 250 {
+			asks = append(asks, level)
+		} else {
+			bids = append(bids, level)
+		}
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price.GreaterThan(bids[j].Price) })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price.LessThan(asks[j].Price) })
+	assert.Equal(t, bids, ob.Depth(models.Buy, 0))
+	assert.Equal(t, asks, ob.Depth(models.Sell, 0))
+	assert.Equal(t, asks[:10], ob.Depth(models.Sell, 10))
+}
+
+/*
+BenchmarkLevelChurn places and cancels an order at a price of its own in the middle of a side that already holds
+depth levels, so every iteration adds a level and drops it again: the cost of bookSide.level and removeLevel
+against how deep the book is.
+*/
+func BenchmarkLevelChurn(b *testing.B) {
+	for _, depth := range []int{100, 1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			ob := models.NewOrderbook()
+			for i := 0; i < depth; i++ {
+				_, _ = ob.AddOrder(newOrder(models.Sell, int64(1000000+2*i), 1, "alice"))
+			}
+			middle := int64(1000000 + depth + 1) // NOTE: odd, so a price no resting order has
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				order := newOrder(models.Sell, middle, 1, "bob")
+				if _, err := ob.AddOrder(order); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := ob.CancelOrder(order.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// NOTE: an Order from a client still on the double price and quantity (fields 2 and 3) must not be misread.
+func TestOrderWireSkipsTheOldDoubleFields(t *testing.T) {
+	var old []byte
+	old = protowire.AppendTag(old, 1, protowire.VarintType)
+	old = protowire.AppendVarint(old, 7)
+	old = protowire.AppendTag(old, 2, protowire.Fixed64Type)
+	old = protowire.AppendFixed64(old, math.Float64bits(64250.5))
+	old = protowire.AppendTag(old, 3, protowire.Fixed64Type)
+	old = protowire.AppendFixed64(old, math.Float64bits(0.0015))
+
+	var order pb.Order
+	assert.NoError(t, proto.Unmarshal(old, &order))
+	assert.Equal(t, uint64(7), order.GetId())
+	assert.Empty(t, order.GetPrice())
+	assert.Empty(t, order.GetQuantity())
+
+	wire, err := proto.Marshal(&pb.Order{Price: "64250.5", Quantity: "0.0015"})
+	assert.NoError(t, err)
+	var numbers []protowire.Number
+	for len(wire) > 0 {
+		number, kind, n := protowire.ConsumeTag(wire)
+		assert.Equal(t, protowire.BytesType, kind)
+		wire = wire[n:]
+		n = protowire.ConsumeFieldValue(number, kind, wire)
+		wire = wire[n:]
+		numbers = append(numbers, number)
+	}
+	assert.Equal(t, []protowire.Number{27, 28}, numbers)
+}
@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTradePreventionModes(t *testing.T) {
+	// NOTE: alice's own ask of 2 @ 100 is in front of bob's 3 @ 101, alice buys 4 @ 101
+	for _, tc := range []struct {
+		mode           models.SelfTradePrevention
+		traded         []models.Decimal // NOTE: quantities, all against bob
+		own_ask, taker models.OrderStatus
+		bids, asks     int
+	}{
+		{models.STPCancelNewest, nil, models.StatusNew, models.StatusCancelled, 0, 2},
+		{models.STPCancelOldest, []models.Decimal{dec(3)}, models.StatusCancelled, models.StatusPartiallyFilled, 1, 0},
+		{models.STPCancelBoth, nil, models.StatusCancelled, models.StatusCancelled, 0, 1},
+		{models.STPDecrementAndCancel, []models.Decimal{dec(2)}, models.StatusCancelled, models.StatusFilled, 0, 1},
+	} {
+		ob := models.NewOrderbook()
+		own_ask := newOrder(models.Sell, 100, 2, "alice")
+		_, err := ob.AddOrder(own_ask)
+		assert.NoError(t, err)
+		_, err = ob.AddOrder(newOrder(models.Sell, 101, 3, "bob"))
+		assert.NoError(t, err)
+
+		taker := newOrder(models.Buy, 101, 4, "alice")
+		taker.SelfTradePrevention = tc.mode
+		trades, err := ob.AddOrder(taker)
+		assert.NoError(t, err)
+		var traded []models.Decimal
+		for _, trade := range trades {
+			assert.Equal(t, "bob", trade.MakerOwner, tc.mode.String())
+			traded = append(traded, trade.Quantity)
+		}
+		assert.Equal(t, tc.traded, traded, tc.mode.String())
+		assert.Equal(t, tc.own_ask, own_ask.Status, tc.mode.String())
+		assert.Equal(t, tc.taker, taker.Status, tc.mode.String())
+		assert.Equal(t, tc.bids, ob.BidCount(), tc.mode.String())
+		assert.Equal(t, tc.asks, ob.AskCount(), tc.mode.String())
+		if own_ask.Status == models.StatusCancelled {
+			assert.Equal(t, models.RejectSelfTradePrevented, own_ask.RejectReason, tc.mode.String())
+		}
+	}
+
+	ob := models.NewOrderbook()
+	_, err := ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	assert.NoError(t, err)
+	trades, err := ob.AddOrder(newOrder(models.Buy, 100, 2, "alice"))
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1, "without a mode an owner trades with themselves")
+
+	bad := newOrder(models.Buy, 100, 2, "alice")
+	bad.SelfTradePrevention = 9
+	_, err = ob.AddOrder(bad)
+	assert.Error(t, err)
+}
+
+func TestDecrementAndCancelKeepsTheLargerOrder(t *testing.T) {
+	ob := models.NewOrderbook()
+	var events []models.OrderEvent
+	ob.SetOrderListener(func(event models.OrderEvent) { events = append(events, event) })
+	own_ask := newOrder(models.Sell, 100, 5, "alice")
+	own_ask.DisplayQuantity = dec(2)
+	_, err := ob.AddOrder(own_ask)
+	assert.NoError(t, err)
+
+	taker := newOrder(models.Buy, 100, 4, "alice")
+	taker.SelfTradePrevention = models.STPDecrementAndCancel
+	trades, err := ob.AddOrder(taker)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, models.StatusCancelled, taker.Status)
+	assert.Equal(t, dec(1), own_ask.Quantity, "5 less the 4 alice would have bought")
+	assert.Equal(t, models.StatusNew, own_ask.Status)
+	assert.Equal(t, []models.DepthLevel{{Price: dec(100), Quantity: dec(1), OrderCount: 1}}, ob.Depth(models.Sell, 0))
+	assert.Equal(t, models.OrderAmended, events[len(events)-2].Kind)
+	assert.Equal(t, own_ask.ID, events[len(events)-2].Order.ID)
+	assert.Equal(t, models.OrderCancelled, events[len(events)-1].Kind)
+}
+
+func TestSelfTradePreventionLooksAhead(t *testing.T) {
+	ob := models.NewOrderbook()
+	_, err := ob.AddOrder(newOrder(models.Sell, 100, 2, "alice"))
+	assert.NoError(t, err)
+	_, err = ob.AddOrder(newOrder(models.Sell, 101, 2, "bob"))
+	assert.NoError(t, err)
+
+	fok := newOrder(models.Buy, 101, 2, "alice")
+	fok.TimeInForce, fok.SelfTradePrevention = models.FOK, models.STPCancelNewest
+	trades, err := ob.AddOrder(fok)
+	assert.NoError(t, err)
+	assert.Empty(t, trades, "alice's own ask stops the order before bob's")
+	assert.Equal(t, models.StatusCancelled, fok.Status)
+
+	fok = newOrder(models.Buy, 101, 2, "alice")
+	fok.TimeInForce, fok.SelfTradePrevention = models.FOK, models.STPCancelOldest
+	trades, err = ob.AddOrder(fok)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1, "alice's own ask is cancelled out of the way")
+	assert.Equal(t, models.StatusFilled, fok.Status)
+}
+
+func TestAccountSelfTradePrevention(t *testing.T) {
+	registry, balances := newFundedRegistry(t)
+	assert.NoError(t, balances.Deposit("alice", "USDT", dec(1000), "test"))
+	assert.NoError(t, balances.Deposit("carol", "BTC", dec(2), "test"))
+	assert.NoError(t, registry.SetSelfTradePrevention("alice", models.STPDecrementAndCancel))
+	assert.Error(t, registry.SetSelfTradePrevention("alice", 9))
+
+	sell := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(4), OwnerUsername: "alice"}
+	_, err := registry.AddOrder(sell)
+	assert.NoError(t, err)
+	buy := &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"}
+	registry.PinSelfTradePrevention(buy)
+	trades, err := registry.AddOrder(buy)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+	assert.Equal(t, models.STPDecrementAndCancel, buy.SelfTradePrevention, "the order takes the account setting")
+	assert.Equal(t, models.StatusCancelled, buy.Status)
+	assert.Equal(t, dec(2), sell.Held, "the decremented sell gives back what it no longer needs")
+	assert.Equal(t, [2]models.Decimal{dec(8), dec(2)}, balanceOf(balances, "alice", "BTC"))
+	assert.Equal(t, [2]models.Decimal{dec(1000), models.Zero}, balanceOf(balances, "alice", "USDT"))
+
+	// NOTE: a market buy holds for carol's ask at 110, the one it will reach once its own ask at 100 is cancelled
+	_, err = registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: dec(110), Quantity: dec(2), OwnerUsername: "carol"})
+	assert.NoError(t, err)
+	market := withID(registry, &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Type: models.MarketOrder, Quantity: dec(2), OwnerUsername: "alice", SelfTradePrevention: models.STPCancelOldest})
+	assert.NoError(t, registry.ReserveOrder(market))
+	var held []models.Decimal
+	balances.Ledger().SetEntryListener(func(entry models.LedgerEntry) {
+		if entry.Kind == models.EntryHold && entry.Reference == fmt.Sprintf("order %d", market.ID) {
+			held = append(held, entry.Lines[0].Amount)
+		}
+	})
+	trades, err = registry.AddOrder(market)
+	assert.Equal(t, []models.Decimal{dec(220)}, held)
+	registry.Unreserve(market.ID)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, dec(110), trades[0].Price)
+	assert.Equal(t, models.StatusCancelled, sell.Status)
+	assert.Equal(t, [2]models.Decimal{dec(780), models.Zero}, balanceOf(balances, "alice", "USDT"))
+	assert.Equal(t, [2]models.Decimal{dec(12), models.Zero}, balanceOf(balances, "alice", "BTC"), "her cancelled 2 back and carol's 2")
+
+	_, err = balances.Ledger().Reconcile()
+	assert.NoError(t, err)
+}
+
+func TestEngineSelfTradePreventionReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live := newTestRegistry(t)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+
+	place := func(side models.OrderSide, price, quantity int64, owner string) journal.Result {
+		order := &models.Order{Symbol: "ETH-USDT", Side: side, Price: dec(price), Quantity: dec(quantity), OwnerUsername: owner}
+		result, err := books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: order.Symbol, Order: order})
+		assert.NoError(t, err)
+		return result
+	}
+	place(models.Sell, 100, 3, "alice")
+	assert.Len(t, place(models.Buy, 100, 1, "alice").Trades, 1)
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.SetSelfTradePrevention, Owner: "alice", SelfTradePrevention: models.STPCancelBoth})
+	assert.NoError(t, err)
+	buy := place(models.Buy, 100, 1, "alice")
+	assert.Empty(t, buy.Trades)
+	assert.Equal(t, models.StatusCancelled, buy.Order.Status)
+	place(models.Sell, 101, 3, "alice")
+	books.Close()
+	assert.Equal(t, models.STPCancelBoth, live.SelfTradePrevention("alice"))
+
+	replayed := newTestRegistry(t)
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+
+	restored := newTestRegistry(t)
+	assert.NoError(t, restored.Restore(live.State()))
+	assert.Equal(t, models.STPCancelBoth, restored.SelfTradePrevention("alice"))
+}
+
+/*
+A live run can journal alice's order, then journal and apply her new setting (account commands don't wait for
+the market), then apply the order. The order has to keep the mode it was journaled with, the one a replay sees.
+*/
+func TestSelfTradePreventionIsPinnedBeforeJournaling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	books := engine.New(newTestRegistry(t), wal, 8, nil)
+	ctx := context.Background()
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.SetSelfTradePrevention, Owner: "alice", SelfTradePrevention: models.STPCancelOldest})
+	assert.NoError(t, err)
+	order := &models.Order{Symbol: "ETH-USDT", Side: models.Sell, Price: dec(100), Quantity: dec(1), OwnerUsername: "alice"}
+	_, err = books.Submit(ctx, journal.Record{Kind: journal.NewOrder, Symbol: order.Symbol, Order: order})
+	assert.NoError(t, err)
+	books.Close()
+	var journaled []models.SelfTradePrevention
+	_, err = journal.Read(path, func(rec journal.Record) error {
+		if rec.Kind == journal.NewOrder {
+			journaled = append(journaled, rec.Order.SelfTradePrevention)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SelfTradePrevention{models.STPCancelOldest}, journaled, "the record carries the account setting")
+
+	order_rec := func(side models.OrderSide, id uint) journal.Record {
+		order := &models.Order{Symbol: "ETH-USDT", Side: side, Price: dec(100), Quantity: dec(1), OwnerUsername: "alice"}
+		order.ID = id
+		return journal.Record{Kind: journal.NewOrder, Symbol: order.Symbol, Order: order}
+	}
+	set := journal.Record{Kind: journal.SetSelfTradePrevention, Owner: "alice", SelfTradePrevention: models.STPCancelBoth}
+	journal_order := []journal.Record{order_rec(models.Sell, 1), order_rec(models.Buy, 2), set}
+	live_order := []journal.Record{journal_order[0], journal_order[2], journal_order[1]}
+
+	apply := func(records []journal.Record) *models.MarketRegistry {
+		registry := newTestRegistry(t)
+		for _, rec := range records {
+			if rec.Order != nil {
+				order := *rec.Order
+				rec.Order = &order
+			}
+			_, err := journal.Apply(registry, rec)
+			assert.NoError(t, err)
+		}
+		return registry
+	}
+	live, replayed := apply(live_order), apply(journal_order)
+	assert.JSONEq(t, stateJSON(t, replayed), stateJSON(t, live))
+	market, _ := live.Market("ETH-USDT")
+	assert.Equal(t, 0, market.Book.AskCount()+market.Book.BidCount(), "the buy journaled without a mode traded")
+}
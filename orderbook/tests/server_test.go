@@ -0,0 +1,324 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/pb"
+	"github.com/ParsaAminpour/GoCoin/orderbook/server"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "test-signing-key"
+
+/*
+serve runs the gRPC services over an in-memory listener on the books of newFundedRegistry (alice holds 10 BTC, bob
+10000 USDT) and database (may be nil), with "admin" as the only admin, and returns a connection to them.
+*/
+func serve(t *testing.T, database *gorm.DB) *grpc.ClientConn {
+	registry, _ := newFundedRegistry(t)
+	books := engine.New(registry, nil, 8, nil)
+	t.Cleanup(books.Close)
+	server.Attach(registry, books, database)
+	assert.NoError(t, server.SetAuth([]byte(testSecret), []string{"admin"}))
+
+	listener := bufconn.Listen(1 << 20)
+	s := server.NewGRPCServer()
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// NOTE: token signs claims the way user_auth does at login.
+func token(t *testing.T, secret string, claims jwt.MapClaims) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return signed
+}
+
+// NOTE: as is a call context carrying a valid token for username.
+func as(t *testing.T, username string) context.Context {
+	signed := token(t, testSecret, jwt.MapClaims{"username": username, "exp": time.Now().Add(time.Hour).Unix()})
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signed)
+}
+
+func withAuthorization(value string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", value)
+}
+
+func TestAuthNeedsASigningKey(t *testing.T) {
+	assert.ErrorIs(t, server.SetAuth(nil, nil), models.ErrNoJWTSecret)
+	assert.ErrorIs(t, server.SetAuth([]byte{}, []string{"admin"}), models.ErrNoJWTSecret)
+}
+
+// NOTE: Run on a local port with no database and an empty data dir, serving until its context is cancelled.
+func TestRunServesUntilCancelled(t *testing.T) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := free.Addr().String()
+	free.Close()
+	dir := t.TempDir()
+	t.Setenv("DB_HOST_ORDERBOOK", "")
+	t.Setenv("MARKETS_FILE_ORDERBOOK", "../config/markets.json")
+	t.Setenv("JOURNAL_FILE_ORDERBOOK", filepath.Join(dir, "orderbook.journal"))
+	t.Setenv("SNAPSHOT_DIR_ORDERBOOK", filepath.Join(dir, "snapshots"))
+	t.Setenv("LISTEN_ADDR_ORDERBOOK", addr)
+	t.Setenv("ADMINS_ORDERBOOK", "admin")
+	t.Setenv("JWT_SECRET", testSecret)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- server.Run(ctx) }()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	trading := pb.NewTradingServiceClient(conn)
+	call, stop := context.WithTimeout(as(t, "alice"), 5*time.Second)
+	defer stop()
+	reply, err := trading.ListOpenOrders(call, &pb.ListOpenOrdersRequest{}, grpc.WaitForReady(true))
+	assert.NoError(t, err)
+	assert.Empty(t, reply.GetOrders())
+	_, err = trading.ListOpenOrders(context.Background(), &pb.ListOpenOrdersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return after its context was cancelled")
+	}
+}
+
+func TestUnaryCallsNeedAValidToken(t *testing.T) {
+	trading := pb.NewTradingServiceClient(serve(t, nil))
+	hour := time.Hour
+	refused := map[string]context.Context{
+		"no token":       context.Background(),
+		"not a bearer":   withAuthorization("Basic YWxpY2U6cGFzcw=="),
+		"garbage":        withAuthorization("Bearer not-a-jwt"),
+		"other key":      withAuthorization("Bearer " + token(t, "another-key", jwt.MapClaims{"username": "alice", "exp": time.Now().Add(hour).Unix()})),
+		"expired":        withAuthorization("Bearer " + token(t, testSecret, jwt.MapClaims{"username": "alice", "exp": time.Now().Add(-hour).Unix()})),
+		"no username":    withAuthorization("Bearer " + token(t, testSecret, jwt.MapClaims{"exp": time.Now().Add(hour).Unix()})),
+		"empty username": withAuthorization("Bearer " + token(t, testSecret, jwt.MapClaims{"username": "", "exp": time.Now().Add(hour).Unix()})),
+	}
+	for name, ctx := range refused {
+		_, err := trading.ListOpenOrders(ctx, &pb.ListOpenOrdersRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), name)
+	}
+
+	_, err := trading.ListOpenOrders(as(t, "alice"), &pb.ListOpenOrdersRequest{})
+	assert.NoError(t, err)
+	_, err = trading.ListOpenOrders(as(t, "alice"), &pb.ListOpenOrdersRequest{OwnerUsername: "alice"})
+	assert.NoError(t, err)
+	_, err = trading.ListOpenOrders(as(t, "alice"), &pb.ListOpenOrdersRequest{OwnerUsername: "bob"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestOrdersBelongToTheCaller(t *testing.T) {
+	trading := pb.NewTradingServiceClient(serve(t, nil))
+	sell := &pb.PlaceOrderRequest{Symbol: "BTC-USDT", Side: pb.EnumSide_SELL, Price: "100", Quantity: "2"}
+
+	_, err := trading.PlaceOrder(as(t, "bob"), &pb.PlaceOrderRequest{Symbol: "BTC-USDT", Side: pb.EnumSide_SELL, Price: "100", Quantity: "2", OwnerUsername: "alice"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "bob can't place orders for alice")
+	placed, err := trading.PlaceOrder(as(t, "alice"), sell)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", placed.GetOrder().GetOwnerUsername())
+
+	_, err = trading.CancelOrder(as(t, "bob"), &pb.CancelOrderRequest{Id: placed.GetOrder().GetId()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "bob can't cancel alice's order")
+	_, err = trading.AmendOrder(as(t, "bob"), &pb.AmendOrderRequest{Id: placed.GetOrder().GetId(), Symbol: "BTC-USDT", Price: "105", Quantity: "2"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "bob can't amend alice's order")
+	cancelled, err := trading.CancelOrder(as(t, "alice"), &pb.CancelOrderRequest{Id: placed.GetOrder().GetId(), Symbol: "BTC-USDT"})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.OrderStatus_CANCELLED, cancelled.GetOrder().GetStatus())
+}
+
+func TestPlaceOrderTakesExpiresAtForGoodTillDateOnly(t *testing.T) {
+	trading := pb.NewTradingServiceClient(serve(t, nil))
+	expires_at := uint32(time.Now().Add(time.Hour).Unix())
+	for _, tif := range []pb.TimeInForce{pb.TimeInForce_GTC, pb.TimeInForce_IOC, pb.TimeInForce_FOK, pb.TimeInForce_DAY} {
+		_, err := trading.PlaceOrder(as(t, "alice"), &pb.PlaceOrderRequest{Symbol: "BTC-USDT", Side: pb.EnumSide_SELL, Price: "100", Quantity: "2", TimeInForce: tif, ExpiresAt: expires_at})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), tif.String())
+	}
+
+	placed, err := trading.PlaceOrder(as(t, "alice"), &pb.PlaceOrderRequest{Symbol: "BTC-USDT", Side: pb.EnumSide_SELL, Price: "100", Quantity: "2", TimeInForce: pb.TimeInForce_GTD, ExpiresAt: expires_at})
+	assert.NoError(t, err)
+	assert.Equal(t, expires_at, placed.GetOrder().GetExpiresAt())
+}
+
+func TestAdminCallsNeedAnAdmin(t *testing.T) {
+	transfers := pb.NewTransferServiceClient(serve(t, nil))
+
+	_, err := transfers.GetPendingApprovals(context.Background(), &pb.PendingApprovalsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = transfers.GetPendingApprovals(as(t, "alice"), &pb.PendingApprovalsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = transfers.RejectWithdrawal(as(t, "alice"), &pb.ReviewWithdrawalRequest{TransferId: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = transfers.GetPendingApprovals(as(t, "admin"), &pb.PendingApprovalsRequest{})
+	assert.NoError(t, err)
+}
+
+func TestStreamCallsNeedAValidToken(t *testing.T) {
+	executions := pb.NewExecutionServiceClient(serve(t, nil))
+	subscribe := func(ctx context.Context, req *pb.ExecutionsRequest) error {
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		stream, err := executions.SubscribeExecutions(ctx, req)
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(subscribe(context.Background(), &pb.ExecutionsRequest{})))
+	assert.Equal(t, codes.Unauthenticated, status.Code(subscribe(withAuthorization("Bearer not-a-jwt"), &pb.ExecutionsRequest{})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(subscribe(as(t, "alice"), &pb.ExecutionsRequest{OwnerUsername: "bob"})))
+	// NOTE: an accepted subscription has nothing to report yet, it's still open when the deadline comes
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(subscribe(as(t, "alice"), &pb.ExecutionsRequest{})))
+}
+
+func TestGetOrderInfoStatusCodes(t *testing.T) {
+	conn := serve(t, nil)
+	trading, info := pb.NewTradingServiceClient(conn), pb.NewOrderInfoServiceClient(conn)
+	placed, err := trading.PlaceOrder(as(t, "alice"), &pb.PlaceOrderRequest{Symbol: "BTC-USDT", Side: pb.EnumSide_SELL, Price: "100", Quantity: "2"})
+	assert.NoError(t, err)
+	id := placed.GetOrder().GetId()
+
+	_, err = info.GetOrderInfo(context.Background(), &pb.OrderInfoRequest{Id: id})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = info.GetOrderInfo(as(t, "bob"), &pb.OrderInfoRequest{Id: id})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{Id: id + 1})
+	assert.Equal(t, codes.NotFound, status.Code(err), "not in the books and no database")
+
+	reply, err := info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC-USDT", reply.GetOrder().GetSymbol())
+	assert.Equal(t, "100", reply.GetOrder().GetPrice())
+	assert.Equal(t, "2", reply.GetOrder().GetQuantity())
+	_, err = time.Parse(time.RFC3339, reply.GetOrder().GetCreatedAt())
+	assert.NoError(t, err, "created_at is ISO8601")
+}
+
+func TestGetOrderInfoReadsClosedOrdersFromTheDatabase(t *testing.T) {
+	created := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	stored := &ordersTable{rows: map[int64][]driver.Value{
+		42: {int64(42), created, created.Add(time.Minute), "BTC-USDT", int64(models.Sell), "100", "2", "alice", int64(models.StatusFilled)},
+	}, failing: 13}
+	database, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(stored)}), &gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+	info := pb.NewOrderInfoServiceClient(serve(t, database))
+
+	reply, err := info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{Id: 42})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), reply.GetOrder().GetId())
+	assert.Equal(t, pb.OrderStatus_FILLED, reply.GetOrder().GetStatus())
+	assert.Equal(t, "2026-03-14T15:09:26Z", reply.GetOrder().GetCreatedAt())
+	assert.Equal(t, "2026-03-14T15:10:26Z", reply.GetOrder().GetUpdatedAt())
+	_, err = info.GetOrderInfo(as(t, "bob"), &pb.OrderInfoRequest{Id: 42})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{Id: 43})
+	assert.Equal(t, codes.NotFound, status.Code(err), "gorm.ErrRecordNotFound is told apart from a failure")
+	_, err = info.GetOrderInfo(as(t, "alice"), &pb.OrderInfoRequest{Id: 13})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotEmpty(t, stored.queries())
+	for _, query := range stored.queries() {
+		assert.Contains(t, query, "WHERE id = $1")
+	}
+}
+
+/*
+ordersTable is a database/sql driver that serves an orders table held in memory to gorm's postgres dialect. It only
+answers lookups by "id = $1", anything else is an error, and looking up the failing ID fails like a lost connection.
+*/
+type ordersTable struct {
+	mu      sync.Mutex
+	rows    map[int64][]driver.Value
+	failing int64
+	seen    []string
+}
+
+var ordersColumns = []string{"id", "created_at", "updated_at", "symbol", "side", "price", "quantity", "owner_username", "status"}
+
+func (table *ordersTable) Connect(context.Context) (driver.Conn, error) { return table, nil }
+func (table *ordersTable) Driver() driver.Driver                        { return nil }
+func (table *ordersTable) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("unexpected prepare of %q", query)
+}
+func (table *ordersTable) Close() error              { return nil }
+func (table *ordersTable) Begin() (driver.Tx, error) { return nil, fmt.Errorf("no transactions") }
+
+func (table *ordersTable) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	table.seen = append(table.seen, query)
+	if !strings.Contains(query, "WHERE id = $1") || len(args) == 0 {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	id, ok := args[0].Value.(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected id %v", args[0].Value)
+	}
+	if id == table.failing {
+		return nil, driver.ErrBadConn
+	}
+	rows := &tableRows{}
+	if row, ok := table.rows[id]; ok {
+		rows.values = append(rows.values, row)
+	}
+	return rows, nil
+}
+
+func (table *ordersTable) queries() []string {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	return append([]string(nil), table.seen...)
+}
+
+type tableRows struct {
+	values [][]driver.Value
+}
+
+func (rows *tableRows) Columns() []string { return ordersColumns }
+func (rows *tableRows) Close() error      { return nil }
+func (rows *tableRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, rows.values[0])
+	rows.values = rows.values[1:]
+	return nil
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/ParsaAminpour/GoCoin/orderbook/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func stateJSON(t *testing.T, registry *models.MarketRegistry) string {
+	data, err := json.Marshal(registry.State())
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestSnapshotThenJournalTail(t *testing.T) {
+	dir := t.TempDir()
+	journal_path := filepath.Join(dir, "orderbook.journal")
+	snapshot_dir := filepath.Join(dir, "snapshots")
+	live := writeTestJournal(t, journal_path)
+
+	// an iceberg and a stop make sure hidden quantity and the trigger book survive the snapshot
+	wal, err := journal.Open(journal_path)
+	assert.NoError(t, err)
+	at := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	iceberg := &models.Order{Symbol: "BTC-USDT", Side: models.Sell, Price: models.MustParseDecimal("64300"), Quantity: models.MustParseDecimal("0.01"), DisplayQuantity: models.MustParseDecimal("0.002"), OwnerUsername: "dave"}
+	stop := &models.Order{Symbol: "BTC-USDT", Type: models.StopMarketOrder, Side: models.Buy, StopPrice: models.MustParseDecimal("64290"), Quantity: models.MustParseDecimal("0.001"), OwnerUsername: "erin"}
+	commitTo(t, wal, live, at, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: iceberg})
+	commitTo(t, wal, live, at, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: stop})
+	commitTo(t, wal, live, at, journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: &models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: models.MustParseDecimal("64300"), Quantity: models.MustParseDecimal("0.001"), OwnerUsername: "bob"}})
+	_, err = snapshot.Write(snapshot_dir, wal.LSN(), live.State())
+	assert.NoError(t, err)
+
+	// the tail: commands after the snapshot
+	taker := &models.Order{Symbol: "BTC-USDT", Type: models.MarketOrder, Side: models.Buy, Quantity: models.MustParseDecimal("0.003"), OwnerUsername: "bob"}
+	commitTo(t, wal, live, at.Add(time.Minute), journal.Record{Kind: journal.NewOrder, Symbol: "BTC-USDT", Order: taker})
+	commitTo(t, wal, live, at.Add(time.Minute), journal.Record{Kind: journal.NewOrder, Symbol: "ETH-USDT", Order: &models.Order{Symbol: "ETH-USDT", Side: models.Buy, Price: models.MustParseDecimal("3000"), Quantity: models.MustParseDecimal("0.5"), OwnerUsername: "carol"}})
+	assert.NoError(t, wal.Close())
+
+	snap, ok, skipped, err := snapshot.Latest(snapshot_dir)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, skipped)
+	assert.Equal(t, uint64(10), snap.LSN)
+
+	recovered := loadConfigMarkets(t)
+	assert.NoError(t, recovered.Restore(snap.State))
+	lsn, err := journal.ReplayAfter(journal_path, recovered, snap.LSN)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), lsn)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, recovered))
+
+	full := loadConfigMarkets(t)
+	_, err = journal.Replay(journal_path, full)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, full), "snapshot + tail and a full replay agree")
+}
+
+func TestCorruptedSnapshotIsSkipped(t *testing.T) {
+	dir := t.TempDir()
+	registry := newTestRegistry(t)
+	_, err := registry.AddOrder(&models.Order{Symbol: "BTC-USDT", Side: models.Buy, Price: dec(100), Quantity: dec(2), OwnerUsername: "alice"})
+	assert.NoError(t, err)
+	_, err = snapshot.Write(dir, 1, registry.State())
+	assert.NoError(t, err)
+	newer, err := snapshot.Write(dir, 2, registry.State())
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(newer)
+	assert.NoError(t, err)
+	data[len(data)-10] ^= 0x01
+	assert.NoError(t, os.WriteFile(newer, data, 0o644))
+
+	snap, ok, skipped, err := snapshot.Latest(dir)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), snap.LSN)
+	assert.Len(t, skipped, 1)
+	assert.ErrorIs(t, skipped[0], snapshot.ErrInvalid)
+
+	restored := newTestRegistry(t)
+	assert.NoError(t, restored.Restore(snap.State))
+	depth, _ := restored.Depth("BTC-USDT", models.Buy, 0)
+	assert.Equal(t, []models.DepthLevel{{Price: dec(100), Quantity: dec(2), OrderCount: 1}}, depth)
+	assert.Error(t, restored.Restore(snap.State), "only empty books can be restored")
+
+	_, err = snapshot.Write(dir, 3, registry.State())
+	assert.NoError(t, err)
+	assert.NoError(t, snapshot.Prune(dir, 1))
+	snap, _, _, _ = snapshot.Latest(dir)
+	assert.Equal(t, uint64(3), snap.LSN)
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParsaAminpour/GoCoin/orderbook/custody"
+	"github.com/ParsaAminpour/GoCoin/orderbook/engine"
+	"github.com/ParsaAminpour/GoCoin/orderbook/journal"
+	"github.com/ParsaAminpour/GoCoin/orderbook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// NOTE: BTC in config/markets.json: 10 a day per user, withdrawals above 1 wait for an admin.
+func TestTransfersMoveFundsInAndOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live, balances := loadConfigMarkets(t), models.NewBalances()
+	live.SetBalances(balances)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+	vault := custody.NewFake(nil)
+	submit := func(rec journal.Record) (models.Transfer, error) {
+		result, err := books.Submit(ctx, rec)
+		if result.Transfer == nil {
+			return models.Transfer{}, err
+		}
+		return *result.Transfer, err
+	}
+
+	deposit, err := submit(journal.Record{Kind: journal.Deposit, Owner: "alice", Asset: "BTC", Amount: models.MustParseDecimal("12")})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferPending, deposit.Status)
+	assert.True(t, balanceOf(balances, "alice", "BTC")[0].IsZero(), "nothing is credited before custody settles it")
+	tx, err := vault.Receive(ctx, deposit)
+	assert.NoError(t, err)
+	_, err = submit(journal.Record{Kind: journal.CompleteTransfer, TransferID: deposit.ID, TxID: tx})
+	assert.ErrorIs(t, err, models.ErrTransferStatus, "a deposit is confirmed before it completes")
+	_, err = submit(journal.Record{Kind: journal.ConfirmTransfer, TransferID: deposit.ID})
+	assert.NoError(t, err)
+	deposit, err = submit(journal.Record{Kind: journal.CompleteTransfer, TransferID: deposit.ID, TxID: tx})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferCompleted, deposit.Status)
+	assert.Equal(t, [2]models.Decimal{dec(12), models.Zero}, balanceOf(balances, "alice", "BTC"))
+
+	// NOTE: a small withdrawal is confirmed straight away and holds its funds until custody sends it
+	small, err := submit(journal.Record{Kind: journal.Withdraw, Owner: "alice", Asset: "BTC", Amount: models.MustParseDecimal("0.5"), Address: "bc1alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferConfirmed, small.Status)
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("11.5"), models.MustParseDecimal("0.5")}, balanceOf(balances, "alice", "BTC"))
+	tx, err = vault.Send(ctx, small)
+	assert.NoError(t, err)
+	_, err = submit(journal.Record{Kind: journal.CompleteTransfer, TransferID: small.ID, TxID: tx})
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("11.5"), models.Zero}, balanceOf(balances, "alice", "BTC"))
+	assert.Equal(t, models.MustParseDecimal("11.5"), vault.Wallet("BTC"))
+
+	// NOTE: a large one waits for an admin, a rejection gives the funds back and doesn't count towards the limit
+	large, err := submit(journal.Record{Kind: journal.Withdraw, Owner: "alice", Asset: "BTC", Amount: dec(3), Address: "bc1alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferPending, large.Status)
+	assert.True(t, large.NeedsApproval)
+	_, err = submit(journal.Record{Kind: journal.ConfirmTransfer, TransferID: large.ID})
+	assert.Error(t, err, "no admin")
+	large, err = submit(journal.Record{Kind: journal.ConfirmTransfer, TransferID: large.ID, Owner: "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", large.ApprovedBy)
+	_, err = custody.NewFake(nil).Send(ctx, large)
+	assert.ErrorIs(t, err, custody.ErrWalletShort)
+	large, err = submit(journal.Record{Kind: journal.RejectTransfer, TransferID: large.ID, Reason: err.Error()})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferRejected, large.Status)
+	assert.Equal(t, [2]models.Decimal{models.MustParseDecimal("11.5"), models.Zero}, balanceOf(balances, "alice", "BTC"))
+
+	_, err = submit(journal.Record{Kind: journal.Withdraw, Owner: "alice", Asset: "BTC", Amount: models.MustParseDecimal("9.6"), Address: "bc1alice"})
+	assert.ErrorIs(t, err, models.ErrWithdrawalLimit, "0.5 + 9.6 is over 10 a day")
+	_, err = submit(journal.Record{Kind: journal.Withdraw, Owner: "bob", Asset: "BTC", Amount: dec(1), Address: "bc1bob"})
+	assert.ErrorIs(t, err, models.ErrInsufficientFunds)
+	_, err = submit(journal.Record{Kind: journal.Withdraw, Owner: "alice", Asset: "BTC", Amount: models.MustParseDecimal("9.5"), Address: "bc1alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, [2]models.Decimal{dec(2), models.MustParseDecimal("9.5")}, balanceOf(balances, "alice", "BTC"))
+	assert.Len(t, balances.Transfers().List("alice", models.TransferPending), 1, "9.5 waits for an admin")
+
+	held, err := balances.Ledger().Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"BTC": models.MustParseDecimal("11.5")}, held)
+	books.Close()
+
+	replayed := loadConfigMarkets(t)
+	replayed.SetBalances(models.NewBalances())
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+}
+
+// NOTE: ETH has 18 decimals, amounts above 9.22 ETH didn't fit the old 64-bit mantissa
+func TestEighteenDecimalDeposits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orderbook.journal")
+	wal, err := journal.Open(path)
+	assert.NoError(t, err)
+	defer wal.Close()
+	live, balances := loadConfigMarkets(t), models.NewBalances()
+	live.SetBalances(balances)
+	books := engine.New(live, wal, 8, nil)
+	ctx := context.Background()
+	vault := custody.NewFake(nil)
+
+	for _, amount := range []string{"9.5", "0.000000000000000001", "15.25"} {
+		requested, err := books.Submit(ctx, journal.Record{Kind: journal.Deposit, Owner: "alice", Asset: "ETH", Amount: models.MustParseDecimal(amount)})
+		assert.NoError(t, err)
+		tx, err := vault.Receive(ctx, *requested.Transfer)
+		assert.NoError(t, err)
+		_, err = books.Submit(ctx, journal.Record{Kind: journal.ConfirmTransfer, TransferID: requested.Transfer.ID})
+		assert.NoError(t, err)
+		_, err = books.Submit(ctx, journal.Record{Kind: journal.CompleteTransfer, TransferID: requested.Transfer.ID, TxID: tx})
+		assert.NoError(t, err)
+	}
+	total := models.MustParseDecimal("24.750000000000000001")
+	assert.Equal(t, [2]models.Decimal{total, models.Zero}, balanceOf(balances, "alice", "ETH"))
+	assert.Equal(t, total, vault.Wallet("ETH"))
+	held, err := balances.Ledger().Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Decimal{"ETH": total}, held)
+
+	// NOTE: a total past 18 whole digits is refused, not a panic
+	huge := models.MustParseDecimal("999999999999999999")
+	assert.ErrorIs(t, balances.Deposit("bob", "ETH", huge, "test"), models.ErrDecimalOverflow)
+	_, err = vault.Receive(ctx, models.Transfer{ID: 99, Asset: "ETH", Amount: huge})
+	assert.ErrorIs(t, err, models.ErrDecimalOverflow)
+	assert.Equal(t, total, vault.Wallet("ETH"))
+	books.Close()
+
+	replayed := loadConfigMarkets(t)
+	replayed.SetBalances(models.NewBalances())
+	_, err = journal.Replay(path, replayed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, stateJSON(t, live), stateJSON(t, replayed))
+}
+
+func TestWithdrawalApprovalNeedsASecondAdmin(t *testing.T) {
+	registry, balances := loadConfigMarkets(t), models.NewBalances()
+	registry.SetBalances(balances)
+	assert.NoError(t, balances.Deposit("alice", "BTC", dec(5), "test"))
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	registry.SetApprovers([]string{"admin", "alice"})
+
+	withdrawal, err := registry.RequestWithdrawal(1, "alice", "BTC", dec(3), "bc1alice", at)
+	assert.NoError(t, err)
+	assert.True(t, withdrawal.NeedsApproval)
+	for by, why := range map[string]string{"": "nobody", "alice": "an admin can't approve their own", "bob": "not an admin"} {
+		_, err := registry.ConfirmTransfer(withdrawal.ID, by, at)
+		assert.ErrorIs(t, err, models.ErrNotApprover, why)
+	}
+	pending, _ := balances.Transfers().Get(withdrawal.ID)
+	assert.Equal(t, models.TransferPending, pending.Status)
+
+	confirmed, err := registry.ConfirmTransfer(withdrawal.ID, "admin", at)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferConfirmed, confirmed.Status)
+	assert.Equal(t, "admin", confirmed.ApprovedBy)
+}
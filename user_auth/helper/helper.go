@@ -116,7 +116,10 @@ func _generateJWT(username string, exp_time uint) (string, error) {
 		"username": username,
 		"exp":      exp_time,
 	}
-	jwtSecret := []byte("SECRET")
+	jwtSecret, err := models.JWTSecret()
+	if err != nil {
+		return "", err
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
@@ -146,7 +146,10 @@ func _generateJWT(username string, exp_time uint) (string, error) {
 		"username": username,
 		"exp":      exp_time,
 	}
-	jwtSecret := []byte("SECRET")
+	jwtSecret, err := models.JWTSecret()
+	if err != nil {
+		return "", err
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
@@ -160,6 +163,10 @@ func main() {
 	my_db := getDB()
 	fmt.Println("DB initialized:", my_db)
 	fmt.Println("err: ", db.Error)
+	jwtSecret, err := models.JWTSecret()
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
 
 	e := echo.New()
 
@@ -175,7 +182,7 @@ func main() {
 	e.POST("/auth/logout", func(c echo.Context) error { return nil })
 	e.POST("/auth/resert-password", withHandlerFunc(helper.ResetPassword))
 
-	e.Use(echojwt.JWT([]byte("secret")))
+	e.Use(echojwt.JWT(jwtSecret))
 	e.Use(middleware.Recover())
 	e.Use(middleware.Logger())
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
@@ -38,6 +38,18 @@ func (conf *Config) ExtractDbConfig() (Config, error) {
 	return db_conf, nil
 }
 
+var ErrNoJWTSecret = errors.New("JWT_SECRET is not set")
+
+// NOTE: JWTSecret is the HS256 key tokens are signed with, shared with the orderbook service through JWT_SECRET.
+// There's no default key, tokens signed with one anybody can read would be worthless.
+func JWTSecret() ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, ErrNoJWTSecret
+	}
+	return []byte(secret), nil
+}
+
 type Database struct {
 	DB *gorm.DB
 }